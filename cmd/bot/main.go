@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	_ "time/tzdata" // user time zones must not depend on the host tz database
 
 	"github.com/krezefal/eng-tg-bot/internal/app"
 	"github.com/krezefal/eng-tg-bot/pkg/log"
@@ -26,7 +27,7 @@ func main() {
 	}
 
 	// TODO: context cancellation?
-	app.Start()
+	app.Start(ctx)
 
 	zerolog.Info().Msg("shutdown app")
 }
@@ -11,9 +11,10 @@ import (
 	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/subosito/gotenv"
 
+	"github.com/krezefal/eng-tg-bot/internal/domain"
 	"github.com/krezefal/eng-tg-bot/pkg/log"
 )
 
@@ -35,13 +36,19 @@ type seedDictionary struct {
 	Description string `json:"description"`
 	Mode        string `json:"mode"`
 	Author      string `json:"author"`
+	// WordOrder is one of domain.WordOrder, random by default. The author
+	// order is the order of words in the file.
+	WordOrder string `json:"word_order"`
 }
 
 type seedWord struct {
-	Spelling      string `json:"spelling"`
-	Transcription string `json:"transcription"`
-	AudioLink     string `json:"audio"`
-	RUTranslation string `json:"ru_translation"`
+	Spelling      string   `json:"spelling"`
+	Transcription string   `json:"transcription"`
+	AudioLink     string   `json:"audio"`
+	RUTranslation string   `json:"ru_translation"`
+	Examples      []string `json:"examples"`
+	FrequencyRank *int     `json:"frequency_rank"`
+	Topic         string   `json:"topic"`
 }
 
 type seedData struct {
@@ -115,10 +122,15 @@ func run(help, up, down bool, filePath string) error {
 	defer cancel()
 
 	if up {
-		if err = seedUp(ctx, db, seed); err != nil {
-			return err
+		added, notified, seedErr := seedUp(ctx, db, seed)
+		if seedErr != nil {
+			return seedErr
 		}
-		logger.Info().Str("dictionary", seed.Dictionary.Title).Msg("seed applied")
+		logger.Info().
+			Str("dictionary", seed.Dictionary.Title).
+			Int("words_added", added).
+			Int("subscribers_notified", notified).
+			Msg("seed applied")
 
 		return nil
 	}
@@ -164,6 +176,9 @@ func validateSeed(seed *seedData) error {
 	if strings.TrimSpace(dict.Author) == "" {
 		return errors.New("dictionary.author is required")
 	}
+	if _, ok := domain.ParseWordOrder(wordOrder(dict)); !ok {
+		return fmt.Errorf("dictionary.word_order %q is not supported", dict.WordOrder)
+	}
 	if len(seed.Words) == 0 {
 		return errors.New("words must not be empty")
 	}
@@ -175,15 +190,22 @@ func validateSeed(seed *seedData) error {
 		if strings.TrimSpace(w.RUTranslation) == "" {
 			return fmt.Errorf("words[%d].ru_translation is required", i)
 		}
+		if w.FrequencyRank != nil && *w.FrequencyRank <= 0 {
+			return fmt.Errorf("words[%d].frequency_rank must be positive", i)
+		}
 	}
 
 	return nil
 }
 
-func seedUp(ctx context.Context, db *sql.DB, seed *seedData) error {
+// seedUp upserts the dictionary and its words. If some words are new, the
+// subscribers are told about them through the notifications outbox, in the
+// same transaction. It returns how many words were new and how many
+// subscribers were notified.
+func seedUp(ctx context.Context, db *sql.DB, seed *seedData) (int, int, error) {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return 0, 0, fmt.Errorf("begin tx: %w", err)
 	}
 	defer func() {
 		_ = tx.Rollback()
@@ -191,7 +213,7 @@ func seedUp(ctx context.Context, db *sql.DB, seed *seedData) error {
 
 	dictID, err := ensureDictionary(ctx, tx, seed.Dictionary)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
 	const upsertWordQuery = `
@@ -201,18 +223,35 @@ func seedUp(ctx context.Context, db *sql.DB, seed *seedData) error {
 			spelling,
 			transcription,
 			audio,
-			ru_translation
+			ru_translation,
+			examples,
+			position,
+			frequency_rank,
+			topic,
+			topic_position
 		)
-		VALUES ($1, NULL, $2, $3, $4, $5)
+		VALUES ($1, NULL, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (dictionary_id, spelling) DO UPDATE
 		SET transcription = EXCLUDED.transcription,
 			audio = EXCLUDED.audio,
 			ru_translation = EXCLUDED.ru_translation,
-			batch_id = NULL;
+			examples = EXCLUDED.examples,
+			position = EXCLUDED.position,
+			frequency_rank = EXCLUDED.frequency_rank,
+			topic = EXCLUDED.topic,
+			topic_position = EXCLUDED.topic_position,
+			batch_id = NULL
+		RETURNING (xmax = 0) AS inserted;
 	`
 
-	for _, w := range seed.Words {
-		_, err = tx.ExecContext(
+	added := 0
+	topicPositions := make(map[string]int)
+	for i, w := range seed.Words {
+		topic := strings.TrimSpace(w.Topic)
+		topicPositions[topic]++
+
+		var inserted bool
+		err = tx.QueryRowContext(
 			ctx,
 			upsertWordQuery,
 			dictID,
@@ -220,17 +259,69 @@ func seedUp(ctx context.Context, db *sql.DB, seed *seedData) error {
 			strings.TrimSpace(w.Transcription),
 			strings.TrimSpace(w.AudioLink),
 			strings.TrimSpace(w.RUTranslation),
-		)
+			pq.Array(trimExamples(w.Examples)),
+			i+1,
+			w.FrequencyRank,
+			topic,
+			topicPositions[topic],
+		).Scan(&inserted)
 		if err != nil {
-			return fmt.Errorf("upsert word %q: %w", w.Spelling, err)
+			return 0, 0, fmt.Errorf("upsert word %q: %w", w.Spelling, err)
+		}
+		if inserted {
+			added++
+		}
+	}
+
+	notified := 0
+	if added > 0 {
+		if notified, err = notifySubscribers(ctx, tx, dictID, added); err != nil {
+			return 0, 0, err
 		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+		return 0, 0, fmt.Errorf("commit tx: %w", err)
 	}
 
-	return nil
+	return added, notified, nil
+}
+
+// notifySubscribers enqueues a notification about the words added to the
+// dictionary for every subscriber who has notifications on; the bot sends
+// them.
+func notifySubscribers(ctx context.Context, tx *sql.Tx, dictID string, words int) (int, error) {
+	const query = `
+		INSERT INTO notifications (user_id, kind, dictionary_id, words)
+		SELECT ud.user_id, 'new_words', ud.dictionary_id, $2
+		FROM user_dictionaries ud
+		JOIN users u ON u.tg_id = ud.user_id
+		WHERE ud.dictionary_id = $1
+			AND u.notifications_enabled;
+	`
+
+	res, err := tx.ExecContext(ctx, query, dictID, words)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue notifications: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+func trimExamples(examples []string) []string {
+	trimmed := make([]string, 0, len(examples))
+	for _, e := range examples {
+		if e = strings.TrimSpace(e); e != "" {
+			trimmed = append(trimmed, e)
+		}
+	}
+
+	return trimmed
 }
 
 func ensureDictionary(ctx context.Context, tx *sql.Tx, dict seedDictionary) (string, error) {
@@ -251,6 +342,16 @@ func ensureDictionary(ctx context.Context, tx *sql.Tx, dict seedDictionary) (str
 	).Scan(&dictID)
 
 	if err == nil {
+		const updateQuery = `
+			UPDATE dictionaries
+			SET word_order = $2
+			WHERE id = $1;
+		`
+
+		if _, err = tx.ExecContext(ctx, updateQuery, dictID, wordOrder(dict)); err != nil {
+			return "", fmt.Errorf("update dictionary word order: %w", err)
+		}
+
 		return dictID, nil
 	}
 
@@ -259,8 +360,8 @@ func ensureDictionary(ctx context.Context, tx *sql.Tx, dict seedDictionary) (str
 	}
 
 	const insertQuery = `
-		INSERT INTO dictionaries (title, description, mode, author)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO dictionaries (title, description, mode, author, word_order)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id;
 	`
 
@@ -271,6 +372,7 @@ func ensureDictionary(ctx context.Context, tx *sql.Tx, dict seedDictionary) (str
 		strings.TrimSpace(dict.Description),
 		strings.TrimSpace(dict.Mode),
 		strings.TrimSpace(dict.Author),
+		wordOrder(dict),
 	).Scan(&dictID)
 	if err != nil {
 		return "", fmt.Errorf("insert dictionary: %w", err)
@@ -279,6 +381,14 @@ func ensureDictionary(ctx context.Context, tx *sql.Tx, dict seedDictionary) (str
 	return dictID, nil
 }
 
+func wordOrder(dict seedDictionary) string {
+	if order := strings.TrimSpace(dict.WordOrder); order != "" {
+		return order
+	}
+
+	return string(domain.WordOrderRandom)
+}
+
 func seedDown(ctx context.Context, db *sql.DB, mode, author string) (int64, error) {
 	const query = `
 		DELETE FROM dictionaries
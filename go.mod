@@ -3,6 +3,7 @@ module github.com/krezefal/eng-tg-bot
 go 1.25.4
 
 require (
+	github.com/golang-migrate/migrate/v4 v4.19.1
 	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/lib/pq v1.10.9
 	github.com/rs/zerolog v1.34.0
@@ -13,7 +14,6 @@ require (
 )
 
 require (
-	github.com/golang-migrate/migrate/v4 v4.19.1 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
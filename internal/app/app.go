@@ -3,23 +3,65 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"regexp"
+	"time"
 
 	"github.com/rs/zerolog"
 	tele "gopkg.in/telebot.v4"
 
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/repository/memory"
 	"github.com/krezefal/eng-tg-bot/internal/repository/postgres"
 	"github.com/krezefal/eng-tg-bot/internal/resources"
+	"github.com/krezefal/eng-tg-bot/internal/scheduler"
+	"github.com/krezefal/eng-tg-bot/internal/transport/httpserver"
 	"github.com/krezefal/eng-tg-bot/internal/transport/telegram"
 	"github.com/krezefal/eng-tg-bot/internal/usecase/catalog"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/digest"
 	"github.com/krezefal/eng-tg-bot/internal/usecase/learning"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/notification"
 	"github.com/krezefal/eng-tg-bot/internal/usecase/onboarding"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/reminder"
 	"github.com/krezefal/eng-tg-bot/internal/usecase/review"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/settings"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/stats"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/streak"
 	"github.com/krezefal/eng-tg-bot/internal/usecase/subscription"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/words"
 )
 
+const (
+	// cleanupInterval is how often expired sessions and processed updates
+	// are deleted.
+	cleanupInterval = time.Hour
+	// processedUpdatesTTL is how long processed updates are remembered;
+	// Telegram doesn't deliver an update older than a day.
+	processedUpdatesTTL = 24 * time.Hour
+)
+
+// webhookSecretPattern is what Telegram allows as the secret token of a
+// webhook.
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// sessionStore keeps review and learning sessions between updates.
+type sessionStore interface {
+	LoadSession(ctx context.Context, userID int64, kind domain.SessionKind, dst any) (bool, error)
+	SaveSession(ctx context.Context, userID int64, kind domain.SessionKind, session any) error
+	DeleteSession(ctx context.Context, userID int64, kind domain.SessionKind) error
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int, error)
+}
+
+// txManager runs a function in a transaction.
+type txManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type App struct {
-	logger *zerolog.Logger
-	tgSrv  *telegram.Server
+	logger    *zerolog.Logger
+	tgSrv     *telegram.Server
+	httpSrv   *httpserver.Server
+	scheduler *scheduler.Scheduler
 }
 
 func New(ctx context.Context, logger *zerolog.Logger) (*App, error) {
@@ -27,9 +69,21 @@ func New(ctx context.Context, logger *zerolog.Logger) (*App, error) {
 
 	logger.Info().Msg("initializing application...")
 
+	var poller tele.Poller = &tele.LongPoller{Timeout: resources.Env.Timeout}
+	webhook, webhookPath, err := newWebhook(resources.Env)
+	if err != nil {
+		return nil, err
+	}
+	if webhook != nil {
+		poller = webhook
+	}
+
 	pref := tele.Settings{
 		Token:  resources.Env.Token,
-		Poller: &tele.LongPoller{Timeout: resources.Env.Timeout},
+		Poller: poller,
+		// The webhook handles every update in its own request, see
+		// telegram.Server.Process.
+		Synchronous: webhook != nil,
 	}
 
 	b, err := tele.NewBot(pref)
@@ -37,18 +91,59 @@ func New(ctx context.Context, logger *zerolog.Logger) (*App, error) {
 		return nil, fmt.Errorf("error init telebot: %w", err)
 	}
 
-	logger.Info().Msg("telebot instance initialized")
+	logger.Info().Str("mode", resources.Env.Mode).Msg("telebot instance initialized")
+
+	// Telegram doesn't serve getUpdates while a webhook is set, so the
+	// webhook is removed in polling mode; switching modes takes a restart
+	// only.
+	if webhook != nil {
+		if err = b.SetWebhook(webhook); err != nil {
+			return nil, fmt.Errorf("error set webhook: %w", err)
+		}
+		logger.Info().Str("path", webhookPath).Msg("webhook set")
+	} else {
+		if err = b.RemoveWebhook(); err != nil {
+			return nil, fmt.Errorf("error remove webhook: %w", err)
+		}
+	}
+
+	if resources.Env.HTTPListen == "" && webhook != nil {
+		return nil, fmt.Errorf("webhook mode needs HTTP_LISTEN")
+	}
 
 	userRepo := postgres.NewUserRepo(resources.Db, logger)
 	dictRepo := postgres.NewDictionaryRepo(resources.Db, logger)
 	subsRepo := postgres.NewSubscriptionsRepo(resources.Db, logger)
 	wordsStateRepo := postgres.NewWordsStateRepo(resources.Db, logger)
+	settingsRepo := postgres.NewSettingsRepo(resources.Db, logger)
+	streakRepo := postgres.NewStreakRepo(resources.Db, logger)
+	statsRepo := postgres.NewStatsRepo(resources.Db, logger)
+	digestRepo := postgres.NewDigestRepo(resources.Db, logger)
+	notificationRepo := postgres.NewNotificationRepo(resources.Db, logger)
+	locker := postgres.NewLocker(resources.LockDb, logger)
+	txManager := postgres.NewTxManager(resources.Db, logger)
+	updatesRepo := postgres.NewUpdatesRepo(resources.Db, logger)
+	jobRunsRepo := postgres.NewJobRunsRepo(resources.Db, logger)
+
+	sessions, sessionsTx, err := newSessionStore(resources, txManager, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	notifier := telegram.NewNotifier(b, logger)
 
 	onboardUC := onboarding.NewUsecase(userRepo, logger)
 	catalogUC := catalog.NewUsecase(dictRepo, subsRepo, logger)
-	subscUC := subscription.NewUsecase(userRepo, dictRepo, subsRepo, logger)
-	learningUC := learning.NewUsecase(userRepo, dictRepo, subsRepo, wordsStateRepo, logger)
-	reviewUC := review.NewUsecase(userRepo, dictRepo, subsRepo, wordsStateRepo, logger)
+	subscUC := subscription.NewUsecase(userRepo, dictRepo, subsRepo, settingsRepo, logger)
+	learningUC := learning.NewUsecase(userRepo, dictRepo, subsRepo, wordsStateRepo, settingsRepo, sessions, sessionsTx, logger)
+	reviewUC := review.NewUsecase(userRepo, dictRepo, subsRepo, wordsStateRepo, settingsRepo, sessions, sessionsTx, logger)
+	wordsUC := words.NewUsecase(wordsStateRepo, settingsRepo, logger)
+	reminderUC := reminder.NewUsecase(userRepo, notifier, logger)
+	settingsUC := settings.NewUsecase(settingsRepo, logger)
+	streakUC := streak.NewUsecase(settingsRepo, streakRepo, logger)
+	statsUC := stats.NewUsecase(subsRepo, settingsRepo, statsRepo, logger)
+	digestUC := digest.NewUsecase(digestRepo, settingsRepo, streakUC, notifier, logger)
+	notificationUC := notification.NewUsecase(notificationRepo, notifier, logger)
 
 	handlers := telegram.NewHandler(
 		onboardUC,
@@ -56,15 +151,41 @@ func New(ctx context.Context, logger *zerolog.Logger) (*App, error) {
 		subscUC,
 		learningUC,
 		reviewUC,
+		wordsUC,
+		reminderUC,
+		settingsUC,
+		streakUC,
+		statsUC,
+		digestUC,
 		logger,
 	)
 
-	tgSrv := telegram.NewServer(b, logger)
+	tgSrv := telegram.NewServer(b, locker, updatesRepo, logger)
 	tgSrv.InitRoutes(ctx, handlers)
 
+	var httpSrv *httpserver.Server
+	if resources.Env.HTTPListen != "" {
+		httpSrv = httpserver.New(httpserver.Config{
+			Listen:      resources.Env.HTTPListen,
+			WebhookPath: webhookPath,
+			SecretToken: resources.Env.WebhookSecret,
+			TLSCert:     resources.Env.WebhookTLSCert,
+			TLSKey:      resources.Env.WebhookTLSKey,
+		}, tgSrv, resources.Db, logger)
+	}
+
+	jobs := scheduler.New(locker, jobRunsRepo, logger)
+	jobs.Add(scheduler.Job{Name: "reminders", Interval: reminder.TickInterval, Run: reminderUC.Tick})
+	jobs.Add(scheduler.Job{Name: "digest", Interval: digest.TickInterval, Run: digestUC.Tick})
+	jobs.Add(scheduler.Job{Name: "notifications", Interval: notification.TickInterval, Run: notificationUC.Tick})
+	jobs.Add(scheduler.Job{Name: "sessions", Interval: cleanupInterval, Run: cleanupSessions(sessions, logger)})
+	jobs.Add(scheduler.Job{Name: "updates", Interval: cleanupInterval, Run: cleanupUpdates(updatesRepo, logger)})
+
 	app := &App{
-		logger: logger,
-		tgSrv:  tgSrv,
+		logger:    logger,
+		tgSrv:     tgSrv,
+		httpSrv:   httpSrv,
+		scheduler: jobs,
 	}
 
 	logger.Info().Msg("application initialized")
@@ -72,7 +193,113 @@ func New(ctx context.Context, logger *zerolog.Logger) (*App, error) {
 	return app, nil
 }
 
-func (a *App) Start() {
+// newWebhook builds the webhook poller for BOT_MODE=webhook and returns the
+// path it's served at; nil is returned in polling mode. The webhook is set
+// by New rather than by the poller, so that a failure stops the start.
+func newWebhook(env *resources.Env) (*tele.Webhook, string, error) {
+	switch env.Mode {
+	case "polling":
+		return nil, "", nil
+	case "webhook":
+	default:
+		return nil, "", fmt.Errorf("unknown bot mode %q", env.Mode)
+	}
+
+	public, err := url.Parse(env.WebhookURL)
+	if err != nil || public.Scheme != "https" || public.Host == "" {
+		return nil, "", fmt.Errorf("WEBHOOK_URL must be an https URL, got %q", env.WebhookURL)
+	}
+	if !webhookSecretPattern.MatchString(env.WebhookSecret) {
+		return nil, "", fmt.Errorf("WEBHOOK_SECRET must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
+	if (env.WebhookTLSCert == "") != (env.WebhookTLSKey == "") {
+		return nil, "", fmt.Errorf("WEBHOOK_TLS_CERT and WEBHOOK_TLS_KEY must be set together")
+	}
+
+	endpoint := &tele.WebhookEndpoint{PublicURL: env.WebhookURL}
+	if env.WebhookSelfSigned {
+		if env.WebhookTLSCert == "" {
+			return nil, "", fmt.Errorf("WEBHOOK_SELF_SIGNED needs WEBHOOK_TLS_CERT")
+		}
+		endpoint.Cert = env.WebhookTLSCert
+	}
+
+	path := public.Path
+	if path == "" {
+		path = "/"
+	}
+
+	webhook := &tele.Webhook{
+		MaxConnections: env.WebhookMaxConnections,
+		SecretToken:    env.WebhookSecret,
+		// The updates are served by httpserver, the poller only waits for
+		// the bot to stop.
+		IgnoreSetWebhook: true,
+		Endpoint:         endpoint,
+	}
+
+	return webhook, path, nil
+}
+
+// newSessionStore picks the session store by the SESSION_STORE env: postgres
+// keeps sessions across restarts and bot instances, memory keeps them in the
+// process only.
+// newSessionStore returns the session store and the transaction manager its
+// writes are atomic with the database ones under.
+func newSessionStore(r *resources.Resources, tx txManager, logger *zerolog.Logger) (sessionStore, txManager, error) {
+	switch r.Env.SessionStore {
+	case "postgres":
+		return postgres.NewSessionStore(r.Db, logger), tx, nil
+	case "memory":
+		store := memory.NewSessionStore()
+		return store, store.WithTx(tx), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown session store %q", r.Env.SessionStore)
+	}
+}
+
+// cleanupSessions deletes the expired sessions, which are otherwise only
+// ignored when loaded.
+func cleanupSessions(sessions sessionStore, logger *zerolog.Logger) func(context.Context, time.Time) error {
+	return func(ctx context.Context, now time.Time) error {
+		deleted, err := sessions.DeleteExpiredSessions(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		if deleted > 0 {
+			logger.Info().Int("sessions", deleted).Msg("expired sessions deleted")
+		}
+
+		return nil
+	}
+}
+
+// cleanupUpdates forgets the processed updates older than
+// processedUpdatesTTL.
+func cleanupUpdates(updates *postgres.UpdatesRepo, logger *zerolog.Logger) func(context.Context, time.Time) error {
+	return func(ctx context.Context, now time.Time) error {
+		deleted, err := updates.DeleteProcessedUpdates(ctx, now.Add(-processedUpdatesTTL))
+		if err != nil {
+			return err
+		}
+
+		if deleted > 0 {
+			logger.Info().Int("updates", deleted).Msg("processed updates deleted")
+		}
+
+		return nil
+	}
+}
+
+// Start runs the background jobs, the HTTP server and the bot; the jobs and
+// the HTTP server stop with ctx.
+func (a *App) Start(ctx context.Context) {
+	go a.scheduler.Run(ctx)
+	if a.httpSrv != nil {
+		go a.httpSrv.Run(ctx)
+	}
+
 	a.logger.Info().Msg("starting bot")
 	a.tgSrv.Start()
 }
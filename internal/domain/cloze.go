@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"math/rand/v2"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const ClozeBlank = "_____"
+
+// Cloze is an example sentence with the reviewed word blanked out.
+type Cloze struct {
+	Text string
+	// Answer is the form of the word as used in the sentence, lowercased.
+	Answer string
+}
+
+// BuildCloze blanks out the word in one of its examples, trying them in random
+// order. Inflected forms are recognized (see WordForms). false is returned if
+// the word has no examples or is not found in any of them.
+func BuildCloze(word *ReviewWord) (*Cloze, bool) {
+	if len(word.Examples) == 0 {
+		return nil, false
+	}
+
+	forms := WordForms(word.Spelling)
+	start := rand.IntN(len(word.Examples))
+	for i := range word.Examples {
+		example := word.Examples[(start+i)%len(word.Examples)]
+		if cloze, ok := blankOut(example, forms); ok {
+			return cloze, true
+		}
+	}
+
+	return nil, false
+}
+
+// blankOut replaces every whole-word occurrence of the forms, the longest
+// form where several match at one place. A sentence using several forms, as
+// in "I run, you ran", is skipped, as one answer can't fill its blanks.
+func blankOut(sentence string, forms []string) (*Cloze, bool) {
+	runes := []rune(sentence)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var (
+		text   strings.Builder
+		answer string
+		last   int
+	)
+	for at := 0; at < len(lower); {
+		n := matchForm(lower, at, forms)
+		if n == 0 {
+			at++
+			continue
+		}
+
+		form := string(lower[at : at+n])
+		if answer != "" && form != answer {
+			return nil, false
+		}
+		answer = form
+
+		text.WriteString(string(runes[last:at]))
+		text.WriteString(ClozeBlank)
+		at += n
+		last = at
+	}
+	if answer == "" {
+		return nil, false
+	}
+	text.WriteString(string(runes[last:]))
+
+	return &Cloze{Text: text.String(), Answer: answer}, true
+}
+
+// matchForm returns the length of the longest form found as a whole word at
+// the position, or 0.
+func matchForm(lower []rune, at int, forms []string) int {
+	if !isWordBoundary(lower, at-1) {
+		return 0
+	}
+
+	best := 0
+	for _, form := range forms {
+		f := []rune(form)
+		if len(f) <= best || at+len(f) > len(lower) {
+			continue
+		}
+		if equalRunes(lower[at:at+len(f)], f) && isWordBoundary(lower, at+len(f)) {
+			best = len(f)
+		}
+	}
+
+	return best
+}
+
+func equalRunes(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isWordBoundary(runes []rune, i int) bool {
+	if i < 0 || i >= len(runes) {
+		return true
+	}
+
+	return !unicode.IsLetter(runes[i]) && runes[i] != '-'
+}
+
+// GradeClozeAnswer grades a typed cloze answer like GradeTypedAnswer against
+// the form used in the sentence. Another form of the same word ("arrive" for
+// "arrived") means the word is known but not its grammar, so it is graded
+// MaxGrade-1 with WrongForm set. It is counted correct only if that grade
+// passes under the scheduler: under sm2 the card starts over.
+func GradeClozeAnswer(
+	cloze *Cloze,
+	word *ReviewWord,
+	answer string,
+	elapsed time.Duration,
+	scheduler Scheduler,
+) *TypedAnswerResult {
+	res := GradeTypedAnswer(cloze.Answer, answer, elapsed, scheduler)
+	if res.Correct {
+		return res
+	}
+
+	normAnswer := normalizeAnswer(answer)
+	for _, form := range WordForms(word.Spelling) {
+		if form == cloze.Answer || normAnswer != form {
+			continue
+		}
+
+		res.WrongForm = true
+		res.Slow = elapsed > TypedAnswerFastLimit
+		res.Grade = MaxGrade - 1
+		if res.Slow {
+			res.Grade = slowGrade(res.Grade, scheduler)
+		}
+		res.Correct = res.Grade >= PassingGrade(scheduler)
+		break
+	}
+
+	return res
+}
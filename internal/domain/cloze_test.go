@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWordForms(t *testing.T) {
+	tests := []struct {
+		word    string
+		include []string
+	}{
+		{"walk", []string{"walk", "walks", "walked", "walking"}},
+		{"watch", []string{"watches"}},
+		{"potato", []string{"potatoes"}},
+		{"photo", []string{"photos"}},
+		{"study", []string{"studies", "studied"}},
+		{"play", []string{"plays", "played"}},
+		{"make", []string{"making", "made"}},
+		{"lie", []string{"lying"}},
+		{"see", []string{"seeing", "saw", "seen"}},
+		{"stop", []string{"stopped", "stopping"}},
+		{"happy", []string{"happier", "happiest"}},
+		{"big", []string{"bigger", "biggest"}},
+		{"child", []string{"children"}},
+		{"good", []string{"better", "best"}},
+		{"get up", []string{"get up", "gets up", "got up", "getting up"}},
+		{"  Walk ", []string{"walk", "walked"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			forms := WordForms(tt.word)
+			if len(forms) == 0 || forms[0] != strings.ToLower(strings.TrimSpace(tt.word)) {
+				t.Fatalf("WordForms(%q) = %v, want the word first", tt.word, forms)
+			}
+			for _, f := range tt.include {
+				if !slices.Contains(forms, f) {
+					t.Errorf("WordForms(%q) = %v, want %q among them", tt.word, forms, f)
+				}
+			}
+		})
+	}
+
+	if forms := WordForms(" "); forms != nil {
+		t.Errorf("WordForms of a blank = %v, want nil", forms)
+	}
+}
+
+func TestBuildCloze(t *testing.T) {
+	tests := []struct {
+		name     string
+		word     string
+		example  string
+		text     string
+		answer   string
+		notFound bool
+	}{
+		{"base form", "cat", "The cat sleeps.", "The _____ sleeps.", "cat", false},
+		{"capitalized", "apple", "Apples are sweet.", "_____ are sweet.", "apples", false},
+		{"irregular past", "go", "She went home.", "She _____ home.", "went", false},
+		{"longest form at the same place", "stop", "He stopped here.", "He _____ here.", "stopped", false},
+		{"every occurrence", "cat", "The cat saw a Cat.", "The _____ saw a _____.", "cat", false},
+		{"several forms", "run", "I run, you ran.", "", "", true},
+		{"phrase", "get up", "He got up early.", "He _____ early.", "got up", false},
+		{"whole words only", "cat", "A category of concatenation.", "", "", true},
+		{"hyphen is part of the word", "well", "A well-known fact.", "", "", true},
+		{"absent", "dog", "The cat sleeps.", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cloze, ok := BuildCloze(&ReviewWord{Spelling: tt.word, Examples: []string{tt.example}})
+			if tt.notFound {
+				if ok {
+					t.Fatalf("BuildCloze(%q, %q) = %+v, want none", tt.word, tt.example, cloze)
+				}
+				return
+			}
+			if !ok || cloze.Text != tt.text || cloze.Answer != tt.answer {
+				t.Fatalf("BuildCloze(%q, %q) = %+v, %v; want %q, %q", tt.word, tt.example, cloze, ok, tt.text, tt.answer)
+			}
+		})
+	}
+
+	if _, ok := BuildCloze(&ReviewWord{Spelling: "cat"}); ok {
+		t.Error("BuildCloze of a word without examples succeeded")
+	}
+}
+
+func TestGradeClozeAnswer(t *testing.T) {
+	const fast, slow = time.Second, TypedAnswerFastLimit + time.Second
+	word := &ReviewWord{Spelling: "arrive"}
+	cloze := &Cloze{Text: "They _____ late.", Answer: "arrived"}
+
+	tests := []struct {
+		name      string
+		answer    string
+		elapsed   time.Duration
+		scheduler Scheduler
+		grade     int
+		correct   bool
+		wrongForm bool
+	}{
+		{"exact form", "arrived", fast, SchedulerSM2, MaxGrade, true, false},
+		{"typo", "arived", fast, SchedulerSM2, MinGrade + 1, false, false},
+		{"other form sm2 doesn't pass", "arrive", fast, SchedulerSM2, MaxGrade - 1, false, true},
+		{"other form slow sm2", "arrives", slow, SchedulerSM2, MaxGrade - 1, false, true},
+		{"other form lenient passes", "arrive", fast, SchedulerSM2Lenient, MaxGrade - 1, true, true},
+		{"other form slow lenient stays passing", "arriving", slow, SchedulerSM2Lenient, MaxGrade - 1, true, true},
+		{"exact form slow lenient", "arrived", slow, SchedulerSM2Lenient, MaxGrade - 1, true, false},
+		{"wrong word", "leave", fast, SchedulerSM2, MinGrade, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := GradeClozeAnswer(cloze, word, tt.answer, tt.elapsed, tt.scheduler)
+			if res.Grade != tt.grade || res.Correct != tt.correct || res.WrongForm != tt.wrongForm {
+				t.Errorf("GradeClozeAnswer(%q) = grade %d, correct %v, wrong form %v; want %d, %v, %v",
+					tt.answer, res.Grade, res.Correct, res.WrongForm, tt.grade, tt.correct, tt.wrongForm)
+			}
+			// A correct answer is exactly one that passes.
+			if passes := res.Grade >= PassingGrade(tt.scheduler); res.Correct != passes {
+				t.Errorf("GradeClozeAnswer(%q) = grade %d, correct %v; grade passing is %v",
+					tt.answer, res.Grade, res.Correct, passes)
+			}
+		})
+	}
+}
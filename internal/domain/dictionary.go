@@ -10,12 +10,12 @@ const (
 	OnScheduleMode
 )
 
-func (m DictionaryMode) HumanReadable() string {
+func (m DictionaryMode) String() string {
 	switch m {
 	case RandomPoolMode:
-		return "Обычный словарь"
+		return "random_pool"
 	case OnScheduleMode:
-		return "Словарь-по-расписанию"
+		return "on_schedule"
 	default:
 		return "unknown"
 	}
@@ -32,6 +32,30 @@ func ParseDictionaryMode(raw string) (DictionaryMode, bool) {
 	}
 }
 
+// WordOrder is the order new words of a dictionary are picked for learning in.
+type WordOrder string
+
+const (
+	// WordOrderAuthor follows the position of words set by the author.
+	WordOrderAuthor WordOrder = "author"
+	// WordOrderFrequency picks the most frequent words first; words of
+	// unknown frequency go last, in author order.
+	WordOrderFrequency WordOrder = "frequency"
+	WordOrderRandom    WordOrder = "random"
+	// WordOrderTopic follows author order within every topic and takes the
+	// topics in turn.
+	WordOrderTopic WordOrder = "topic"
+)
+
+func ParseWordOrder(raw string) (WordOrder, bool) {
+	switch WordOrder(raw) {
+	case WordOrderAuthor, WordOrderFrequency, WordOrderRandom, WordOrderTopic:
+		return WordOrder(raw), true
+	default:
+		return "", false
+	}
+}
+
 type Dictionary struct {
 	ID          string
 	Title       string
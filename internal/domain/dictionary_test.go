@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestParseWordOrder(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want WordOrder
+		ok   bool
+	}{
+		{"author", WordOrderAuthor, true},
+		{"frequency", WordOrderFrequency, true},
+		{"random", WordOrderRandom, true},
+		{"topic", WordOrderTopic, true},
+		{"Random", "", false},
+		{"alphabet", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseWordOrder(tt.raw)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseWordOrder(%q) = %q, %v; want %q, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+const (
+	// DigestWeekday is the local weekday the digest of the past week is sent
+	// on.
+	DigestWeekday = time.Monday
+	// DigestDays is how many days a digest covers and looks ahead.
+	DigestDays = 7
+	// DigestHardestWords is how many of the most forgotten words a digest
+	// lists.
+	DigestHardestWords = 5
+)
+
+// DigestCandidate is a user whose digest of the week is not sent yet.
+type DigestCandidate struct {
+	UserID       int64
+	LanguageCode string
+	// Settings tell the local time the digest may be sent at.
+	Settings ReminderSettings
+	// Week is the local date of the first day of the week the digest covers.
+	Week time.Time
+}
+
+// HardWord is a word the user failed to recall.
+type HardWord struct {
+	Spelling      string
+	RUTranslation string
+	// Lapses counts the grades that didn't pass, see PassingGrade.
+	Lapses int
+}
+
+// WeeklyDigest is the progress of a user over DigestDays days.
+type WeeklyDigest struct {
+	// From and To are the local dates of the first and the last day covered.
+	From time.Time
+	To   time.Time
+	// WordsLearned are the words added to learning.
+	WordsLearned int
+	Retention    Retention
+	Streak       int
+	Hardest      []HardWord
+	// DueAhead are the words due in DigestDays days after the digest is
+	// built, the overdue ones included.
+	DueAhead int
+}
+
+// Empty reports whether there is nothing to tell: nothing was done and
+// nothing is due.
+func (d *WeeklyDigest) Empty() bool {
+	return d.WordsLearned == 0 && d.Retention.Reviews == 0 && d.DueAhead == 0
+}
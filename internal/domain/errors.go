@@ -3,17 +3,41 @@ package domain
 import "errors"
 
 var (
-	ErrDictionaryNotFound   = errors.New("dictionary not found")
-	ErrSubscriptionNotFound = errors.New("subscription not found")
-	ErrAlreadySubscribed    = errors.New("already subscribed")
+	ErrDictionaryNotFound    = errors.New("dictionary not found")
+	ErrSubscriptionNotFound  = errors.New("subscription not found")
+	ErrAlreadySubscribed     = errors.New("already subscribed")
+	ErrInvalidCardDirections = errors.New("invalid card directions")
 
 	ErrInvalidDictionaryNumber = errors.New("invalid dictionary number")
 	ErrNoWordsForLearning      = errors.New("no words for learning")
 	ErrLearningNotStarted      = errors.New("learning not started")
 
+	ErrLearnBatchFinished = errors.New("learn batch finished")
+	ErrStaleLearnCard     = errors.New("learn card is no longer shown")
+
 	ErrReviewNotStarted     = errors.New("review not started")
 	ErrNoWordsDueForReview  = errors.New("no words due for review")
 	ErrEmptyReviewWordsList = errors.New("empty review words list")
 	ErrReviewRoundFinished  = errors.New("review round finished")
 	ErrInvalidReviewGrade   = errors.New("invalid review grade")
+	ErrAnswerNotExpected    = errors.New("answer not expected")
+	ErrNothingToUndo        = errors.New("nothing to undo")
+	ErrWordStateChanged     = errors.New("word state changed concurrently")
+
+	ErrWordNotFound            = errors.New("word not found")
+	ErrInvalidWordAction       = errors.New("invalid word action")
+	ErrWordActionNotApplicable = errors.New("word action not applicable")
+
+	ErrInvalidReviewKeyboard = errors.New("invalid review keyboard")
+	ErrStaleReviewCard       = errors.New("review card is no longer shown")
+
+	ErrInvalidReminderTime  = errors.New("invalid reminder time")
+	ErrInvalidQuietHours    = errors.New("invalid quiet hours")
+	ErrInvalidTimezone      = errors.New("invalid timezone")
+	ErrRecipientUnavailable = errors.New("recipient unavailable")
+	ErrMessageNotSent       = errors.New("message not sent")
+
+	ErrInvalidSetting     = errors.New("invalid setting")
+	ErrDailyNewWordsLimit = errors.New("daily new words limit reached")
+	ErrDailyReviewLimit   = errors.New("daily review limit reached")
 )
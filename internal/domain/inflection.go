@@ -0,0 +1,199 @@
+package domain
+
+import "strings"
+
+// irregularForms are forms of common irregular verbs, nouns and adjectives
+// that the suffix rules in WordForms cannot produce.
+var irregularForms = map[string][]string{
+	"be":         {"am", "is", "are", "was", "were", "been", "being"},
+	"have":       {"has", "had", "having"},
+	"do":         {"does", "did", "done", "doing"},
+	"go":         {"goes", "went", "gone", "going"},
+	"begin":      {"began", "begun"},
+	"break":      {"broke", "broken"},
+	"bring":      {"brought"},
+	"build":      {"built"},
+	"buy":        {"bought"},
+	"catch":      {"caught"},
+	"choose":     {"chose", "chosen"},
+	"come":       {"came"},
+	"cost":       {"cost"},
+	"cut":        {"cut"},
+	"draw":       {"drew", "drawn"},
+	"drink":      {"drank", "drunk"},
+	"drive":      {"drove", "driven"},
+	"eat":        {"ate", "eaten"},
+	"fall":       {"fell", "fallen"},
+	"feel":       {"felt"},
+	"find":       {"found"},
+	"fly":        {"flew", "flown", "flies"},
+	"forget":     {"forgot", "forgotten"},
+	"get":        {"got", "gotten"},
+	"give":       {"gave", "given"},
+	"grow":       {"grew", "grown"},
+	"hear":       {"heard"},
+	"hold":       {"held"},
+	"keep":       {"kept"},
+	"know":       {"knew", "known"},
+	"learn":      {"learnt"},
+	"leave":      {"left"},
+	"lend":       {"lent"},
+	"lose":       {"lost"},
+	"make":       {"made"},
+	"mean":       {"meant"},
+	"meet":       {"met"},
+	"pay":        {"paid"},
+	"put":        {"put"},
+	"read":       {"read"},
+	"ride":       {"rode", "ridden"},
+	"ring":       {"rang", "rung"},
+	"run":        {"ran"},
+	"say":        {"said"},
+	"see":        {"saw", "seen"},
+	"sell":       {"sold"},
+	"send":       {"sent"},
+	"sing":       {"sang", "sung"},
+	"sit":        {"sat"},
+	"sleep":      {"slept"},
+	"speak":      {"spoke", "spoken"},
+	"spend":      {"spent"},
+	"stand":      {"stood"},
+	"swim":       {"swam", "swum"},
+	"take":       {"took", "taken"},
+	"teach":      {"taught"},
+	"tell":       {"told"},
+	"think":      {"thought"},
+	"throw":      {"threw", "thrown"},
+	"understand": {"understood"},
+	"wake":       {"woke", "woken"},
+	"wear":       {"wore", "worn"},
+	"win":        {"won"},
+	"write":      {"wrote", "written"},
+
+	"child":  {"children"},
+	"man":    {"men"},
+	"woman":  {"women"},
+	"person": {"people"},
+	"foot":   {"feet"},
+	"tooth":  {"teeth"},
+	"mouse":  {"mice"},
+	"goose":  {"geese"},
+	"sheep":  {"sheep"},
+	"fish":   {"fish"},
+	"knife":  {"knives"},
+	"wife":   {"wives"},
+	"life":   {"lives"},
+	"leaf":   {"leaves"},
+	"wolf":   {"wolves"},
+	"half":   {"halves"},
+
+	"good": {"better", "best"},
+	"bad":  {"worse", "worst"},
+	"far":  {"farther", "further", "farthest", "furthest"},
+}
+
+// WordForms returns the word itself followed by its likely inflected forms:
+// plural/third person (-s, -es, -ies), past (-ed, -d, -ied), -ing and
+// comparative/superlative (-er, -est), plus irregular forms. Rules are applied
+// blindly, so some forms do not exist ("cheaped"); that is fine for looking the
+// word up in a sentence. For a phrase only the first word is inflected
+// ("gets up", "got up").
+func WordForms(word string) []string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return nil
+	}
+
+	head, tail, _ := strings.Cut(word, " ")
+	if tail != "" {
+		tail = " " + tail
+	}
+
+	forms := []string{word}
+	seen := map[string]bool{word: true}
+	add := func(f string) {
+		f += tail
+		if !seen[f] {
+			seen[f] = true
+			forms = append(forms, f)
+		}
+	}
+
+	for _, f := range irregularForms[head] {
+		add(f)
+	}
+
+	for _, suffix := range []string{"s", "ed", "ing", "er", "est"} {
+		for _, f := range inflect(head, suffix) {
+			add(f)
+		}
+	}
+
+	return forms
+}
+
+func inflect(word, suffix string) []string {
+	n := len(word)
+	if n < 2 {
+		return []string{word + suffix}
+	}
+	last := word[n-1]
+	beforeLast := word[n-2]
+
+	switch suffix {
+	case "s":
+		switch {
+		case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+			strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+			return []string{word + "es"}
+		case last == 'o':
+			// potatoes, but photos
+			return []string{word + "es", word + "s"}
+		case last == 'y' && !isVowel(beforeLast):
+			return []string{word[:n-1] + "ies"}
+		default:
+			return []string{word + "s"}
+		}
+
+	case "ing":
+		switch {
+		case strings.HasSuffix(word, "ie"):
+			return []string{word[:n-2] + "ying"}
+		case last == 'e' && !strings.HasSuffix(word, "ee"):
+			return []string{word[:n-1] + "ing"}
+		}
+
+	default: // "ed", "er", "est" start with "e"
+		switch {
+		case last == 'e':
+			return []string{word + suffix[1:]}
+		case last == 'y' && !isVowel(beforeLast):
+			return []string{word[:n-1] + "i" + suffix}
+		}
+	}
+
+	forms := []string{word + suffix}
+	if endsWithCVC(word) {
+		// stop -> stopped, prefer -> preferred; "visit" -> "visitted" is
+		// harmless.
+		forms = append(forms, word+string(last)+suffix)
+	}
+
+	return forms
+}
+
+// endsWithCVC reports whether the word ends with consonant-vowel-consonant
+// where the final consonant may be doubled (not w, x or y).
+func endsWithCVC(word string) bool {
+	n := len(word)
+	if n < 3 {
+		return false
+	}
+	c1, v, c2 := word[n-3], word[n-2], word[n-1]
+
+	return !isVowel(c1) && isVowel(v) && !isVowel(c2) && !strings.ContainsRune("wxy", rune(c2))
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// LearnBatchSize is how many new words are introduced in a batch.
+const LearnBatchSize = 10
+
+// LearnBatch is a batch of new words, shown all at once before the quiz.
+type LearnBatch struct {
+	ID    string
+	Words []LearningWord
+}
+
+// LearnBatchCard is a quiz card of a batch of new words. The batch is quizzed
+// until every word has been recalled correctly once.
+type LearnBatchCard struct {
+	Word *ReviewWord
+	Quiz *Quiz
+	// Token identifies the card within the batch, so that buttons of answered
+	// cards can be rejected.
+	Token string
+	// Left is the number of words not recalled yet, this one included.
+	Left int
+}
+
+// LearnedWord is a word of a batch that was recalled in the quiz.
+type LearnedWord struct {
+	DictWordID string
+	// Mistakes is the number of wrong answers before the word was recalled.
+	Mistakes int
+}
+
+// learnedReviewSteps are the delays of the first review of a word learned in a
+// batch, by the number of mistakes: a word recalled at the first attempt comes
+// back later than a word that took several.
+var learnedReviewSteps = []time.Duration{4 * time.Hour, time.Hour, 10 * time.Minute}
+
+// LearnedReviewAt is when a word learned in a batch at learnedAt is first due
+// for review. The interval stays at zero days, so SM-2 schedules the word from
+// scratch after that review.
+func LearnedReviewAt(mistakes int, learnedAt time.Time) time.Time {
+	step := learnedReviewSteps[min(max(mistakes, 0), len(learnedReviewSteps)-1)]
+
+	return learnedAt.Add(step)
+}
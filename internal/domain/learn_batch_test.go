@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLearnedReviewAt(t *testing.T) {
+	learnedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		mistakes int
+		want     time.Duration
+	}{
+		{-1, 4 * time.Hour},
+		{0, 4 * time.Hour},
+		{1, time.Hour},
+		{2, 10 * time.Minute},
+		{7, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := LearnedReviewAt(tt.mistakes, learnedAt); !got.Equal(learnedAt.Add(tt.want)) {
+			t.Errorf("LearnedReviewAt(%d) = %v, want %v", tt.mistakes, got, learnedAt.Add(tt.want))
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// NotificationKind is what a notification tells the subscribers of a
+// dictionary about.
+type NotificationKind string
+
+const (
+	// NotificationNewWords tells that words were added to the dictionary.
+	NotificationNewWords NotificationKind = "new_words"
+	// NotificationBatchUnlocked tells that a batch of an on_schedule
+	// dictionary became available.
+	NotificationBatchUnlocked NotificationKind = "batch_unlocked"
+)
+
+const (
+	// NotificationMaxAttempts is how many times a notification is tried
+	// before it's given up on.
+	NotificationMaxAttempts = 5
+	// notificationRetryBase is the delay before the first retry; every next
+	// one waits twice as long.
+	notificationRetryBase = time.Minute
+)
+
+// Notification is a message waiting in the outbox to be sent to a
+// subscriber of a dictionary.
+type Notification struct {
+	ID           int64
+	UserID       int64
+	LanguageCode string
+	// Settings tell the quiet hours of the user.
+	Settings        ReminderSettings
+	Kind            NotificationKind
+	DictionaryID    string
+	DictionaryTitle string
+	// Words are the words added or unlocked.
+	Words int
+	// Attempts counts the failed attempts to send the notification.
+	Attempts int
+}
+
+// NotificationRetryDelay is how long to wait before the next attempt after
+// the given number of failed ones.
+func NotificationRetryDelay(attempts int) time.Duration {
+	return notificationRetryBase << max(attempts-1, 0)
+}
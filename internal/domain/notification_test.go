@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{NotificationMaxAttempts, 16 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := NotificationRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("NotificationRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
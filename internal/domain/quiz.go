@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	QuizOptionsCount = 4
+	// QuizFastLimit is the response time under which a correct choice gets the
+	// top grade.
+	QuizFastLimit = 6 * time.Second
+	// quizDistractorPool is how many of the most similar candidates the
+	// distractors are drawn from, so that the same word does not always get the
+	// same options.
+	quizDistractorPool = 8
+	// QuizCandidatesLimit is how many words of a dictionary distractors are
+	// picked from; bigger dictionaries are sampled.
+	QuizCandidatesLimit = 200
+)
+
+// Quiz is a multiple-choice question for the current review card.
+type Quiz struct {
+	Options []string
+	// Correct is the index of the right option.
+	Correct int
+}
+
+type QuizAnswerResult struct {
+	Word    *ReviewWord
+	Quiz    *Quiz
+	Chosen  int
+	Correct bool
+	Slow    bool
+	Grade   int
+}
+
+// GradeQuizAnswer maps a choice to an SM-2 grade: a wrong choice is MinGrade,
+// a correct one is MaxGrade, lowered by slowGrade if not made faster than
+// QuizFastLimit.
+func GradeQuizAnswer(quiz *Quiz, chosen int, elapsed time.Duration, scheduler Scheduler) *QuizAnswerResult {
+	res := &QuizAnswerResult{
+		Quiz:    quiz,
+		Chosen:  chosen,
+		Correct: chosen == quiz.Correct,
+		Grade:   MinGrade,
+	}
+	if res.Correct {
+		res.Slow = elapsed > QuizFastLimit
+		res.Grade = MaxGrade
+		if res.Slow {
+			res.Grade = slowGrade(res.Grade, scheduler)
+		}
+	}
+
+	return res
+}
+
+// BuildQuiz makes a question for the word: English options for reverse cards
+// and Russian ones for forward cards. Distractors are picked from candidates
+// (normally a sample of the dictionary, see QuizCandidatesLimit), preferring
+// words that look alike or whose translations look like the same part of
+// speech. Fewer than QuizOptionsCount options are returned if the dictionary
+// is too small.
+func BuildQuiz(word *ReviewWord, candidates []DictionaryWordPreview) *Quiz {
+	answer := quizOption(word.Direction, word.Spelling, word.RUTranslation)
+
+	type scored struct {
+		option string
+		score  float64
+	}
+	seen := map[string]bool{strings.ToLower(answer): true}
+	pool := make([]scored, 0, len(candidates))
+	for _, c := range candidates {
+		option := quizOption(word.Direction, c.Spelling, c.RUTranslation)
+		key := strings.ToLower(option)
+		if option == "" || seen[key] || strings.EqualFold(c.Spelling, word.Spelling) {
+			continue
+		}
+		seen[key] = true
+
+		score := spellingSimilarity(word.Spelling, c.Spelling) +
+			translationSimilarity(word.RUTranslation, c.RUTranslation)
+		pool = append(pool, scored{option: option, score: score})
+	}
+
+	// Shuffle first so that ties are broken randomly.
+	rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	sort.SliceStable(pool, func(i, j int) bool { return pool[i].score > pool[j].score })
+	if len(pool) > quizDistractorPool {
+		pool = pool[:quizDistractorPool]
+	}
+	rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	options := []string{answer}
+	for _, s := range pool {
+		if len(options) == QuizOptionsCount {
+			break
+		}
+		options = append(options, s.option)
+	}
+
+	correct := rand.IntN(len(options))
+	options[0], options[correct] = options[correct], options[0]
+
+	return &Quiz{Options: options, Correct: correct}
+}
+
+func quizOption(direction CardDirection, spelling, translation string) string {
+	if direction == CardDirectionReverse {
+		return spelling
+	}
+
+	return translation
+}
+
+// spellingSimilarity is 1 for equal words and goes to 0 as the edit distance
+// approaches the length of the longer word.
+func spellingSimilarity(a, b string) float64 {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 0
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// ruPartOfSpeechEndings are typical endings of Russian dictionary forms.
+// Translations with the same ending are likely the same part of speech, which
+// makes a distractor plausible ("бежать" vs "плавать", not "бежать" vs "стол").
+// Longer endings go first.
+var ruPartOfSpeechEndings = []string{
+	"ость", "ение", "ание", "ство", "ция",
+	"ться", "тись", "ть", "ти", "чь",
+	"ый", "ий", "ой", "ая", "яя", "ое", "ее",
+	"о", "е",
+}
+
+func translationSimilarity(a, b string) float64 {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+
+	score := 0.0
+	if len(strings.Fields(a)) == len(strings.Fields(b)) {
+		score += 0.2
+	}
+	for _, ending := range ruPartOfSpeechEndings {
+		if strings.HasSuffix(a, ending) {
+			if strings.HasSuffix(b, ending) {
+				score += 0.5
+			}
+			break
+		}
+	}
+
+	return score
+}
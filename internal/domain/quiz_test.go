@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGradeQuizAnswer(t *testing.T) {
+	const fast, slow = time.Second, QuizFastLimit + time.Second
+	quiz := &Quiz{Options: []string{"a", "b", "c", "d"}, Correct: 2}
+
+	tests := []struct {
+		name      string
+		chosen    int
+		elapsed   time.Duration
+		scheduler Scheduler
+		grade     int
+		correct   bool
+		slow      bool
+	}{
+		{"correct fast", 2, fast, SchedulerSM2, MaxGrade, true, false},
+		{"correct slow sm2 stays passing", 2, slow, SchedulerSM2, MaxGrade, true, true},
+		{"correct slow lenient lowered", 2, slow, SchedulerSM2Lenient, MaxGrade - 1, true, true},
+		{"wrong", 0, fast, SchedulerSM2, MinGrade, false, false},
+		{"wrong slow", 3, slow, SchedulerSM2Lenient, MinGrade, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := GradeQuizAnswer(quiz, tt.chosen, tt.elapsed, tt.scheduler)
+			if res.Grade != tt.grade || res.Correct != tt.correct || res.Slow != tt.slow {
+				t.Errorf("GradeQuizAnswer(%d, %v) = grade %d, correct %v, slow %v; want %d, %v, %v",
+					tt.chosen, tt.elapsed, res.Grade, res.Correct, res.Slow, tt.grade, tt.correct, tt.slow)
+			}
+		})
+	}
+}
+
+func TestBuildQuiz(t *testing.T) {
+	candidates := []DictionaryWordPreview{
+		{Spelling: "run", RUTranslation: "бежать"},
+		{Spelling: "Run", RUTranslation: "бегать"},
+		{Spelling: "swim", RUTranslation: "плавать"},
+		{Spelling: "table", RUTranslation: "стол"},
+		{Spelling: "walk", RUTranslation: "Плавать"},
+		{Spelling: "empty", RUTranslation: ""},
+		{Spelling: "fly", RUTranslation: "летать"},
+	}
+
+	tests := []struct {
+		name       string
+		word       *ReviewWord
+		candidates []DictionaryWordPreview
+		answer     string
+		options    int
+	}{
+		{
+			name:       "forward",
+			word:       &ReviewWord{Spelling: "run", RUTranslation: "бежать", Direction: CardDirectionForward},
+			candidates: candidates,
+			answer:     "бежать",
+			options:    QuizOptionsCount,
+		},
+		{
+			name:       "reverse",
+			word:       &ReviewWord{Spelling: "run", RUTranslation: "бежать", Direction: CardDirectionReverse},
+			candidates: candidates,
+			answer:     "run",
+			options:    QuizOptionsCount,
+		},
+		{
+			name: "small dictionary",
+			word: &ReviewWord{Spelling: "run", RUTranslation: "бежать", Direction: CardDirectionForward},
+			candidates: []DictionaryWordPreview{
+				{Spelling: "run", RUTranslation: "бежать"},
+				{Spelling: "swim", RUTranslation: "плавать"},
+			},
+			answer:  "бежать",
+			options: 2,
+		},
+		{
+			name:    "no candidates",
+			word:    &ReviewWord{Spelling: "run", RUTranslation: "бежать", Direction: CardDirectionForward},
+			answer:  "бежать",
+			options: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The options are random; every build must hold.
+			for range 50 {
+				quiz := BuildQuiz(tt.word, tt.candidates)
+
+				if len(quiz.Options) != tt.options {
+					t.Fatalf("options = %v, want %d of them", quiz.Options, tt.options)
+				}
+				if quiz.Options[quiz.Correct] != tt.answer {
+					t.Fatalf("correct option = %q, want %q", quiz.Options[quiz.Correct], tt.answer)
+				}
+
+				seen := make([]string, 0, len(quiz.Options))
+				for _, o := range quiz.Options {
+					key := strings.ToLower(o)
+					if o == "" || slices.Contains(seen, key) {
+						t.Fatalf("options %v have an empty or repeated one", quiz.Options)
+					}
+					seen = append(seen, key)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildQuizPrefersSimilarWords(t *testing.T) {
+	word := &ReviewWord{Spelling: "cattle", RUTranslation: "скот", Direction: CardDirectionReverse}
+
+	var candidates []DictionaryWordPreview
+	for _, s := range []string{"castle", "battle", "rattle", "settle", "kettle", "little", "bottle", "tattle"} {
+		candidates = append(candidates, DictionaryWordPreview{Spelling: s, RUTranslation: "слово"})
+	}
+	dissimilar := []string{"umbrella", "philosophy", "knowledge", "yesterday"}
+	for _, s := range dissimilar {
+		candidates = append(candidates, DictionaryWordPreview{Spelling: s, RUTranslation: "другое слово"})
+	}
+
+	// Only the quizDistractorPool most similar candidates are drawn from.
+	for range 50 {
+		for _, o := range BuildQuiz(word, candidates).Options {
+			if slices.Contains(dissimilar, o) {
+				t.Fatalf("dissimilar word %q picked", o)
+			}
+		}
+	}
+}
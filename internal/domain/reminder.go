@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinutesPerDay bounds the clock times of reminder settings, which are kept
+// as minutes after local midnight.
+const MinutesPerDay = 24 * 60
+
+// ReminderSettings is when the user wants to be reminded of due words.
+type ReminderSettings struct {
+	Enabled bool
+	// Timezone is an IANA time zone name.
+	Timezone string
+	// Time is the earliest local time of the daily reminder.
+	Time int
+	// QuietStart and QuietEnd bound the local hours no reminder is sent in;
+	// the range may wrap midnight. Both are nil if there are no quiet hours.
+	QuietStart *int
+	QuietEnd   *int
+}
+
+// Location returns the time zone of the settings, UTC if it is unknown.
+func (s ReminderSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// InQuietHours reports whether the local clock time falls in the quiet hours.
+func (s ReminderSettings) InQuietHours(minute int) bool {
+	if s.QuietStart == nil || s.QuietEnd == nil {
+		return false
+	}
+
+	start, end := *s.QuietStart, *s.QuietEnd
+	if start <= end {
+		return minute >= start && minute < end
+	}
+
+	return minute >= start || minute < end
+}
+
+// Allows reports whether the user may be messaged at the moment: not before
+// the preferred time of the local day and not in the quiet hours.
+func (s ReminderSettings) Allows(now time.Time) bool {
+	minute := s.localMinute(now)
+
+	return minute >= s.Time && !s.InQuietHours(minute)
+}
+
+// Quiet reports whether the moment falls in the quiet hours of the user.
+func (s ReminderSettings) Quiet(now time.Time) bool {
+	return s.InQuietHours(s.localMinute(now))
+}
+
+// localMinute is the minute of the local day the moment falls on.
+func (s ReminderSettings) localMinute(now time.Time) int {
+	local := now.In(s.Location())
+
+	return local.Hour()*60 + local.Minute()
+}
+
+// ReminderCandidate is a user with due words who hasn't been reminded today.
+type ReminderCandidate struct {
+	UserID       int64
+	LanguageCode string
+	Settings     ReminderSettings
+	DueCount     int
+}
+
+// ShouldRemind reports whether the reminder is to be sent at the moment, see
+// ReminderSettings.Allows.
+func (c ReminderCandidate) ShouldRemind(now time.Time) bool {
+	return c.Settings.Allows(now)
+}
+
+// ParseClock parses a local clock time like "9:30" or "21:05" into minutes
+// after midnight.
+func ParseClock(raw string) (int, bool) {
+	hh, mm, found := strings.Cut(strings.TrimSpace(raw), ":")
+	if !found {
+		return 0, false
+	}
+
+	hour, err := strconv.Atoi(hh)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, false
+	}
+	minute, err := strconv.Atoi(mm)
+	if err != nil || len(mm) != 2 || minute < 0 || minute > 59 {
+		return 0, false
+	}
+
+	return hour*60 + minute, true
+}
+
+// FormatClock is the inverse of ParseClock.
+func FormatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// ParseQuietHours parses a range of local clock times like "23:00-08:00".
+func ParseQuietHours(raw string) (int, int, bool) {
+	from, to, found := strings.Cut(raw, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, ok := ParseClock(from)
+	if !ok {
+		return 0, 0, false
+	}
+	end, ok := ParseClock(to)
+	if !ok || end == start {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// ParseTimezone accepts an IANA time zone name like "Europe/Moscow" or a
+// whole-hour UTC offset like "UTC+3", "+3" or "GMT-5", and returns the IANA
+// name to store.
+func ParseTimezone(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false
+	}
+
+	offset := strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(raw), "UTC"), "GMT")
+	if offset == "" {
+		return "UTC", true
+	}
+	if offset[0] == '+' || offset[0] == '-' {
+		hours, err := strconv.Atoi(offset[1:])
+		if err != nil || hours < 0 || hours > 14 || (offset[0] == '-' && hours > 12) {
+			return "", false
+		}
+		if hours == 0 {
+			return "UTC", true
+		}
+
+		// the signs of the Etc zones are inverted: Etc/GMT-3 is UTC+3
+		sign := "-"
+		if offset[0] == '-' {
+			sign = "+"
+		}
+
+		return fmt.Sprintf("Etc/GMT%s%d", sign, hours), true
+	}
+
+	loc, err := time.LoadLocation(raw)
+	if err != nil || loc.String() == "Local" {
+		return "", false
+	}
+
+	return loc.String(), true
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimezone(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+		ok   bool
+	}{
+		{"Europe/Moscow", "Europe/Moscow", true},
+		{" Asia/Tokyo ", "Asia/Tokyo", true},
+		{"UTC", "UTC", true},
+		{"gmt", "UTC", true},
+		{"UTC+3", "Etc/GMT-3", true},
+		{"+3", "Etc/GMT-3", true},
+		{"GMT-5", "Etc/GMT+5", true},
+		{"utc+14", "Etc/GMT-14", true},
+		{"UTC-12", "Etc/GMT+12", true},
+		{"UTC+0", "UTC", true},
+		{"UTC-13", "", false},
+		{"UTC+15", "", false},
+		{"UTC+3:30", "", false},
+		{"+", "", false},
+		{"Local", "", false},
+		{"Mars/Olympus", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, ok := ParseTimezone(tt.raw)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseTimezone(%q) = %q, %v; want %q, %v", tt.raw, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want int
+		ok   bool
+	}{
+		{"9:30", 9*60 + 30, true},
+		{"09:30", 9*60 + 30, true},
+		{" 21:05 ", 21*60 + 5, true},
+		{"0:00", 0, true},
+		{"23:59", MinutesPerDay - 1, true},
+		{"24:00", 0, false},
+		{"12:60", 0, false},
+		{"12:5", 0, false},
+		{"-1:00", 0, false},
+		{"1230", 0, false},
+		{"ab:cd", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, ok := ParseClock(tt.raw)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseClock(%q) = %d, %v; want %d, %v", tt.raw, got, ok, tt.want, tt.ok)
+			}
+			if ok {
+				if back, _ := ParseClock(FormatClock(got)); back != got {
+					t.Errorf("FormatClock(%d) = %q doesn't parse back", got, FormatClock(got))
+				}
+			}
+		})
+	}
+}
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		raw        string
+		start, end int
+		ok         bool
+	}{
+		{"23:00-08:00", 23 * 60, 8 * 60, true},
+		{"13:00 - 14:30", 13 * 60, 14*60 + 30, true},
+		{"00:00-06:00", 0, 6 * 60, true},
+		{"10:00-10:00", 0, 0, false},
+		{"23:00", 0, 0, false},
+		{"23:00-25:00", 0, 0, false},
+		{"late-early", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			start, end, ok := ParseQuietHours(tt.raw)
+			if start != tt.start || end != tt.end || ok != tt.ok {
+				t.Errorf("ParseQuietHours(%q) = %d, %d, %v; want %d, %d, %v",
+					tt.raw, start, end, ok, tt.start, tt.end, tt.ok)
+			}
+		})
+	}
+}
+
+func TestReminderSettingsAllows(t *testing.T) {
+	clock := func(h, m int) *int {
+		minute := h*60 + m
+		return &minute
+	}
+	// The moments are in UTC; Moscow is UTC+3.
+	at := func(h, m int) time.Time {
+		return time.Date(2026, 3, 10, h, m, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		settings ReminderSettings
+		now      time.Time
+		allows   bool
+		quiet    bool
+	}{
+		{
+			name:     "before the time",
+			settings: ReminderSettings{Timezone: "UTC", Time: 9 * 60},
+			now:      at(8, 59),
+			allows:   false,
+		},
+		{
+			name:     "at the time",
+			settings: ReminderSettings{Timezone: "UTC", Time: 9 * 60},
+			now:      at(9, 0),
+			allows:   true,
+		},
+		{
+			name:     "local time of the zone",
+			settings: ReminderSettings{Timezone: "Europe/Moscow", Time: 9 * 60},
+			now:      at(6, 0),
+			allows:   true,
+		},
+		{
+			name:     "unknown zone is UTC",
+			settings: ReminderSettings{Timezone: "Mars/Olympus", Time: 9 * 60},
+			now:      at(6, 0),
+			allows:   false,
+		},
+		{
+			name:     "quiet hours within a day",
+			settings: ReminderSettings{Timezone: "UTC", QuietStart: clock(13, 0), QuietEnd: clock(14, 0)},
+			now:      at(13, 30),
+			allows:   false,
+			quiet:    true,
+		},
+		{
+			name:     "end of quiet hours is not quiet",
+			settings: ReminderSettings{Timezone: "UTC", QuietStart: clock(13, 0), QuietEnd: clock(14, 0)},
+			now:      at(14, 0),
+			allows:   true,
+		},
+		{
+			name:     "quiet hours wrapping midnight, evening",
+			settings: ReminderSettings{Timezone: "UTC", QuietStart: clock(22, 0), QuietEnd: clock(7, 0)},
+			now:      at(23, 0),
+			allows:   false,
+			quiet:    true,
+		},
+		{
+			name:     "quiet hours wrapping midnight, morning",
+			settings: ReminderSettings{Timezone: "Europe/Moscow", QuietStart: clock(22, 0), QuietEnd: clock(7, 0)},
+			now:      at(3, 0),
+			allows:   false,
+			quiet:    true,
+		},
+		{
+			name:     "quiet hours wrapping midnight, day",
+			settings: ReminderSettings{Timezone: "UTC", QuietStart: clock(22, 0), QuietEnd: clock(7, 0)},
+			now:      at(12, 0),
+			allows:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.Allows(tt.now); got != tt.allows {
+				t.Errorf("Allows(%v) = %v, want %v", tt.now, got, tt.allows)
+			}
+			if got := tt.settings.Quiet(tt.now); got != tt.quiet {
+				t.Errorf("Quiet(%v) = %v, want %v", tt.now, got, tt.quiet)
+			}
+		})
+	}
+}
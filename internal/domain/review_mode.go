@@ -0,0 +1,77 @@
+package domain
+
+// ReviewMode is how cards are presented and graded in a review session.
+type ReviewMode string
+
+const (
+	// ReviewModeFlashcard shows the card and the user grades themselves.
+	ReviewModeFlashcard ReviewMode = "flashcard"
+	// ReviewModeTyped shows the translation and the user types the word; the
+	// grade is computed from the answer.
+	ReviewModeTyped ReviewMode = "typed"
+	// ReviewModeQuiz shows the card with several options to choose from; the
+	// grade is computed from the choice.
+	ReviewModeQuiz ReviewMode = "quiz"
+	// ReviewModeCloze shows an example sentence with the word blanked out and
+	// the user types the missing word. Words without a suitable example fall
+	// back to ReviewModeTyped.
+	ReviewModeCloze ReviewMode = "cloze"
+	// ReviewModeListening sends only the pronunciation and the user types the
+	// word. Words without audio fall back to ReviewModeTyped.
+	ReviewModeListening ReviewMode = "listening"
+)
+
+// ReviewKeyboard is how review cards are presented in the chat.
+type ReviewKeyboard string
+
+const (
+	// ReviewKeyboardReply sends every card as a new message with a reply
+	// keyboard.
+	ReviewKeyboardReply ReviewKeyboard = "reply"
+	// ReviewKeyboardInline edits one message with an inline keyboard. Modes
+	// with typed answers always use the reply keyboard.
+	ReviewKeyboardInline ReviewKeyboard = "inline"
+)
+
+func ParseReviewKeyboard(raw string) (ReviewKeyboard, bool) {
+	switch ReviewKeyboard(raw) {
+	case ReviewKeyboardReply, ReviewKeyboardInline:
+		return ReviewKeyboard(raw), true
+	default:
+		return "", false
+	}
+}
+
+// TypesAnswers reports whether the user answers cards of the mode by typing.
+func (m ReviewMode) TypesAnswers() bool {
+	switch m {
+	case ReviewModeTyped, ReviewModeCloze, ReviewModeListening:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReviewCard is a review word prepared for presentation in a session mode.
+// Mode is the mode of this card, which may differ from the session one.
+type ReviewCard struct {
+	Word *ReviewWord
+	Mode ReviewMode
+	// Quiz is set in ReviewModeQuiz.
+	Quiz *Quiz
+	// Cloze is set in ReviewModeCloze.
+	Cloze *Cloze
+	// Token identifies the card within the session, so that buttons of cards
+	// that are no longer shown can be rejected.
+	Token    string
+	Keyboard ReviewKeyboard
+	// PreviousChanged is set when the card shown before was changed elsewhere
+	// since it was shown, e.g. by /word, and so was skipped without its grade.
+	PreviousChanged bool
+}
+
+// Inline reports whether the card is shown with an inline keyboard.
+func (c *ReviewCard) Inline() bool {
+	return c.Keyboard == ReviewKeyboardInline &&
+		(c.Mode == ReviewModeFlashcard || c.Mode == ReviewModeQuiz)
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "testing"
+
+func TestReviewModeTypesAnswers(t *testing.T) {
+	tests := []struct {
+		mode ReviewMode
+		want bool
+	}{
+		{ReviewModeFlashcard, false},
+		{ReviewModeQuiz, false},
+		{ReviewModeTyped, true},
+		{ReviewModeCloze, true},
+		{ReviewModeListening, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.mode.TypesAnswers(); got != tt.want {
+			t.Errorf("%q.TypesAnswers() = %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestParseReviewKeyboard(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want ReviewKeyboard
+		ok   bool
+	}{
+		{"reply", ReviewKeyboardReply, true},
+		{"inline", ReviewKeyboardInline, true},
+		{"Inline", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseReviewKeyboard(tt.raw)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseReviewKeyboard(%q) = %q, %v; want %q, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReviewCardInline(t *testing.T) {
+	tests := []struct {
+		keyboard ReviewKeyboard
+		mode     ReviewMode
+		want     bool
+	}{
+		{ReviewKeyboardInline, ReviewModeFlashcard, true},
+		{ReviewKeyboardInline, ReviewModeQuiz, true},
+		{ReviewKeyboardInline, ReviewModeTyped, false},
+		{ReviewKeyboardInline, ReviewModeCloze, false},
+		{ReviewKeyboardInline, ReviewModeListening, false},
+		{ReviewKeyboardReply, ReviewModeFlashcard, false},
+		{ReviewKeyboardReply, ReviewModeQuiz, false},
+	}
+
+	for _, tt := range tests {
+		card := &ReviewCard{Keyboard: tt.keyboard, Mode: tt.mode}
+		if got := card.Inline(); got != tt.want {
+			t.Errorf("Inline() of a %q card with a %q keyboard = %v, want %v", tt.mode, tt.keyboard, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Overdueness is how late the card is relative to its interval: 0 when it is
+// exactly due, 1 when it is a whole interval late. The later the card is
+// relative to the interval, the lower its retrievability, so it goes first.
+// Cards that were never reviewed go before any scheduled card.
+func Overdueness(w *ReviewWord, now time.Time) float64 {
+	if w.NextReviewAt == nil {
+		return maxOverdueness
+	}
+
+	interval := float64(max(w.IntervalDays, 1)) * 24
+	late := now.Sub(*w.NextReviewAt).Hours()
+
+	return late / interval
+}
+
+const maxOverdueness = 1e9
+
+// InterleaveReviewWords orders due words from several dictionaries: the most
+// overdue first, while avoiding two words of the same dictionary in a row
+// whenever another dictionary still has words.
+func InterleaveReviewWords(words []*ReviewWord, now time.Time) []*ReviewWord {
+	sorted := make([]*ReviewWord, len(words))
+	copy(sorted, words)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return Overdueness(sorted[i], now) > Overdueness(sorted[j], now)
+	})
+
+	result := make([]*ReviewWord, 0, len(sorted))
+	lastDictionaryID := ""
+	for len(sorted) > 0 {
+		pick := 0
+		for i, w := range sorted {
+			if w.DictionaryID != lastDictionaryID {
+				pick = i
+				break
+			}
+		}
+
+		result = append(result, sorted[pick])
+		lastDictionaryID = sorted[pick].DictionaryID
+		sorted = append(sorted[:pick], sorted[pick+1:]...)
+	}
+
+	return result
+}
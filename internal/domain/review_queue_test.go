@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOverdueness(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		ts := now.Add(-d)
+		return &ts
+	}
+
+	tests := []struct {
+		name string
+		word *ReviewWord
+		want float64
+	}{
+		{"never reviewed", &ReviewWord{}, maxOverdueness},
+		{"exactly due", &ReviewWord{IntervalDays: 4, NextReviewAt: at(0)}, 0},
+		{"half an interval late", &ReviewWord{IntervalDays: 4, NextReviewAt: at(48 * time.Hour)}, 0.5},
+		{"zero interval counts as a day", &ReviewWord{NextReviewAt: at(24 * time.Hour)}, 1},
+		{"not due yet", &ReviewWord{IntervalDays: 1, NextReviewAt: at(-12 * time.Hour)}, -0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Overdueness(tt.word, now); got != tt.want {
+				t.Errorf("Overdueness = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterleaveReviewWords(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	// word makes a word of the dictionary late by the days on a one-day
+	// interval, so that a later word is more overdue.
+	word := func(id, dictionaryID string, lateDays int) *ReviewWord {
+		at := now.AddDate(0, 0, -lateDays)
+		return &ReviewWord{ID: id, DictionaryID: dictionaryID, IntervalDays: 1, NextReviewAt: &at}
+	}
+
+	tests := []struct {
+		name  string
+		words []*ReviewWord
+		want  []string
+	}{
+		{
+			name:  "empty",
+			words: nil,
+			want:  []string{},
+		},
+		{
+			name:  "one dictionary by overdueness",
+			words: []*ReviewWord{word("a1", "a", 1), word("a3", "a", 3), word("a2", "a", 2)},
+			want:  []string{"a3", "a2", "a1"},
+		},
+		{
+			name: "dictionaries alternate",
+			words: []*ReviewWord{
+				word("a1", "a", 6), word("a2", "a", 5), word("a3", "a", 4),
+				word("b1", "b", 3), word("b2", "b", 2),
+			},
+			want: []string{"a1", "b1", "a2", "b2", "a3"},
+		},
+		{
+			name: "leftovers of one dictionary in a row",
+			words: []*ReviewWord{
+				word("a1", "a", 5), word("a2", "a", 4), word("a3", "a", 3), word("b1", "b", 1),
+			},
+			want: []string{"a1", "b1", "a2", "a3"},
+		},
+		{
+			name: "new words first",
+			words: []*ReviewWord{
+				word("a1", "a", 9),
+				{ID: "b-new", DictionaryID: "b"},
+				word("b1", "b", 1),
+			},
+			want: []string{"b-new", "a1", "b1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := slices.Clone(tt.words)
+
+			got := make([]string, 0, len(tt.words))
+			for _, w := range InterleaveReviewWords(tt.words, now) {
+				got = append(got, w.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("InterleaveReviewWords = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(tt.words, input) {
+				t.Error("InterleaveReviewWords changed its input")
+			}
+		})
+	}
+}
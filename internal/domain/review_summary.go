@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// maxHardestWords is how many of the worst graded words the summary lists.
+const maxHardestWords = 5
+
+// ReviewGradeRecord is a grade given in a review session.
+type ReviewGradeRecord struct {
+	Word  *ReviewWord
+	Grade int
+}
+
+// ReviewSummary describes a finished review round.
+type ReviewSummary struct {
+	Reviewed int
+	// Grades is the number of cards per grade, indexed by grade.
+	Grades [MaxGrade + 1]int
+	// Accuracy is the share of cards recalled with a grade passing under the
+	// scheduler of the round, see PassingGrade.
+	Accuracy float64
+	Duration time.Duration
+	// Hardest are the words graded below passing, worst first.
+	Hardest []*ReviewWord
+	// DueTomorrow is the number of words due by the end of the next day in the
+	// same scope as the round.
+	DueTomorrow int
+}
+
+// SummarizeReview builds a summary of the grades given under the scheduler;
+// DueTomorrow is left to the caller.
+func SummarizeReview(records []ReviewGradeRecord, scheduler Scheduler, duration time.Duration) *ReviewSummary {
+	summary := &ReviewSummary{
+		Reviewed: len(records),
+		Duration: duration,
+	}
+
+	passing := PassingGrade(scheduler)
+	recalled := 0
+	hard := make([]ReviewGradeRecord, 0, len(records))
+	for _, r := range records {
+		if r.Grade < MinGrade || r.Grade > MaxGrade {
+			continue
+		}
+
+		summary.Grades[r.Grade]++
+		if r.Grade >= passing {
+			recalled++
+			continue
+		}
+		hard = append(hard, r)
+	}
+
+	if summary.Reviewed > 0 {
+		summary.Accuracy = float64(recalled) / float64(summary.Reviewed)
+	}
+
+	sort.SliceStable(hard, func(i, j int) bool { return hard[i].Grade < hard[j].Grade })
+	seen := make(map[string]struct{}, len(hard))
+	for _, r := range hard {
+		if len(summary.Hardest) == maxHardestWords {
+			break
+		}
+		if _, ok := seen[r.Word.ID]; ok {
+			continue
+		}
+
+		seen[r.Word.ID] = struct{}{}
+		summary.Hardest = append(summary.Hardest, r.Word)
+	}
+
+	return summary
+}
+
+// ReviewRoundFinishedError is ErrReviewRoundFinished with the summary of the
+// round.
+type ReviewRoundFinishedError struct {
+	Summary *ReviewSummary
+}
+
+func (e *ReviewRoundFinishedError) Error() string {
+	return ErrReviewRoundFinished.Error()
+}
+
+func (e *ReviewRoundFinishedError) Unwrap() error {
+	return ErrReviewRoundFinished
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeReview(t *testing.T) {
+	cat := &ReviewWord{ID: "cat"}
+	dog := &ReviewWord{ID: "dog"}
+	fox := &ReviewWord{ID: "fox"}
+	records := []ReviewGradeRecord{
+		{Word: cat, Grade: 3},
+		{Word: dog, Grade: 2},
+		{Word: fox, Grade: 0},
+		{Word: dog, Grade: 1},
+	}
+
+	tests := []struct {
+		name         string
+		scheduler    Scheduler
+		wantAccuracy float64
+		wantHardest  []*ReviewWord
+	}{
+		{"sm2", SchedulerSM2, 0.25, []*ReviewWord{fox, dog}},
+		{"lenient", SchedulerSM2Lenient, 0.5, []*ReviewWord{fox, dog}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := SummarizeReview(records, tt.scheduler, time.Minute)
+
+			if summary.Reviewed != len(records) {
+				t.Errorf("reviewed = %d, want %d", summary.Reviewed, len(records))
+			}
+			if summary.Accuracy != tt.wantAccuracy {
+				t.Errorf("accuracy = %v, want %v", summary.Accuracy, tt.wantAccuracy)
+			}
+			if summary.Grades != [MaxGrade + 1]int{1, 1, 1, 1} {
+				t.Errorf("grades = %v, want one of each", summary.Grades)
+			}
+			if summary.Duration != time.Minute {
+				t.Errorf("duration = %v, want %v", summary.Duration, time.Minute)
+			}
+
+			if len(summary.Hardest) != len(tt.wantHardest) {
+				t.Fatalf("hardest = %d words, want %d", len(summary.Hardest), len(tt.wantHardest))
+			}
+			for i, word := range tt.wantHardest {
+				if summary.Hardest[i] != word {
+					t.Errorf("hardest[%d] = %q, want %q", i, summary.Hardest[i].ID, word.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeReviewHardestLimit(t *testing.T) {
+	var records []ReviewGradeRecord
+	for i := range maxHardestWords + 3 {
+		records = append(records, ReviewGradeRecord{Word: &ReviewWord{ID: string(rune('a' + i))}, Grade: i % 3})
+	}
+
+	summary := SummarizeReview(records, SchedulerSM2, 0)
+	if len(summary.Hardest) != maxHardestWords {
+		t.Fatalf("hardest = %d words, want %d", len(summary.Hardest), maxHardestWords)
+	}
+	for i := 1; i < len(summary.Hardest); i++ {
+		prev, cur := summary.Hardest[i-1].ID[0]-'a', summary.Hardest[i].ID[0]-'a'
+		if prev%3 > cur%3 {
+			t.Errorf("hardest aren't worst first: %q before %q", summary.Hardest[i-1].ID, summary.Hardest[i].ID)
+		}
+	}
+}
+
+func TestSummarizeReviewEmpty(t *testing.T) {
+	summary := SummarizeReview(nil, SchedulerSM2, 0)
+	if summary.Reviewed != 0 || summary.Accuracy != 0 || len(summary.Hardest) != 0 {
+		t.Errorf("summary of no grades = %+v, want an empty one", summary)
+	}
+}
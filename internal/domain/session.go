@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// SessionKind is the flow a session of a user keeps the state of. A user has
+// at most one session of every kind.
+type SessionKind string
+
+const (
+	// SessionReview is a review round.
+	SessionReview SessionKind = "review"
+	// SessionLearning is the word being learned one by one.
+	SessionLearning SessionKind = "learning"
+	// SessionLearnBatch is a batch of new words and its quiz.
+	SessionLearnBatch SessionKind = "learn_batch"
+)
+
+// SessionTTL is how long a session is kept since it was last saved.
+const SessionTTL = 24 * time.Hour
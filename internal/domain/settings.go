@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Scheduler is the algorithm that computes the next review of a card from a
+// grade.
+type Scheduler string
+
+const (
+	// SchedulerSM2 is classic SM-2: any grade below the top one starts the
+	// card over.
+	SchedulerSM2 Scheduler = "sm2"
+	// SchedulerSM2Lenient is SM-2 where only a forgotten card starts over; a
+	// hard or easy recall keeps the progress and grows the interval slower.
+	SchedulerSM2Lenient Scheduler = "sm2_lenient"
+)
+
+// Schedulers are the schedulers to choose from, in menu order.
+var Schedulers = []Scheduler{SchedulerSM2, SchedulerSM2Lenient}
+
+func ParseScheduler(raw string) (Scheduler, bool) {
+	if slices.Contains(Schedulers, Scheduler(raw)) {
+		return Scheduler(raw), true
+	}
+
+	return "", false
+}
+
+// ReviewModePreferred stands for the review mode chosen in the settings.
+const ReviewModePreferred ReviewMode = ""
+
+// ReviewModes are the review modes to choose from, in menu order.
+var ReviewModes = []ReviewMode{
+	ReviewModeFlashcard,
+	ReviewModeTyped,
+	ReviewModeQuiz,
+	ReviewModeCloze,
+	ReviewModeListening,
+}
+
+func ParseReviewMode(raw string) (ReviewMode, bool) {
+	if slices.Contains(ReviewModes, ReviewMode(raw)) {
+		return ReviewMode(raw), true
+	}
+
+	return "", false
+}
+
+// AllCardDirections are the card directions to choose from, in menu order.
+var AllCardDirections = []CardDirections{CardDirectionsForward, CardDirectionsReverse, CardDirectionsBoth}
+
+// Bounds and steps of the daily limits.
+const (
+	NewWordsPerDayMin  = 5
+	NewWordsPerDayMax  = 100
+	NewWordsPerDayStep = 5
+
+	ReviewsPerDayMin  = 50
+	ReviewsPerDayMax  = 1000
+	ReviewsPerDayStep = 50
+
+	// maxUTCOffsetHours and minUTCOffsetHours bound the time zones reachable
+	// by stepping the offset.
+	maxUTCOffsetHours = 14
+	minUTCOffsetHours = -12
+)
+
+// UserSettings are the preferences of a user.
+type UserSettings struct {
+	// Language is empty until the user chooses one; the language of the
+	// Telegram client is used then.
+	Language string
+	Reminder ReminderSettings
+	// NewWordsPerDay limits the words added to learning per local day.
+	NewWordsPerDay int
+	// ReviewsPerDay limits the grades of due rounds per local day.
+	ReviewsPerDay int
+	// ReviewMode is the mode of rounds started without choosing one.
+	ReviewMode ReviewMode
+	// ReviewKeyboard is how review cards are presented.
+	ReviewKeyboard ReviewKeyboard
+	// CardDirections are given to newly subscribed dictionaries.
+	CardDirections CardDirections
+	Scheduler      Scheduler
+	DailyGoal      DailyGoal
+	// StreakFreeze lets earned streak freezes cover missed days.
+	StreakFreeze bool
+	// Digest sends the weekly progress digest.
+	Digest bool
+	// Notifications tell about new words in the subscribed dictionaries.
+	Notifications bool
+}
+
+// DefaultUserSettings are the settings of a user who hasn't changed any.
+func DefaultUserSettings() *UserSettings {
+	quietStart, quietEnd := 22*60, 8*60
+
+	return &UserSettings{
+		Reminder: ReminderSettings{
+			Enabled:    true,
+			Timezone:   "UTC",
+			Time:       10 * 60,
+			QuietStart: &quietStart,
+			QuietEnd:   &quietEnd,
+		},
+		NewWordsPerDay: 20,
+		ReviewsPerDay:  200,
+		ReviewMode:     ReviewModeFlashcard,
+		ReviewKeyboard: ReviewKeyboardReply,
+		CardDirections: CardDirectionsForward,
+		Scheduler:      SchedulerSM2,
+		DailyGoal:      DailyGoal{Kind: GoalKindReviews, Target: 20},
+		StreakFreeze:   true,
+		Notifications:  true,
+	}
+}
+
+// DayStart is the start of the local day of the user the moment falls in.
+func (s *UserSettings) DayStart(t time.Time) time.Time {
+	year, month, day := t.In(s.Reminder.Location()).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, s.Reminder.Location())
+}
+
+// SettingField is a setting changed step by step from the settings menu.
+type SettingField string
+
+const (
+	SettingTimezone       SettingField = "tz"
+	SettingReminderTime   SettingField = "rtime"
+	SettingReminders      SettingField = "remind"
+	SettingNewWordsPerDay SettingField = "new"
+	SettingReviewsPerDay  SettingField = "reviews"
+	SettingReviewMode     SettingField = "mode"
+	SettingCardDirections SettingField = "dirs"
+	SettingScheduler      SettingField = "sched"
+	SettingGoalKind       SettingField = "goal"
+	SettingGoalTarget     SettingField = "goaln"
+	SettingStreakFreeze   SettingField = "freeze"
+	SettingDigest         SettingField = "digest"
+	SettingNotifications  SettingField = "notify"
+)
+
+// Adjust moves the field by delta steps: an hour for the time zone and the
+// reminder time, the limit step for the limits and the next option for the
+// choices; a delta of any sign toggles the reminders, the streak freezes, the
+// digest and the notifications. Limits are clamped to their bounds and
+// choices wrap around.
+func (s *UserSettings) Adjust(field SettingField, delta int, now time.Time) error {
+	switch field {
+	case SettingTimezone:
+		_, offset := now.In(s.Reminder.Location()).Zone()
+		hours := min(max(offset/3600+delta, minUTCOffsetHours), maxUTCOffsetHours)
+		timezone, ok := ParseTimezone(fmt.Sprintf("%+d", hours))
+		if !ok {
+			return ErrInvalidTimezone
+		}
+		s.Reminder.Timezone = timezone
+	case SettingReminderTime:
+		s.Reminder.Time = ((s.Reminder.Time+delta*60)%MinutesPerDay + MinutesPerDay) % MinutesPerDay
+	case SettingReminders:
+		s.Reminder.Enabled = !s.Reminder.Enabled
+	case SettingNewWordsPerDay:
+		s.NewWordsPerDay = min(max(s.NewWordsPerDay+delta*NewWordsPerDayStep, NewWordsPerDayMin), NewWordsPerDayMax)
+	case SettingReviewsPerDay:
+		s.ReviewsPerDay = min(max(s.ReviewsPerDay+delta*ReviewsPerDayStep, ReviewsPerDayMin), ReviewsPerDayMax)
+	case SettingReviewMode:
+		s.ReviewMode = cycle(ReviewModes, s.ReviewMode, delta)
+	case SettingCardDirections:
+		s.CardDirections = cycle(AllCardDirections, s.CardDirections, delta)
+	case SettingScheduler:
+		s.Scheduler = cycle(Schedulers, s.Scheduler, delta)
+	case SettingGoalKind:
+		s.DailyGoal.Kind = cycle(GoalKinds, s.DailyGoal.Kind, delta)
+	case SettingGoalTarget:
+		s.DailyGoal.Target = min(max(s.DailyGoal.Target+delta*DailyGoalStep, DailyGoalMin), DailyGoalMax)
+	case SettingStreakFreeze:
+		s.StreakFreeze = !s.StreakFreeze
+	case SettingDigest:
+		s.Digest = !s.Digest
+	case SettingNotifications:
+		s.Notifications = !s.Notifications
+	default:
+		return ErrInvalidSetting
+	}
+
+	return nil
+}
+
+// cycle returns the option delta places after current, wrapping around.
+func cycle[T comparable](options []T, current T, delta int) T {
+	i := max(slices.Index(options, current), 0)
+	n := len(options)
+
+	return options[((i+delta)%n+n)%n]
+}
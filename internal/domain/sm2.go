@@ -11,11 +11,21 @@ const (
 	MaxGrade = 3
 )
 
+// lenientHardFactor grows the interval of a card recalled with difficulty
+// under SchedulerSM2Lenient.
+const lenientHardFactor = 1.2
+
 type SM2Input struct {
 	EF           float64
 	IntervalDays int
 	Repetition   int
 	Grade        int
+	// Slow is set for a recall that took long. Where slowGrade keeps its grade
+	// passing, the ease factor doesn't grow for it, so the intervals grow
+	// slower.
+	Slow bool
+	// Scheduler picks the SM-2 variant; empty means SchedulerSM2.
+	Scheduler Scheduler
 }
 
 type SM2Result struct {
@@ -28,9 +38,26 @@ type SM2Result struct {
 type ApplyReviewResultInput struct {
 	UserID     int64
 	DictWordID string
+	Direction  CardDirection
 	Grade      int
+	// Passed is whether the grade passes under the scheduler of the user; it's
+	// kept in the history, as the scheduler may change later.
+	Passed     bool
 	Result     *SM2Result
 	ReviewedAt time.Time
+	// Version is the version of the state the result is computed from; the
+	// result isn't stored if the state has changed since.
+	Version int
+}
+
+// PassingGrade is the lowest grade the scheduler counts as a successful
+// recall; a lower one doesn't grow the interval.
+func PassingGrade(scheduler Scheduler) int {
+	if scheduler == SchedulerSM2Lenient {
+		return MaxGrade - 1
+	}
+
+	return MaxGrade
 }
 
 func ComputeSM2(input *SM2Input, now time.Time) (*SM2Result, error) {
@@ -53,10 +80,11 @@ func ComputeSM2(input *SM2Input, now time.Time) (*SM2Result, error) {
 		interval = 0
 	}
 
-	if input.Grade < 3 {
-		repetition = 0
-		interval = 1
-	} else {
+	// under SchedulerSM2Lenient an easy recall counts as a success and a hard
+	// one keeps the progress; only a forgotten card starts over
+	lenient := input.Scheduler == SchedulerSM2Lenient
+	switch {
+	case input.Grade >= PassingGrade(input.Scheduler):
 		switch repetition {
 		case 0:
 			interval = 1
@@ -70,9 +98,18 @@ func ComputeSM2(input *SM2Input, now time.Time) (*SM2Result, error) {
 		}
 
 		repetition++
+	case lenient && input.Grade > MinGrade && repetition > 0:
+		interval = max(int(math.Round(float64(interval)*lenientHardFactor)), interval+1)
+	default:
+		repetition = 0
+		interval = 1
 	}
 
-	qualityDiff := float64(MaxGrade - input.Grade)
+	quality := input.Grade
+	if input.Slow {
+		quality = min(quality, MaxGrade-1)
+	}
+	qualityDiff := float64(MaxGrade - quality)
 	ef = ef + (0.1 - qualityDiff*(0.08+qualityDiff*0.02))
 	if ef < 1.3 {
 		ef = 1.3
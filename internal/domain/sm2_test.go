@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeSM2(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		input      SM2Input
+		interval   int
+		repetition int
+		ef         float64
+	}{
+		{
+			name:       "sm2 first success",
+			input:      SM2Input{EF: 2.5, Grade: MaxGrade},
+			interval:   1,
+			repetition: 1,
+			ef:         2.6,
+		},
+		{
+			name:       "sm2 second success",
+			input:      SM2Input{EF: 2.5, IntervalDays: 1, Repetition: 1, Grade: MaxGrade},
+			interval:   6,
+			repetition: 2,
+			ef:         2.6,
+		},
+		{
+			name:       "sm2 later success grows by ef",
+			input:      SM2Input{EF: 2.5, IntervalDays: 6, Repetition: 2, Grade: MaxGrade},
+			interval:   15,
+			repetition: 3,
+			ef:         2.6,
+		},
+		{
+			name:       "sm2 hard recall starts over",
+			input:      SM2Input{EF: 2.5, IntervalDays: 6, Repetition: 2, Grade: MaxGrade - 1},
+			interval:   1,
+			repetition: 0,
+			ef:         2.5,
+		},
+		{
+			name:       "lenient hard recall passes",
+			input:      SM2Input{EF: 2.5, IntervalDays: 6, Repetition: 2, Grade: MaxGrade - 1, Scheduler: SchedulerSM2Lenient},
+			interval:   15,
+			repetition: 3,
+			ef:         2.5,
+		},
+		{
+			name: "lenient weak recall keeps progress",
+			input: SM2Input{
+				EF: 2.5, IntervalDays: 10, Repetition: 3, Grade: MinGrade + 1, Scheduler: SchedulerSM2Lenient,
+			},
+			interval:   12,
+			repetition: 3,
+			ef:         2.36,
+		},
+		{
+			name: "lenient weak recall grows by a day at least",
+			input: SM2Input{
+				EF: 2.5, IntervalDays: 1, Repetition: 1, Grade: MinGrade + 1, Scheduler: SchedulerSM2Lenient,
+			},
+			interval:   2,
+			repetition: 1,
+			ef:         2.36,
+		},
+		{
+			name:       "lenient weak recall of a new card starts over",
+			input:      SM2Input{EF: 2.5, Grade: MinGrade + 1, Scheduler: SchedulerSM2Lenient},
+			interval:   1,
+			repetition: 0,
+			ef:         2.36,
+		},
+		{
+			name:       "lenient forgotten starts over",
+			input:      SM2Input{EF: 2.5, IntervalDays: 10, Repetition: 3, Grade: MinGrade, Scheduler: SchedulerSM2Lenient},
+			interval:   1,
+			repetition: 0,
+			ef:         2.18,
+		},
+		{
+			name:       "sm2 slow success holds ef",
+			input:      SM2Input{EF: 2.5, IntervalDays: 6, Repetition: 2, Grade: MaxGrade, Slow: true},
+			interval:   15,
+			repetition: 3,
+			ef:         2.5,
+		},
+		{
+			name: "lenient slow grade isn't lowered twice",
+			input: SM2Input{
+				EF: 2.5, IntervalDays: 6, Repetition: 2, Grade: MaxGrade - 1, Slow: true, Scheduler: SchedulerSM2Lenient,
+			},
+			interval:   15,
+			repetition: 3,
+			ef:         2.5,
+		},
+		{
+			name:       "slow failure as any failure",
+			input:      SM2Input{EF: 2.5, IntervalDays: 6, Repetition: 2, Grade: MinGrade, Slow: true},
+			interval:   1,
+			repetition: 0,
+			ef:         2.18,
+		},
+		{
+			name:       "ef floor",
+			input:      SM2Input{EF: 1.3, IntervalDays: 10, Repetition: 3, Grade: MinGrade},
+			interval:   1,
+			repetition: 0,
+			ef:         1.3,
+		},
+		{
+			name:       "unset ef defaults",
+			input:      SM2Input{Grade: MaxGrade},
+			interval:   1,
+			repetition: 1,
+			ef:         2.6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ComputeSM2(&tt.input, now)
+			if err != nil {
+				t.Fatalf("ComputeSM2: %v", err)
+			}
+			if res.IntervalDays != tt.interval || res.Repetition != tt.repetition ||
+				math.Abs(res.EF-tt.ef) > 1e-9 {
+				t.Errorf("ComputeSM2 = interval %d, repetition %d, ef %v; want %d, %d, %v",
+					res.IntervalDays, res.Repetition, res.EF, tt.interval, tt.repetition, tt.ef)
+			}
+			if want := now.AddDate(0, 0, tt.interval); !res.NextReviewAt.Equal(want) {
+				t.Errorf("next review at %v, want %v", res.NextReviewAt, want)
+			}
+		})
+	}
+}
+
+func TestComputeSM2InvalidGrade(t *testing.T) {
+	for _, grade := range []int{MinGrade - 1, MaxGrade + 1} {
+		if _, err := ComputeSM2(&SM2Input{Grade: grade}, time.Now()); !errors.Is(err, ErrInvalidReviewGrade) {
+			t.Errorf("ComputeSM2 with grade %d: err = %v, want ErrInvalidReviewGrade", grade, err)
+		}
+	}
+	if _, err := ComputeSM2(nil, time.Now()); err == nil {
+		t.Error("ComputeSM2(nil) succeeded")
+	}
+}
+
+func TestPassingGrade(t *testing.T) {
+	tests := []struct {
+		scheduler Scheduler
+		want      int
+	}{
+		{SchedulerSM2, MaxGrade},
+		{"", MaxGrade},
+		{SchedulerSM2Lenient, MaxGrade - 1},
+	}
+
+	for _, tt := range tests {
+		if got := PassingGrade(tt.scheduler); got != tt.want {
+			t.Errorf("PassingGrade(%q) = %d, want %d", tt.scheduler, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+const (
+	// StatsDays is how many days the review chart and the due forecast
+	// cover.
+	StatsDays = 30
+	// RetentionShortDays is the short window of the retention rate; the long
+	// one is StatsDays.
+	RetentionShortDays = 7
+)
+
+// DayCount is a count of a local day.
+type DayCount struct {
+	// Day is the local date, at midnight UTC.
+	Day   time.Time
+	Count int
+}
+
+// Retention is how many of the grades were recalls, i.e. passed under the
+// scheduler of the user at the time, see PassingGrade.
+type Retention struct {
+	Reviews  int
+	Recalled int
+}
+
+// Rate is the share of recalls, 0 without grades.
+func (r Retention) Rate() float64 {
+	if r.Reviews == 0 {
+		return 0
+	}
+
+	return float64(r.Recalled) / float64(r.Reviews)
+}
+
+// UserStats is the progress of a user in a subscribed dictionary or in all
+// of them.
+type UserStats struct {
+	// DictionaryTitle is empty for the stats of all dictionaries.
+	DictionaryTitle string
+	// WordsByStatus counts the tracked words by status.
+	WordsByStatus  map[UserWordStatus]int
+	RetentionShort Retention
+	RetentionLong  Retention
+	// ReviewsPerDay are the grades of the last StatsDays local days, today
+	// last.
+	ReviewsPerDay []DayCount
+	// DueForecast are the words due on each of the next StatsDays local
+	// days, today first; the overdue ones count for today.
+	DueForecast []DayCount
+}
+
+// FillDays lays the counts out on the n days from start, zero for the
+// missing ones.
+func FillDays(counts []DayCount, start time.Time, n int) []DayCount {
+	byDay := make(map[string]int, len(counts))
+	for _, c := range counts {
+		byDay[c.Day.Format(time.DateOnly)] += c.Count
+	}
+
+	days := make([]DayCount, 0, n)
+	for i := range n {
+		day := start.AddDate(0, 0, i)
+		days = append(days, DayCount{Day: day, Count: byDay[day.Format(time.DateOnly)]})
+	}
+
+	return days
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFillDays(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC) }
+
+	counts := []DayCount{
+		{Day: day(27), Count: 2},
+		{Day: day(28), Count: 1},
+		{Day: day(28), Count: 4},
+		{Day: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Count: 3},
+		// out of the range
+		{Day: day(20), Count: 9},
+		{Day: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), Count: 9},
+	}
+
+	got := FillDays(counts, day(27), 4)
+	want := []int{2, 5, 0, 3}
+	if len(got) != len(want) {
+		t.Fatalf("FillDays = %d days, want %d", len(got), len(want))
+	}
+	for i, count := range want {
+		wantDay := day(27).AddDate(0, 0, i)
+		if !got[i].Day.Equal(wantDay) || got[i].Count != count {
+			t.Errorf("day %d = %s: %d, want %s: %d",
+				i, got[i].Day.Format(time.DateOnly), got[i].Count, wantDay.Format(time.DateOnly), count)
+		}
+	}
+
+	if days := FillDays(nil, day(1), 3); len(days) != 3 || days[0].Count != 0 || days[2].Count != 0 {
+		t.Errorf("FillDays without counts = %+v, want 3 empty days", days)
+	}
+}
+
+func TestRetentionRate(t *testing.T) {
+	tests := []struct {
+		retention Retention
+		want      float64
+	}{
+		{Retention{}, 0},
+		{Retention{Reviews: 4, Recalled: 3}, 0.75},
+		{Retention{Reviews: 2, Recalled: 2}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.retention.Rate(); got != tt.want {
+			t.Errorf("%+v.Rate() = %v, want %v", tt.retention, got, tt.want)
+		}
+	}
+}
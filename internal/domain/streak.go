@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// GoalKind is what the daily goal counts.
+type GoalKind string
+
+const (
+	GoalKindReviews  GoalKind = "reviews"
+	GoalKindNewWords GoalKind = "new_words"
+)
+
+// GoalKinds are the goal kinds to choose from, in menu order.
+var GoalKinds = []GoalKind{GoalKindReviews, GoalKindNewWords}
+
+func ParseGoalKind(raw string) (GoalKind, bool) {
+	if slices.Contains(GoalKinds, GoalKind(raw)) {
+		return GoalKind(raw), true
+	}
+
+	return "", false
+}
+
+// Bounds and step of the daily goal.
+const (
+	DailyGoalMin  = 5
+	DailyGoalMax  = 500
+	DailyGoalStep = 5
+)
+
+const (
+	// StreakFreezeEvery is how many goal days in a row earn a streak freeze.
+	StreakFreezeEvery = 7
+	// MaxStreakFreezes is how many streak freezes can be kept at once.
+	MaxStreakFreezes = 2
+	// ActivityWeeks is how many weeks the activity calendar covers, the
+	// current one included. Streaks are not tracked beyond it.
+	ActivityWeeks = 53
+)
+
+// DailyGoal is how many grades or new words a local day needs to count
+// towards the streak.
+type DailyGoal struct {
+	Kind   GoalKind
+	Target int
+}
+
+// Progress is how much of the goal is done on the day.
+func (g DailyGoal) Progress(a DayActivity) int {
+	if g.Kind == GoalKindNewWords {
+		return a.NewWords
+	}
+
+	return a.Reviews
+}
+
+func (g DailyGoal) Met(a DayActivity) bool {
+	return g.Progress(a) >= g.Target
+}
+
+// DayActivity is what the user did on a local day.
+type DayActivity struct {
+	// Day is the local date, at midnight UTC.
+	Day      time.Time
+	Reviews  int
+	NewWords int
+	// Frozen is set if a streak freeze covered the day.
+	Frozen bool
+}
+
+// LocalDate returns the date t falls on in loc, at midnight UTC.
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// DateStart is the moment the local date starts in loc.
+func DateStart(date time.Time, loc *time.Location) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// ActivityCalendarStart is the first day of the activity calendar ending on
+// today: the Monday ActivityWeeks-1 weeks before the current week's one.
+func ActivityCalendarStart(today time.Time) time.Time {
+	weekday := (int(today.Weekday()) + 6) % 7
+
+	return today.AddDate(0, 0, -weekday-(ActivityWeeks-1)*7)
+}
+
+// ActivityLog is the activity of a user by local date; days without any are
+// missing.
+type ActivityLog map[string]DayActivity
+
+func NewActivityLog(days []DayActivity) ActivityLog {
+	log := make(ActivityLog, len(days))
+	for _, day := range days {
+		log[day.Day.Format(time.DateOnly)] = day
+	}
+
+	return log
+}
+
+// On returns the activity of the date, empty if there was none.
+func (l ActivityLog) On(date time.Time) DayActivity {
+	day, ok := l[date.Format(time.DateOnly)]
+	if !ok {
+		return DayActivity{Day: date}
+	}
+
+	return day
+}
+
+// Freeze marks the dates as covered by a streak freeze.
+func (l ActivityLog) Freeze(dates []time.Time) {
+	for _, date := range dates {
+		day := l.On(date)
+		day.Frozen = true
+		l[date.Format(time.DateOnly)] = day
+	}
+}
+
+// StreakState is the streak freezes of a user.
+type StreakState struct {
+	// Freezes is how many streak freezes the user has.
+	Freezes int
+	// SettledThrough is the last day the freezes were settled for, nil if
+	// they never were.
+	SettledThrough *time.Time
+}
+
+// StreakSettlement is the streak state after settling the finished days.
+type StreakSettlement struct {
+	Freezes        int
+	SettledThrough time.Time
+	// Frozen are the days newly covered by a freeze.
+	Frozen []time.Time
+}
+
+// SettleStreak goes over the finished days since the last settlement, the
+// calendar window and yesterday at most: a goal day extends the streak and
+// every StreakFreezeEvery goal days in a row earn a freeze, up to
+// MaxStreakFreezes; a missed day of a running streak uses a freeze up if
+// useFreezes is set and breaks the streak otherwise. The first settlement
+// only covers yesterday.
+func SettleStreak(
+	log ActivityLog,
+	goal DailyGoal,
+	state StreakState,
+	useFreezes bool,
+	today time.Time,
+) StreakSettlement {
+	yesterday := today.AddDate(0, 0, -1)
+	windowStart := ActivityCalendarStart(today)
+
+	from := yesterday
+	if state.SettledThrough != nil {
+		from = state.SettledThrough.AddDate(0, 0, 1)
+	}
+	if from.Before(windowStart) {
+		from = windowStart
+	}
+
+	settlement := StreakSettlement{
+		Freezes:        state.Freezes,
+		SettledThrough: yesterday,
+	}
+
+	run := streakEndingOn(log, goal, from.AddDate(0, 0, -1), windowStart)
+	for day := from; !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+		activity := log.On(day)
+		switch {
+		case goal.Met(activity):
+			run++
+			if run%StreakFreezeEvery == 0 && settlement.Freezes < MaxStreakFreezes {
+				settlement.Freezes++
+			}
+		case activity.Frozen:
+		case useFreezes && run > 0 && settlement.Freezes > 0:
+			settlement.Freezes--
+			settlement.Frozen = append(settlement.Frozen, day)
+		default:
+			run = 0
+		}
+	}
+
+	return settlement
+}
+
+// StreakStats is the streak of a user and the activity calendar.
+type StreakStats struct {
+	Goal  DailyGoal
+	Today DayActivity
+	// Current counts the goal days of the running streak; today counts once
+	// its goal is met, but the streak isn't broken before the day is over.
+	Current int
+	// Longest is the longest streak within the calendar.
+	Longest int
+	Freezes int
+	// Days are the days of the calendar from ActivityCalendarStart to today.
+	Days []DayActivity
+}
+
+// ComputeStreak counts the streaks in the log. Days covered by a freeze keep
+// a streak going but don't add to it.
+func ComputeStreak(log ActivityLog, goal DailyGoal, today time.Time, freezes int) *StreakStats {
+	start := ActivityCalendarStart(today)
+
+	stats := &StreakStats{
+		Goal:    goal,
+		Today:   log.On(today),
+		Freezes: freezes,
+	}
+
+	run := 0
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		activity := log.On(day)
+		stats.Days = append(stats.Days, activity)
+
+		switch {
+		case goal.Met(activity):
+			run++
+		case activity.Frozen, day.Equal(today):
+		default:
+			run = 0
+		}
+		stats.Longest = max(stats.Longest, run)
+	}
+	stats.Current = run
+
+	return stats
+}
+
+// streakEndingOn counts the goal days of the streak running on the date,
+// looking no further back than start.
+func streakEndingOn(log ActivityLog, goal DailyGoal, date, start time.Time) int {
+	run := 0
+	for day := date; !day.Before(start); day = day.AddDate(0, 0, -1) {
+		activity := log.On(day)
+		switch {
+		case goal.Met(activity):
+			run++
+		case activity.Frozen:
+		default:
+			return run
+		}
+	}
+
+	return run
+}
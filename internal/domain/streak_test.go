@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// streakToday is a Tuesday.
+var streakToday = time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+var streakGoal = DailyGoal{Kind: GoalKindReviews, Target: 5}
+
+// streakLog builds the log of the days ending on streakToday from a pattern,
+// one character a day: 'x' meets the goal, 'f' is frozen and '.' falls short.
+func streakLog(pattern string) ActivityLog {
+	days := make([]DayActivity, 0, len(pattern))
+	for i, c := range pattern {
+		day := DayActivity{Day: daysAgo(len(pattern) - 1 - i)}
+		switch c {
+		case 'x':
+			day.Reviews = streakGoal.Target
+		case 'f':
+			day.Frozen = true
+		default:
+			day.Reviews = streakGoal.Target - 1
+		}
+		days = append(days, day)
+	}
+
+	return NewActivityLog(days)
+}
+
+func daysAgo(n int) time.Time {
+	return streakToday.AddDate(0, 0, -n)
+}
+
+func TestDailyGoalMet(t *testing.T) {
+	activity := DayActivity{Reviews: 10, NewWords: 3}
+
+	tests := []struct {
+		goal DailyGoal
+		want bool
+	}{
+		{DailyGoal{Kind: GoalKindReviews, Target: 10}, true},
+		{DailyGoal{Kind: GoalKindReviews, Target: 11}, false},
+		{DailyGoal{Kind: GoalKindNewWords, Target: 3}, true},
+		{DailyGoal{Kind: GoalKindNewWords, Target: 5}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.goal.Met(activity); got != tt.want {
+			t.Errorf("%+v.Met(%+v) = %v, want %v", tt.goal, activity, got, tt.want)
+		}
+	}
+}
+
+func TestComputeStreak(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		current int
+		longest int
+	}{
+		{"no activity", "", 0, 0},
+		{"today not done yet", "xxx.", 3, 3},
+		{"today done", "xxxx", 4, 4},
+		{"broken before", "xxx.xx", 2, 3},
+		{"broken yesterday", "xxx..", 0, 3},
+		{"freeze keeps the streak", "xxfx.", 3, 3},
+		{"freeze doesn't add", "ffx", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := ComputeStreak(streakLog(tt.pattern), streakGoal, streakToday, 1)
+			if stats.Current != tt.current || stats.Longest != tt.longest {
+				t.Errorf("ComputeStreak(%q) = current %d, longest %d; want %d, %d",
+					tt.pattern, stats.Current, stats.Longest, tt.current, tt.longest)
+			}
+			if stats.Freezes != 1 {
+				t.Errorf("freezes = %d, want 1", stats.Freezes)
+			}
+
+			// The current week is cut at Tuesday.
+			first := ActivityCalendarStart(streakToday)
+			if len(stats.Days) != ActivityWeeks*7-5 || !stats.Days[0].Day.Equal(first) ||
+				!stats.Days[len(stats.Days)-1].Day.Equal(streakToday) {
+				t.Errorf("calendar of %d days from %v, want %d days from %v to %v",
+					len(stats.Days), stats.Days[0].Day, ActivityWeeks*7-5, first, streakToday)
+			}
+		})
+	}
+}
+
+func TestActivityCalendarStart(t *testing.T) {
+	start := ActivityCalendarStart(streakToday)
+
+	if start.Weekday() != time.Monday {
+		t.Errorf("calendar starts on %v, want Monday", start.Weekday())
+	}
+	if want := daysAgo(1 + (ActivityWeeks-1)*7); !start.Equal(want) {
+		t.Errorf("calendar starts on %v, want %v", start, want)
+	}
+}
+
+func TestSettleStreak(t *testing.T) {
+	at := func(n int) *time.Time {
+		d := daysAgo(n)
+		return &d
+	}
+
+	tests := []struct {
+		name       string
+		pattern    string
+		state      StreakState
+		useFreezes bool
+		freezes    int
+		frozen     []time.Time
+	}{
+		{
+			name:    "week of goals earns a freeze",
+			pattern: "xxxxxxx.",
+			freezes: 1,
+		},
+		{
+			name:    "freezes are capped",
+			pattern: "xxxxxxx.",
+			state:   StreakState{Freezes: MaxStreakFreezes},
+			freezes: MaxStreakFreezes,
+		},
+		{
+			name:    "week with a frozen day earns nothing",
+			pattern: "xxxfxxx.",
+			freezes: 0,
+		},
+		{
+			name:       "missed day uses a freeze",
+			pattern:    "xxx..",
+			state:      StreakState{Freezes: 1},
+			useFreezes: true,
+			freezes:    0,
+			frozen:     []time.Time{daysAgo(1)},
+		},
+		{
+			name:    "freezes not used when turned off",
+			pattern: "xxx..",
+			state:   StreakState{Freezes: 1},
+			freezes: 1,
+		},
+		{
+			name:       "no streak to keep",
+			pattern:    "..",
+			state:      StreakState{Freezes: 1},
+			useFreezes: true,
+			freezes:    1,
+		},
+		{
+			name:       "days since the last settlement",
+			pattern:    "xxx...",
+			state:      StreakState{Freezes: 1, SettledThrough: at(3)},
+			useFreezes: true,
+			freezes:    0,
+			frozen:     []time.Time{daysAgo(2)},
+		},
+		{
+			name:       "settled already",
+			pattern:    "xxx..",
+			state:      StreakState{Freezes: 1, SettledThrough: at(1)},
+			useFreezes: true,
+			freezes:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := SettleStreak(streakLog(tt.pattern), streakGoal, tt.state, tt.useFreezes, streakToday)
+			if s.Freezes != tt.freezes || !slices.EqualFunc(s.Frozen, tt.frozen, time.Time.Equal) {
+				t.Errorf("SettleStreak(%q) = freezes %d, frozen %v; want %d, %v",
+					tt.pattern, s.Freezes, s.Frozen, tt.freezes, tt.frozen)
+			}
+			if !s.SettledThrough.Equal(daysAgo(1)) {
+				t.Errorf("settled through %v, want yesterday", s.SettledThrough)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TypedAnswerFastLimit is the response time under which a correct typed
+// answer gets the top grade.
+const TypedAnswerFastLimit = 10 * time.Second
+
+type DiffOpKind int
+
+const (
+	DiffEqual DiffOpKind = iota
+	// DiffSubstitute means Actual was typed instead of Expected.
+	DiffSubstitute
+	// DiffMissing means Expected was not typed.
+	DiffMissing
+	// DiffExtra means Actual was typed but not expected.
+	DiffExtra
+)
+
+type DiffOp struct {
+	Kind     DiffOpKind
+	Expected rune
+	Actual   rune
+}
+
+type TypedAnswerResult struct {
+	Word *ReviewWord
+	// Expected is the normalized expected answer.
+	Expected string
+	Answer   string
+	Grade    int
+	Distance int
+	// Correct is true for an exact match or an accepted spelling variant, and
+	// for another form of the word in cloze mode if its grade passes.
+	Correct bool
+	// Variant is the accepted spelling the answer matched, if it differs from
+	// the dictionary spelling.
+	Variant string
+	// WrongForm is set in cloze mode when another form of the word was typed.
+	WrongForm bool
+	Slow      bool
+	// Diff aligns the normalized answer with the closest accepted spelling.
+	Diff []DiffOp
+}
+
+// GradeTypedAnswer grades a typed answer against the expected spelling:
+//   - exact match (after case/article normalization) or accepted spelling
+//     variant: MaxGrade, lowered by slowGrade if not answered faster than
+//     TypedAnswerFastLimit;
+//   - a few typos (see maxTypos): 1;
+//   - anything else: MinGrade.
+func GradeTypedAnswer(expected, answer string, elapsed time.Duration, scheduler Scheduler) *TypedAnswerResult {
+	normExpected := normalizeAnswer(expected)
+	normAnswer := normalizeAnswer(answer)
+
+	best := normExpected
+	bestDist := levenshtein([]rune(normAnswer), []rune(normExpected))
+	for _, v := range SpellingVariants(normExpected) {
+		if d := levenshtein([]rune(normAnswer), []rune(v)); d < bestDist {
+			best, bestDist = v, d
+		}
+	}
+
+	res := &TypedAnswerResult{
+		Expected: normExpected,
+		Answer:   answer,
+		Distance: bestDist,
+		Diff:     diffRunes([]rune(best), []rune(normAnswer)),
+	}
+	if best != normExpected {
+		res.Variant = best
+	}
+
+	switch {
+	case normAnswer == "":
+		res.Grade = MinGrade
+	case bestDist == 0:
+		res.Correct = true
+		res.Slow = elapsed > TypedAnswerFastLimit
+		res.Grade = MaxGrade
+		if res.Slow {
+			res.Grade = slowGrade(res.Grade, scheduler)
+		}
+	case bestDist <= maxTypos(len([]rune(best))):
+		res.Grade = MinGrade + 1
+	default:
+		res.Grade = MinGrade
+	}
+
+	return res
+}
+
+// slowGrade lowers the grade of a slow answer by one, unless the card would
+// no longer pass under the scheduler: a correct answer must not start the
+// card over just for taking long. The slowness then holds back the ease
+// factor instead, see SM2Input.Slow.
+func slowGrade(grade int, scheduler Scheduler) int {
+	if grade-1 < PassingGrade(scheduler) {
+		return grade
+	}
+
+	return grade - 1
+}
+
+// maxTypos is the number of edits still treated as a typo rather than a wrong
+// word. Short words get no tolerance: "cat" vs "cut" is a different word.
+func maxTypos(length int) int {
+	switch {
+	case length <= 3:
+		return 0
+	case length <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+var leadingArticles = []string{"a ", "an ", "the ", "to "}
+
+// normalizeAnswer lowercases, collapses whitespace, unifies apostrophes and
+// drops a leading article ("the", "a", "an", "to") and trailing punctuation.
+func normalizeAnswer(s string) string {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	s = strings.NewReplacer("’", "'", "`", "'").Replace(s)
+	s = strings.TrimRightFunc(s, func(r rune) bool {
+		return unicode.IsPunct(r) && r != '\''
+	})
+
+	for _, a := range leadingArticles {
+		if strings.HasPrefix(s, a) && len(s) > len(a) {
+			s = s[len(a):]
+			break
+		}
+	}
+
+	return strings.TrimSpace(s)
+}
+
+// spellingPairs are irregular British/American spellings.
+var spellingPairs = map[string]string{
+	"grey":      "gray",
+	"tyre":      "tire",
+	"cheque":    "check",
+	"programme": "program",
+	"aluminium": "aluminum",
+	"mum":       "mom",
+	"plough":    "plow",
+	"jewellery": "jewelry",
+	"pyjamas":   "pajamas",
+	"defence":   "defense",
+	"offence":   "offense",
+	"licence":   "license",
+	"pretence":  "pretense",
+	"travelled": "traveled",
+	"traveller": "traveler",
+	"cancelled": "canceled",
+	"modelling": "modeling",
+	"enrol":     "enroll",
+	"fulfil":    "fulfill",
+	"skilful":   "skillful",
+	"manoeuvre": "maneuver",
+	"ageing":    "aging",
+	"draught":   "draft",
+	"kerb":      "curb",
+	"sceptic":   "skeptic",
+}
+
+// spellingSuffixRules are regular British/American suffix alternations. The
+// stem must be long enough not to turn "four" into "for". Rules that would
+// accept too many wrong words the other way round ("water" -> "watre") only
+// map British to American.
+var spellingSuffixRules = []struct {
+	british, american string
+	minStem           int
+	bothWays          bool
+}{
+	{"our", "or", 3, false},
+	{"ours", "ors", 3, false},
+	{"oured", "ored", 3, false},
+	{"ouring", "oring", 3, false},
+	{"ourite", "orite", 3, false},
+	{"ourful", "orful", 3, false},
+	{"tre", "ter", 2, false},
+	{"tres", "ters", 2, false},
+	{"elling", "eling", 2, false},
+	{"elled", "eled", 2, false},
+	{"ise", "ize", 3, true},
+	{"ised", "ized", 3, true},
+	{"ising", "izing", 3, true},
+	{"isation", "ization", 3, true},
+	{"yse", "yze", 3, true},
+	{"ogue", "og", 3, true},
+	{"ogues", "ogs", 3, true},
+}
+
+// SpellingVariants returns accepted alternative spellings of a normalized
+// word, e.g. "color" for "colour" and vice versa.
+func SpellingVariants(word string) []string {
+	variants := make([]string, 0, 2)
+	add := func(v string) {
+		if v == word {
+			return
+		}
+		for _, existing := range variants {
+			if existing == v {
+				return
+			}
+		}
+		variants = append(variants, v)
+	}
+
+	for british, american := range spellingPairs {
+		switch word {
+		case british:
+			add(american)
+		case american:
+			add(british)
+		}
+	}
+
+	for _, r := range spellingSuffixRules {
+		if stem, ok := strings.CutSuffix(word, r.british); ok && len([]rune(stem)) >= r.minStem {
+			add(stem + r.american)
+		}
+		if !r.bothWays {
+			continue
+		}
+		if stem, ok := strings.CutSuffix(word, r.american); ok && len([]rune(stem)) >= r.minStem {
+			add(stem + r.british)
+		}
+	}
+
+	return variants
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+// diffRunes aligns actual against expected with a Levenshtein backtrace.
+func diffRunes(expected, actual []rune) []DiffOp {
+	n, m := len(expected), len(actual)
+	dist := make([][]int, n+1)
+	for i := range dist {
+		dist[i] = make([]int, m+1)
+		dist[i][0] = i
+	}
+	for j := 0; j <= m; j++ {
+		dist[0][j] = j
+	}
+
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			cost := 1
+			if expected[i-1] == actual[j-1] {
+				cost = 0
+			}
+			dist[i][j] = min(dist[i-1][j]+1, dist[i][j-1]+1, dist[i-1][j-1]+cost)
+		}
+	}
+
+	ops := make([]DiffOp, 0, max(n, m))
+	i, j := n, m
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && expected[i-1] == actual[j-1] && dist[i][j] == dist[i-1][j-1]:
+			ops = append(ops, DiffOp{Kind: DiffEqual, Expected: expected[i-1], Actual: actual[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && dist[i][j] == dist[i-1][j-1]+1:
+			ops = append(ops, DiffOp{Kind: DiffSubstitute, Expected: expected[i-1], Actual: actual[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && dist[i][j] == dist[i-1][j]+1:
+			ops = append(ops, DiffOp{Kind: DiffMissing, Expected: expected[i-1]})
+			i--
+		default:
+			ops = append(ops, DiffOp{Kind: DiffExtra, Actual: actual[j-1]})
+			j--
+		}
+	}
+
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+
+	return ops
+}
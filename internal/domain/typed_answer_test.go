@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGradeTypedAnswer(t *testing.T) {
+	const fast, slow = time.Second, TypedAnswerFastLimit + time.Second
+
+	tests := []struct {
+		name      string
+		expected  string
+		answer    string
+		elapsed   time.Duration
+		scheduler Scheduler
+		grade     int
+		correct   bool
+		variant   string
+	}{
+		{"exact", "apple", "apple", fast, SchedulerSM2, MaxGrade, true, ""},
+		{"case and spaces", "get up", "  Get   UP ", fast, SchedulerSM2, MaxGrade, true, ""},
+		{"article dropped", "the apple", "apple", fast, SchedulerSM2, MaxGrade, true, ""},
+		{"trailing punctuation", "apple", "apple!", fast, SchedulerSM2, MaxGrade, true, ""},
+		{"curly apostrophe", "don't", "don’t", fast, SchedulerSM2, MaxGrade, true, ""},
+		{"spelling variant", "colour", "color", fast, SchedulerSM2, MaxGrade, true, "color"},
+		{"slow sm2 stays passing", "apple", "apple", slow, SchedulerSM2, MaxGrade, true, ""},
+		{"slow lenient lowered", "apple", "apple", slow, SchedulerSM2Lenient, MaxGrade - 1, true, ""},
+		{"typo", "banana", "banan", fast, SchedulerSM2, MinGrade + 1, false, ""},
+		{"two typos in long word", "beautiful", "beatiful", fast, SchedulerSM2, MinGrade + 1, false, ""},
+		{"short word no typos", "cat", "cut", fast, SchedulerSM2, MinGrade, false, ""},
+		{"wrong word", "apple", "orange", fast, SchedulerSM2, MinGrade, false, ""},
+		{"empty", "apple", "  ", fast, SchedulerSM2, MinGrade, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := GradeTypedAnswer(tt.expected, tt.answer, tt.elapsed, tt.scheduler)
+			if res.Grade != tt.grade || res.Correct != tt.correct || res.Variant != tt.variant {
+				t.Errorf("GradeTypedAnswer(%q, %q) = grade %d, correct %v, variant %q; want %d, %v, %q",
+					tt.expected, tt.answer, res.Grade, res.Correct, res.Variant, tt.grade, tt.correct, tt.variant)
+			}
+			if res.Correct && res.Grade < PassingGrade(tt.scheduler) {
+				t.Errorf("correct answer graded %d, below passing %d", res.Grade, PassingGrade(tt.scheduler))
+			}
+		})
+	}
+}
+
+func TestGradeTypedAnswerDiff(t *testing.T) {
+	res := GradeTypedAnswer("house", "hose", time.Second, SchedulerSM2)
+
+	want := []DiffOpKind{DiffEqual, DiffEqual, DiffMissing, DiffEqual, DiffEqual}
+	got := make([]DiffOpKind, 0, len(res.Diff))
+	for _, op := range res.Diff {
+		got = append(got, op.Kind)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("diff kinds = %v, want %v", got, want)
+	}
+}
+
+func TestSpellingVariants(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"colour", []string{"color"}},
+		{"favourite", []string{"favorite"}},
+		{"centre", []string{"center"}},
+		{"travelling", []string{"traveling"}},
+		{"organise", []string{"organize"}},
+		{"organize", []string{"organise"}},
+		{"analyse", []string{"analyze"}},
+		{"catalogue", []string{"catalog"}},
+		{"grey", []string{"gray"}},
+		{"gray", []string{"grey"}},
+		{"defence", []string{"defense"}},
+		// Too short a stem or a one-way rule.
+		{"four", nil},
+		{"hour", nil},
+		{"color", nil},
+		{"water", nil},
+		{"apple", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			got := SpellingVariants(tt.word)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) && !(len(got) == 0 && len(tt.want) == 0) {
+				t.Errorf("SpellingVariants(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
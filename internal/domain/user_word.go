@@ -0,0 +1,110 @@
+package domain
+
+import "time"
+
+// UserWord is a word of a subscribed dictionary with the user's progress on
+// it.
+type UserWord struct {
+	ID              string
+	DictionaryID    string
+	DictionaryTitle string
+	Spelling        string
+	Transcription   string
+	RUTranslation   string
+	// Status is empty for a word the user hasn't started learning.
+	Status UserWordStatus
+	// Cards are the scheduling states of the card directions of the word.
+	Cards []WordCardState
+}
+
+// WordCardState is the scheduling state of one card direction of a word.
+type WordCardState struct {
+	Direction    CardDirection
+	EF           float64
+	IntervalDays int
+	Repetition   int
+	LastResult   *int
+	LastReviewAt *time.Time
+	NextReviewAt *time.Time
+	// History is the latest grades of the card, oldest first.
+	History []ReviewHistoryEntry
+}
+
+// WordHistoryLimit is how many latest grades of a card are shown.
+const WordHistoryLimit = 10
+
+// WordSetAsideLimit is how many hidden and paused words are listed to pick a
+// word card from.
+const WordSetAsideLimit = 30
+
+// ReviewHistoryEntry is a grade of a card with the state it led to.
+type ReviewHistoryEntry struct {
+	Direction    CardDirection
+	Grade        int
+	EF           float64
+	IntervalDays int
+	ReviewedAt   time.Time
+}
+
+// AttachHistory distributes the history entries of the word among its cards.
+func (w *UserWord) AttachHistory(history []ReviewHistoryEntry) {
+	for i := range w.Cards {
+		card := &w.Cards[i]
+		card.History = card.History[:0]
+		for _, entry := range history {
+			if entry.Direction == card.Direction {
+				card.History = append(card.History, entry)
+			}
+		}
+	}
+}
+
+// WordAction is a manual change of the user's progress on a word.
+type WordAction string
+
+const (
+	// WordActionUnblock brings a blocked word back to learning from scratch.
+	WordActionUnblock WordAction = "unblock"
+	// WordActionReset starts learning the word from scratch.
+	WordActionReset WordAction = "reset"
+	// WordActionSuspend hides the word from review for Arg days.
+	WordActionSuspend WordAction = "suspend"
+	// WordActionResume brings a suspended word back to review right away.
+	WordActionResume WordAction = "resume"
+	// WordActionTomorrow makes the word due at the start of the next day.
+	WordActionTomorrow WordAction = "tomorrow"
+	// WordActionKnown schedules the word Arg months ahead, as if it had been
+	// recalled with that interval.
+	WordActionKnown WordAction = "known"
+)
+
+// WordSuspendDays and WordKnownMonths are the choices of Arg offered for
+// WordActionSuspend and WordActionKnown.
+var (
+	WordSuspendDays = []int{7, 30, 90}
+	WordKnownMonths = []int{1, 3, 6, 12}
+)
+
+func ParseWordAction(raw string) (WordAction, bool) {
+	switch WordAction(raw) {
+	case WordActionUnblock, WordActionReset, WordActionSuspend, WordActionResume, WordActionTomorrow,
+		WordActionKnown:
+		return WordAction(raw), true
+	default:
+		return "", false
+	}
+}
+
+// Actions returns the actions that apply to a word of the status.
+func (s UserWordStatus) Actions() []WordAction {
+	switch s {
+	case UserWordStatusLearning:
+		return []WordAction{WordActionTomorrow, WordActionReset, WordActionSuspend, WordActionKnown}
+	case UserWordStatusSuspended:
+		return []WordAction{WordActionResume, WordActionReset, WordActionSuspend, WordActionKnown}
+	case UserWordStatusBlocked:
+		return []WordAction{WordActionUnblock}
+	default:
+		return nil
+	}
+}
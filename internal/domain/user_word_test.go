@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseWordAction(t *testing.T) {
+	for _, action := range []WordAction{
+		WordActionUnblock, WordActionReset, WordActionSuspend, WordActionResume, WordActionTomorrow, WordActionKnown,
+	} {
+		if got, ok := ParseWordAction(string(action)); !ok || got != action {
+			t.Errorf("ParseWordAction(%q) = %q, %v; want %q, true", action, got, ok, action)
+		}
+	}
+
+	for _, raw := range []string{"", "delete", "Reset"} {
+		if got, ok := ParseWordAction(raw); ok {
+			t.Errorf("ParseWordAction(%q) = %q, true; want false", raw, got)
+		}
+	}
+}
+
+func TestUserWordStatusActions(t *testing.T) {
+	tests := []struct {
+		status UserWordStatus
+		has    []WordAction
+		hasNot []WordAction
+	}{
+		{UserWordStatusLearning, []WordAction{WordActionTomorrow, WordActionSuspend}, []WordAction{WordActionUnblock, WordActionResume}},
+		{UserWordStatusSuspended, []WordAction{WordActionResume, WordActionReset}, []WordAction{WordActionUnblock, WordActionTomorrow}},
+		{UserWordStatusBlocked, []WordAction{WordActionUnblock}, []WordAction{WordActionReset, WordActionSuspend}},
+		{"", nil, []WordAction{WordActionUnblock, WordActionReset}},
+	}
+
+	for _, tt := range tests {
+		actions := tt.status.Actions()
+		for _, action := range tt.has {
+			if !slices.Contains(actions, action) {
+				t.Errorf("actions of %q = %v, want %q among them", tt.status, actions, action)
+			}
+		}
+		for _, action := range tt.hasNot {
+			if slices.Contains(actions, action) {
+				t.Errorf("actions of %q = %v, want no %q", tt.status, actions, action)
+			}
+		}
+	}
+}
+
+func TestUserWordAttachHistory(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	history := []ReviewHistoryEntry{
+		{Direction: CardDirectionForward, Grade: 1, ReviewedAt: at},
+		{Direction: CardDirectionReverse, Grade: 2, ReviewedAt: at.Add(time.Hour)},
+		{Direction: CardDirectionForward, Grade: 3, ReviewedAt: at.Add(2 * time.Hour)},
+	}
+	word := &UserWord{Cards: []WordCardState{
+		{Direction: CardDirectionForward},
+		{Direction: CardDirectionReverse},
+	}}
+
+	word.AttachHistory(history)
+	forward, reverse := word.Cards[0].History, word.Cards[1].History
+	if len(forward) != 2 || forward[0].Grade != 1 || forward[1].Grade != 3 {
+		t.Errorf("forward history = %+v, want grades 1 and 3 in order", forward)
+	}
+	if len(reverse) != 1 || reverse[0].Grade != 2 {
+		t.Errorf("reverse history = %+v, want grade 2", reverse)
+	}
+
+	// attaching again replaces the history rather than adding to it
+	word.AttachHistory(history[:1])
+	if len(word.Cards[0].History) != 1 || len(word.Cards[1].History) != 0 {
+		t.Errorf("history attached again = %+v, %+v; want one forward grade", word.Cards[0].History, word.Cards[1].History)
+	}
+}
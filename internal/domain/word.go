@@ -14,6 +14,7 @@ type LearningWord struct {
 	Transcription string
 	Audio         string
 	RUTranslation string
+	Examples      []string
 }
 
 type ReviewWord struct {
@@ -23,6 +24,8 @@ type ReviewWord struct {
 	Transcription string
 	Audio         string
 	RUTranslation string
+	Examples      []string
+	Direction     CardDirection
 	EF            float64
 	IntervalDays  int
 	Repetition    int
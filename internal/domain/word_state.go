@@ -1,8 +1,60 @@
 package domain
 
+import "time"
+
 type UserWordStatus string
 
 const (
 	UserWordStatusLearning UserWordStatus = "learning"
 	UserWordStatusBlocked  UserWordStatus = "blocked"
+	// UserWordStatusSuspended is not reviewed until its next_review_at; then
+	// it is resumed as learning.
+	UserWordStatusSuspended UserWordStatus = "suspended"
+)
+
+// CardDirection is the direction of a review card. Each direction of a word
+// has its own scheduling state.
+type CardDirection string
+
+const (
+	// CardDirectionForward shows English, hides Russian.
+	CardDirectionForward CardDirection = "forward"
+	// CardDirectionReverse shows Russian, hides English.
+	CardDirectionReverse CardDirection = "reverse"
 )
+
+// CardDirections is the set of card directions the user reviews in a
+// subscribed dictionary.
+type CardDirections string
+
+const (
+	CardDirectionsForward CardDirections = "forward"
+	CardDirectionsReverse CardDirections = "reverse"
+	CardDirectionsBoth    CardDirections = "both"
+)
+
+func ParseCardDirections(raw string) (CardDirections, bool) {
+	switch CardDirections(raw) {
+	case CardDirectionsForward, CardDirectionsReverse, CardDirectionsBoth:
+		return CardDirections(raw), true
+	default:
+		return "", false
+	}
+}
+
+// WordStateSnapshot is the scheduling state of a card, saved before a grade
+// is applied so that the grade can be undone.
+type WordStateSnapshot struct {
+	UserID       int64
+	DictWordID   string
+	Direction    CardDirection
+	EF           float64
+	IntervalDays int
+	Repetition   int
+	LastResult   *int
+	LastReviewAt *time.Time
+	NextReviewAt *time.Time
+	// Version is bumped by every change of the state, see
+	// ApplyReviewResultInput.
+	Version int
+}
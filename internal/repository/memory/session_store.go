@@ -0,0 +1,126 @@
+// Package memory keeps state in the memory of the bot process. It's lost on
+// restart and not shared between bot instances.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type sessionKey struct {
+	userID int64
+	kind   domain.SessionKind
+}
+
+type storedSession struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// SessionStore keeps the sessions of users encoded the same way as the
+// postgres one does, so that the usecases never share state through it. Its
+// writes join the transactions run through WithTx.
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[sessionKey]storedSession
+}
+
+func NewSessionStore() *SessionStore {
+	return &SessionStore{
+		sessions: make(map[sessionKey]storedSession),
+	}
+}
+
+// LoadSession decodes the session into dst and reports whether there was an
+// unexpired one.
+func (s *SessionStore) LoadSession(ctx context.Context, userID int64, kind domain.SessionKind, dst any) (bool, error) {
+	const op = "LoadSession"
+
+	key := sessionKey{userID: userID, kind: kind}
+	stored, ok := s.pending(ctx).load(key)
+	if !ok {
+		s.mu.Lock()
+		committed, found := s.sessions[key]
+		s.mu.Unlock()
+
+		stored, ok = &committed, found
+	}
+
+	if !ok || stored == nil || !stored.expiresAt.After(time.Now()) {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(stored.data, dst); err != nil {
+		return false, fmt.Errorf("%s: decode %s session: %w", op, kind, err)
+	}
+
+	return true, nil
+}
+
+// SaveSession stores the session and keeps it for domain.SessionTTL more.
+func (s *SessionStore) SaveSession(ctx context.Context, userID int64, kind domain.SessionKind, session any) error {
+	const op = "SaveSession"
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("%s: encode %s session: %w", op, kind, err)
+	}
+
+	s.write(ctx, sessionKey{userID: userID, kind: kind}, &storedSession{
+		data:      data,
+		expiresAt: time.Now().Add(domain.SessionTTL),
+	})
+
+	return nil
+}
+
+func (s *SessionStore) DeleteSession(ctx context.Context, userID int64, kind domain.SessionKind) error {
+	s.write(ctx, sessionKey{userID: userID, kind: kind}, nil)
+
+	return nil
+}
+
+// write stores the session, or deletes it if stored is nil; in a transaction
+// of WithTx, once it commits.
+func (s *SessionStore) write(ctx context.Context, key sessionKey, stored *storedSession) {
+	if pending := s.pending(ctx); pending != nil {
+		pending.writes[key] = stored
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, stored)
+}
+
+func (s *SessionStore) set(key sessionKey, stored *storedSession) {
+	if stored == nil {
+		delete(s.sessions, key)
+		return
+	}
+
+	s.sessions[key] = *stored
+}
+
+// DeleteExpiredSessions deletes the sessions expired by the moment and
+// returns how many there were.
+func (s *SessionStore) DeleteExpiredSessions(_ context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, stored := range s.sessions {
+		if !stored.expiresAt.After(now) {
+			delete(s.sessions, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
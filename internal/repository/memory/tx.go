@@ -0,0 +1,80 @@
+package memory
+
+import "context"
+
+// TxManager runs fn atomically, like the postgres one.
+type TxManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type pendingKey struct{}
+
+// pendingWrites are the session writes of a transaction; nil deletes the
+// session.
+type pendingWrites struct {
+	store  *SessionStore
+	writes map[sessionKey]*storedSession
+}
+
+// load returns the session written in the transaction, if it was.
+func (p *pendingWrites) load(key sessionKey) (*storedSession, bool) {
+	if p == nil {
+		return nil, false
+	}
+
+	stored, ok := p.writes[key]
+	return stored, ok
+}
+
+// SessionTxManager runs the transactions of next with the session writes of
+// the store in them: the writes are kept aside, seen by the reads of the same
+// transaction, and stored only once it commits.
+type SessionTxManager struct {
+	store *SessionStore
+	next  TxManager
+}
+
+// WithTx makes the writes of the store join the transactions of next.
+func (s *SessionStore) WithTx(next TxManager) *SessionTxManager {
+	if next == nil {
+		panic("TxManager cannot be nil")
+	}
+
+	return &SessionTxManager{store: s, next: next}
+}
+
+// Do runs fn in a transaction of next. If ctx already carries a transaction,
+// fn joins it and the outermost call stores the writes.
+func (m *SessionTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.store.pending(ctx) != nil {
+		return m.next.Do(ctx, fn)
+	}
+
+	var pending *pendingWrites
+	err := m.next.Do(ctx, func(ctx context.Context) error {
+		pending = &pendingWrites{store: m.store, writes: make(map[sessionKey]*storedSession)}
+		return fn(context.WithValue(ctx, pendingKey{}, pending))
+	})
+	if err != nil {
+		return err
+	}
+
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	for key, stored := range pending.writes {
+		m.store.set(key, stored)
+	}
+
+	return nil
+}
+
+// pending returns the writes of the transaction of ctx on the store, or nil
+// outside of one.
+func (s *SessionStore) pending(ctx context.Context) *pendingWrites {
+	if pending, ok := ctx.Value(pendingKey{}).(*pendingWrites); ok && pending.store == s {
+		return pending
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// txRunner runs fn as is, like a database transaction that always commits
+// what fn did.
+type txRunner struct {
+	calls int
+}
+
+func (r *txRunner) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.calls++
+	return fn(ctx)
+}
+
+const testKind = domain.SessionReview
+
+type testSession struct {
+	Card int `json:"card"`
+}
+
+func loadCard(t *testing.T, ctx context.Context, store *SessionStore) (int, bool) {
+	t.Helper()
+
+	var session testSession
+	found, err := store.LoadSession(ctx, 1, testKind, &session)
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+
+	return session.Card, found
+}
+
+func TestSessionTxManager(t *testing.T) {
+	errRollback := errors.New("rollback")
+
+	t.Run("commit stores the writes", func(t *testing.T) {
+		store := NewSessionStore()
+		tx := store.WithTx(&txRunner{})
+
+		err := tx.Do(context.Background(), func(ctx context.Context) error {
+			if err := store.SaveSession(ctx, 1, testKind, testSession{Card: 2}); err != nil {
+				return err
+			}
+
+			if _, found := loadCard(t, context.Background(), store); found {
+				t.Error("session is seen outside of the transaction before the commit")
+			}
+			if card, found := loadCard(t, ctx, store); !found || card != 2 {
+				t.Errorf("session in the transaction = %d, %v, want 2, true", card, found)
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+
+		if card, found := loadCard(t, context.Background(), store); !found || card != 2 {
+			t.Errorf("session after the commit = %d, %v, want 2, true", card, found)
+		}
+	})
+
+	t.Run("rollback drops the writes", func(t *testing.T) {
+		store := NewSessionStore()
+		if err := store.SaveSession(context.Background(), 1, testKind, testSession{Card: 1}); err != nil {
+			t.Fatalf("SaveSession: %v", err)
+		}
+		tx := store.WithTx(&txRunner{})
+
+		err := tx.Do(context.Background(), func(ctx context.Context) error {
+			if err := store.DeleteSession(ctx, 1, testKind); err != nil {
+				return err
+			}
+
+			if _, found := loadCard(t, ctx, store); found {
+				t.Error("deleted session is seen in the transaction")
+			}
+
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("Do = %v, want %v", err, errRollback)
+		}
+
+		if card, found := loadCard(t, context.Background(), store); !found || card != 1 {
+			t.Errorf("session after the rollback = %d, %v, want 1, true", card, found)
+		}
+	})
+
+	t.Run("nested transaction joins the outer one", func(t *testing.T) {
+		store := NewSessionStore()
+		runner := &txRunner{}
+		tx := store.WithTx(runner)
+
+		err := tx.Do(context.Background(), func(ctx context.Context) error {
+			if err := tx.Do(ctx, func(ctx context.Context) error {
+				return store.SaveSession(ctx, 1, testKind, testSession{Card: 3})
+			}); err != nil {
+				return err
+			}
+
+			if _, found := loadCard(t, context.Background(), store); found {
+				t.Error("session of the nested transaction is stored before the outer one commits")
+			}
+
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("Do = %v, want %v", err, errRollback)
+		}
+
+		if _, found := loadCard(t, context.Background(), store); found {
+			t.Error("session of the nested transaction is stored after the outer one rolled back")
+		}
+		if runner.calls != 2 {
+			t.Errorf("transactions run = %d, want 2", runner.calls)
+		}
+	})
+}
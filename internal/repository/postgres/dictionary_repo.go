@@ -38,7 +38,7 @@ func (r *DictionaryRepo) ListPublic(ctx context.Context) ([]domain.Dictionary, e
 		ORDER BY created_at DESC, title ASC;
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -71,7 +71,7 @@ func (r *DictionaryRepo) GetByID(ctx context.Context, dictionaryID string) (*dom
 		WHERE id = $1;
 	`
 
-	row := r.db.QueryRowContext(ctx, query, dictionaryID)
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, dictionaryID)
 	dict, err := toDomainDictionary(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -96,7 +96,7 @@ func (r *DictionaryRepo) ExistsByID(ctx context.Context, dictionaryID string) (b
 	`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, dictionaryID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, dictionaryID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
@@ -112,14 +112,28 @@ func (r *DictionaryRepo) ListRandomPreviewWords(
 	const op = "ListRandomPreviewWords"
 
 	const query = `
-		SELECT spelling, ru_translation
-		FROM dictionary_words
-		WHERE dictionary_id = $1
-		ORDER BY random()
+		WITH pivot AS (SELECT random() AS key)
+		(
+			SELECT dw.spelling, dw.ru_translation
+			FROM dictionary_words dw, pivot
+			WHERE dw.dictionary_id = $1
+				AND dw.random_key >= pivot.key
+			ORDER BY dw.random_key
+			LIMIT $2
+		)
+		UNION ALL
+		(
+			SELECT dw.spelling, dw.ru_translation
+			FROM dictionary_words dw, pivot
+			WHERE dw.dictionary_id = $1
+				AND dw.random_key < pivot.key
+			ORDER BY dw.random_key
+			LIMIT $2
+		)
 		LIMIT $2;
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, dictionaryID, limit)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, dictionaryID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -143,29 +157,169 @@ func (r *DictionaryRepo) ListRandomPreviewWords(
 	return words, nil
 }
 
-// TODO: good place for caching batch of untracked words not to pick from DB
-// every time.
-func (r *DictionaryRepo) PickRandomUntrackedWord(
+// SampleWordPreviews returns up to limit random words of the dictionary: the
+// words from a random point of the random_key index on, wrapping around to its
+// start, like pickUntrackedRandomQuery, rather than sorting the dictionary.
+func (r *DictionaryRepo) SampleWordPreviews(
+	ctx context.Context,
+	dictionaryID string,
+	limit int,
+) ([]domain.DictionaryWordPreview, error) {
+	const op = "SampleWordPreviews"
+
+	const query = `
+		WITH pivot AS (SELECT random() AS key)
+		(
+			SELECT dw.spelling, dw.ru_translation
+			FROM dictionary_words dw, pivot
+			WHERE dw.dictionary_id = $1
+				AND dw.random_key >= pivot.key
+			ORDER BY dw.random_key
+			LIMIT $2
+		)
+		UNION ALL
+		(
+			SELECT dw.spelling, dw.ru_translation
+			FROM dictionary_words dw, pivot
+			WHERE dw.dictionary_id = $1
+				AND dw.random_key < pivot.key
+			ORDER BY dw.random_key
+			LIMIT $2
+		)
+		LIMIT $2;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, dictionaryID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	words := make([]domain.DictionaryWordPreview, 0, limit)
+	for rows.Next() {
+		w, scanErr := toDomainDictionaryWordPreview(rows)
+		if scanErr != nil {
+			err = scanErr
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		words = append(words, *w)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return words, nil
+}
+
+// PickUntrackedWord picks the next new word of the dictionary in its word
+// order; nil is returned when there are none.
+func (r *DictionaryRepo) PickUntrackedWord(
 	ctx context.Context,
 	userID int64,
 	dictionaryID string,
 ) (*domain.LearningWord, error) {
-	const op = "PickRandomUntrackedWord"
+	const op = "PickUntrackedWord"
 
-	const query = `
-		SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation
+	words, err := r.PickUntrackedWords(ctx, userID, dictionaryID, 1)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	return &words[0], nil
+}
+
+// Queries of PickUntrackedWords by word order. Each one walks an index of
+// dictionary_words in the order and stops after limit untracked words, so
+// the cost depends on how many words the user has already tracked rather
+// than on the size of the dictionary.
+const (
+	pickUntrackedByPositionQuery = `
+		SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation, dw.examples
 		FROM dictionary_words dw
-		LEFT JOIN user_words_state uws
-			ON uws.dict_word_id = dw.id AND uws.user_id = $1
 		WHERE dw.dictionary_id = $2
-			AND uws.dict_word_id IS NULL
-		ORDER BY random()
-		LIMIT 1;
+			AND NOT EXISTS (
+				SELECT 1 FROM user_words_state uws WHERE uws.user_id = $1 AND uws.dict_word_id = dw.id
+			)
+		ORDER BY dw.position
+		LIMIT $3;
 	`
 
-	row := r.db.QueryRowContext(ctx, query, userID, dictionaryID)
-	word, err := toDomainLearningWord(row)
-	if err != nil {
+	pickUntrackedByFrequencyQuery = `
+		SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation, dw.examples
+		FROM dictionary_words dw
+		WHERE dw.dictionary_id = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM user_words_state uws WHERE uws.user_id = $1 AND uws.dict_word_id = dw.id
+			)
+		ORDER BY dw.frequency_rank, dw.position
+		LIMIT $3;
+	`
+
+	pickUntrackedByTopicQuery = `
+		SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation, dw.examples
+		FROM dictionary_words dw
+		WHERE dw.dictionary_id = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM user_words_state uws WHERE uws.user_id = $1 AND uws.dict_word_id = dw.id
+			)
+		ORDER BY dw.topic_position, dw.topic
+		LIMIT $3;
+	`
+
+	// Words from a random point of the random_key index on, wrapping around to
+	// its start.
+	pickUntrackedRandomQuery = `
+		WITH pivot AS (SELECT random() AS key)
+		(
+			SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation, dw.examples
+			FROM dictionary_words dw, pivot
+			WHERE dw.dictionary_id = $2
+				AND dw.random_key >= pivot.key
+				AND NOT EXISTS (
+					SELECT 1 FROM user_words_state uws WHERE uws.user_id = $1 AND uws.dict_word_id = dw.id
+				)
+			ORDER BY dw.random_key
+			LIMIT $3
+		)
+		UNION ALL
+		(
+			SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation, dw.examples
+			FROM dictionary_words dw, pivot
+			WHERE dw.dictionary_id = $2
+				AND dw.random_key < pivot.key
+				AND NOT EXISTS (
+					SELECT 1 FROM user_words_state uws WHERE uws.user_id = $1 AND uws.dict_word_id = dw.id
+				)
+			ORDER BY dw.random_key
+			LIMIT $3
+		)
+		LIMIT $3;
+	`
+)
+
+// PickUntrackedWords picks up to limit new words of the dictionary in its
+// word order.
+func (r *DictionaryRepo) PickUntrackedWords(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	limit int,
+) ([]domain.LearningWord, error) {
+	const op = "PickUntrackedWords"
+
+	const orderQuery = `
+		SELECT word_order
+		FROM dictionaries
+		WHERE id = $1;
+	`
+
+	var order string
+	if err := conn(ctx, r.db).QueryRowContext(ctx, orderQuery, dictionaryID).Scan(&order); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -173,5 +327,37 @@ func (r *DictionaryRepo) PickRandomUntrackedWord(
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return word, nil
+	var query string
+	switch domain.WordOrder(order) {
+	case domain.WordOrderAuthor:
+		query = pickUntrackedByPositionQuery
+	case domain.WordOrderFrequency:
+		query = pickUntrackedByFrequencyQuery
+	case domain.WordOrderTopic:
+		query = pickUntrackedByTopicQuery
+	default:
+		query = pickUntrackedRandomQuery
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, dictionaryID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	words := make([]domain.LearningWord, 0, limit)
+	for rows.Next() {
+		word, scanErr := toDomainLearningWord(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("%s: %w", op, scanErr)
+		}
+
+		words = append(words, *word)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return words, nil
 }
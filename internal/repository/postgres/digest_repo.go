@@ -0,0 +1,274 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// DigestRepo finds the users due a weekly digest, records the deliveries and
+// aggregates the progress the digest tells about.
+type DigestRepo struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewDigestRepo(db *sql.DB, parentLogger *zerolog.Logger) *DigestRepo {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "digest_repo").Logger()
+
+	return &DigestRepo{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// ListDigestCandidates returns the users with the digest on, for whom the
+// moment falls on domain.DigestWeekday and whose digest of the week before
+// isn't claimed yet, ordered by ID after afterUserID.
+func (r *DigestRepo) ListDigestCandidates(
+	ctx context.Context,
+	now time.Time,
+	afterUserID int64,
+	limit int,
+) ([]domain.DigestCandidate, error) {
+	const op = "ListDigestCandidates"
+
+	const query = `
+		SELECT u.tg_id, COALESCE(u.language_code, ''), u.timezone, u.reminder_time,
+		       u.quiet_hours_start, u.quiet_hours_end, week.start
+		FROM users u
+		CROSS JOIN LATERAL (
+			SELECT ($1::timestamptz AT TIME ZONE u.timezone)::date - $5::int AS start
+		) week
+		WHERE u.digest_enabled
+			AND u.tg_id > $2
+			AND extract(ISODOW FROM $1::timestamptz AT TIME ZONE u.timezone) = $4
+			AND NOT EXISTS (
+				SELECT 1
+				FROM digest_deliveries dd
+				WHERE dd.user_id = u.tg_id
+					AND dd.week_start = week.start
+			)
+		ORDER BY u.tg_id
+		LIMIT $3;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now, afterUserID, limit, isoWeekday(domain.DigestWeekday), domain.DigestDays)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	candidates := make([]domain.DigestCandidate, 0, limit)
+	for rows.Next() {
+		candidate, scanErr := toDomainDigestCandidate(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("%s: %w", op, scanErr)
+		}
+		candidates = append(candidates, *candidate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return candidates, nil
+}
+
+// ClaimDigest records the digest of the week as taken on and reports whether
+// it wasn't before, so that a digest is sent at most once.
+func (r *DigestRepo) ClaimDigest(ctx context.Context, userID int64, week time.Time) (bool, error) {
+	const op = "ClaimDigest"
+
+	const query = `
+		INSERT INTO digest_deliveries (user_id, week_start)
+		VALUES ($1, $2::date)
+		ON CONFLICT (user_id, week_start) DO NOTHING;
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, userID, week.Format(time.DateOnly))
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected == 1, nil
+}
+
+// ReleaseDigest drops the claim of a digest not sent, so that it's claimed
+// and sent again.
+func (r *DigestRepo) ReleaseDigest(ctx context.Context, userID int64, week time.Time) error {
+	const op = "ReleaseDigest"
+
+	const query = `
+		DELETE FROM digest_deliveries
+		WHERE user_id = $1
+			AND week_start = $2::date
+			AND sent_at IS NULL;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, week.Format(time.DateOnly)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *DigestRepo) MarkDigestSent(ctx context.Context, userID int64, week, at time.Time) error {
+	const op = "MarkDigestSent"
+
+	const query = `
+		UPDATE digest_deliveries
+		SET sent_at = $3
+		WHERE user_id = $1
+			AND week_start = $2::date;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, week.Format(time.DateOnly), at); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *DigestRepo) SetDigestEnabled(ctx context.Context, userID int64, enabled bool) error {
+	const op = "SetDigestEnabled"
+
+	const query = `
+		UPDATE users
+		SET digest_enabled = $2
+		WHERE tg_id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, enabled); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountProgress counts the words added to learning and the grades given in
+// [from, to), and the recalls among the grades.
+func (r *DigestRepo) CountProgress(
+	ctx context.Context,
+	userID int64,
+	from, to time.Time,
+) (int, domain.Retention, error) {
+	const op = "CountProgress"
+
+	const query = `
+		SELECT
+			(
+				SELECT count(DISTINCT uws.dict_word_id)
+				FROM user_words_state uws
+				WHERE uws.user_id = $1
+					AND uws.status <> 'blocked'
+					AND uws.added_at >= $2
+					AND uws.added_at < $3
+			),
+			count(*),
+			count(*) FILTER (WHERE rh.passed)
+		FROM review_history rh
+		WHERE rh.user_id = $1
+			AND rh.reviewed_at >= $2
+			AND rh.reviewed_at < $3;
+	`
+
+	var learned int
+	var retention domain.Retention
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, from, to).
+		Scan(&learned, &retention.Reviews, &retention.Recalled)
+	if err != nil {
+		return 0, domain.Retention{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return learned, retention, nil
+}
+
+// ListHardestWords returns the words forgotten the most in [from, to), most
+// forgotten first.
+func (r *DigestRepo) ListHardestWords(
+	ctx context.Context,
+	userID int64,
+	from, to time.Time,
+	limit int,
+) ([]domain.HardWord, error) {
+	const op = "ListHardestWords"
+
+	const query = `
+		SELECT dw.spelling, dw.ru_translation, count(*) AS lapses
+		FROM review_history rh
+		JOIN dictionary_words dw ON dw.id = rh.dict_word_id
+		WHERE rh.user_id = $1
+			AND rh.reviewed_at >= $2
+			AND rh.reviewed_at < $3
+			AND NOT rh.passed
+		GROUP BY dw.id, dw.spelling, dw.ru_translation
+		ORDER BY lapses DESC, dw.spelling
+		LIMIT $4;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	words := make([]domain.HardWord, 0, limit)
+	for rows.Next() {
+		var w domain.HardWord
+		if err = rows.Scan(&w.Spelling, &w.RUTranslation, &w.Lapses); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		words = append(words, w)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return words, nil
+}
+
+// CountDueUntil counts the words in learning due before the moment, with the
+// suspended ones whose suspension is over by then.
+func (r *DigestRepo) CountDueUntil(ctx context.Context, userID int64, until time.Time) (int, error) {
+	const op = "CountDueUntil"
+
+	const query = `
+		SELECT count(DISTINCT dict_word_id)
+		FROM user_words_state
+		WHERE user_id = $1
+			AND status IN ('learning', 'suspended')
+			AND next_review_at < $2;
+	`
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, until).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// isoWeekday numbers the weekday from Monday as 1 to Sunday as 7, as ISODOW
+// does.
+func isoWeekday(day time.Weekday) int {
+	if day == time.Sunday {
+		return 7
+	}
+
+	return int(day)
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// JobRunsRepo remembers when every background job last ran successfully.
+type JobRunsRepo struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewJobRunsRepo(db *sql.DB, parentLogger *zerolog.Logger) *JobRunsRepo {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "job_runs_repo").Logger()
+
+	return &JobRunsRepo{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// LastJobRun returns the zero time if the job has never run.
+func (r *JobRunsRepo) LastJobRun(ctx context.Context, name string) (time.Time, error) {
+	const op = "LastJobRun"
+
+	const query = `
+		SELECT last_run_at
+		FROM job_runs
+		WHERE name = $1;
+	`
+
+	var lastRunAt time.Time
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, name).Scan(&lastRunAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lastRunAt, nil
+}
+
+func (r *JobRunsRepo) SaveJobRun(ctx context.Context, name string, at time.Time) error {
+	const op = "SaveJobRun"
+
+	const query = `
+		INSERT INTO job_runs (name, last_run_at)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE
+		SET last_run_at = EXCLUDED.last_run_at;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, name, at); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// jobLockClass is the first key of the two-key advisory locks of jobs.
+	// Users are locked by the single bigint key, which Postgres keeps apart
+	// from the two-key ones, so a job never waits for a user.
+	jobLockClass = 1
+	// unlockTimeout bounds releasing a lock, done after the ctx of the lock
+	// may be over.
+	unlockTimeout = 5 * time.Second
+	// A user locked by another instance is tried again after lockRetryMin,
+	// doubled up to lockRetryMax, with no connection held in between.
+	lockRetryMin = 10 * time.Millisecond
+	lockRetryMax = 500 * time.Millisecond
+)
+
+// Locker coordinates the bot instances through session-level advisory locks,
+// each held on a connection of its own and released with it: a lock can't
+// outlive a crashed instance, and no transaction is kept open while it's
+// held. The connections are taken from db, which should be a pool of its own
+// so that the locks don't take the connections of the queries.
+type Locker struct {
+	db *sql.DB
+	// users serializes the updates of a user within the instance, so that
+	// the instance holds one connection per user at most.
+	users  keyedMutex
+	logger *zerolog.Logger
+}
+
+func NewLocker(db *sql.DB, parentLogger *zerolog.Logger) *Locker {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "locker").Logger()
+
+	return &Locker{
+		db:     db,
+		users:  keyedMutex{locks: make(map[int64]*keyedLock)},
+		logger: &logger,
+	}
+}
+
+// LockUser waits until no update of the user is handled by any instance and
+// locks the user until unlock is called. Within the instance the updates wait
+// for each other in memory; a lock taken by another instance is polled with
+// pg_try_advisory_lock, so that waiting doesn't hold a connection.
+func (l *Locker) LockUser(ctx context.Context, userID int64) (func(), error) {
+	const op = "LockUser"
+
+	const lockQuery = `SELECT pg_try_advisory_lock($1)`
+	const unlockQuery = `SELECT pg_advisory_unlock($1)`
+
+	unlockLocal, err := l.users.lock(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	retry := lockRetryMin
+	for {
+		unlock, ok, err := l.hold(ctx, lockQuery, unlockQuery, userID)
+		if err != nil {
+			unlockLocal()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if ok {
+			return func() {
+				unlock()
+				unlockLocal()
+			}, nil
+		}
+
+		// The jitter keeps the instances waiting for the user from trying
+		// in step.
+		select {
+		case <-time.After(retry/2 + rand.N(retry/2+1)):
+		case <-ctx.Done():
+			unlockLocal()
+			return nil, fmt.Errorf("%s: %w", op, ctx.Err())
+		}
+		retry = min(2*retry, lockRetryMax)
+	}
+}
+
+// TryLockJob locks the job unless another instance runs it, in which case
+// false is returned. The lock is held until unlock is called.
+func (l *Locker) TryLockJob(ctx context.Context, name string) (func(), bool, error) {
+	const op = "TryLockJob"
+
+	const lockQuery = `SELECT pg_try_advisory_lock($1, hashtext($2))`
+	const unlockQuery = `SELECT pg_advisory_unlock($1, hashtext($2))`
+
+	unlock, ok, err := l.hold(ctx, lockQuery, unlockQuery, jobLockClass, name)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return unlock, ok, nil
+}
+
+// hold takes a connection and the lock on it with lockQuery, which must
+// return whether the lock is taken; unlockQuery with the same args releases
+// it. The connection is kept out of the pool while the lock is held.
+func (l *Locker) hold(ctx context.Context, lockQuery, unlockQuery string, args ...any) (func(), bool, error) {
+	c, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("get conn: %w", err)
+	}
+
+	var locked bool
+	if err = c.QueryRowContext(ctx, lockQuery, args...).Scan(&locked); err != nil {
+		// The lock may have been taken before the query was canceled, so the
+		// session is closed rather than returned to the pool.
+		l.discard(c)
+		return nil, false, fmt.Errorf("take lock: %w", err)
+	}
+	if !locked {
+		_ = c.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+
+		var released bool
+		if err := c.QueryRowContext(ctx, unlockQuery, args...).Scan(&released); err != nil || !released {
+			l.logger.Warn().Err(err).Bool("released", released).Msg("release lock, closing its session")
+			l.discard(c)
+			return
+		}
+		_ = c.Close()
+	}
+
+	return unlock, true, nil
+}
+
+// discard closes the session of the connection, which releases its locks.
+func (l *Locker) discard(c *sql.Conn) {
+	_ = c.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	_ = c.Close()
+}
+
+// keyedMutex is a mutex per key; waiting for it can be canceled.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[int64]*keyedLock
+}
+
+type keyedLock struct {
+	held chan struct{}
+	// refs counts the holder and the waiters; the lock is dropped from the
+	// map with the last of them.
+	refs int
+}
+
+// lock waits for the key until ctx is done and returns the func releasing it.
+func (m *keyedMutex) lock(ctx context.Context, key int64) (func(), error) {
+	m.mu.Lock()
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{held: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	select {
+	case l.held <- struct{}{}:
+		return func() {
+			<-l.held
+			m.release(key, l)
+		}, nil
+	case <-ctx.Done():
+		m.release(key, l)
+		return nil, ctx.Err()
+	}
+}
+
+func (m *keyedMutex) release(key int64, l *keyedLock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
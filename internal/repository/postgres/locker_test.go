@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	m := keyedMutex{locks: make(map[int64]*keyedLock)}
+
+	const waiters = 20
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		holders = make(map[int64]int)
+		overlap bool
+	)
+	for i := range waiters {
+		key := int64(i % 2)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			unlock, err := m.lock(context.Background(), key)
+			if err != nil {
+				t.Errorf("lock %d: %v", key, err)
+				return
+			}
+
+			mu.Lock()
+			holders[key]++
+			overlap = overlap || holders[key] > 1
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			holders[key]--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+
+	if overlap {
+		t.Error("a key was held twice at once")
+	}
+	if len(m.locks) != 0 {
+		t.Errorf("%d locks left after release, want none", len(m.locks))
+	}
+}
+
+func TestKeyedMutexCancel(t *testing.T) {
+	m := keyedMutex{locks: make(map[int64]*keyedLock)}
+
+	unlock, err := m.lock(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	// Another key isn't held up.
+	unlockOther, err := m.lock(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("lock another key: %v", err)
+	}
+	unlockOther()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = m.lock(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("lock of a held key: err = %v, want deadline exceeded", err)
+	}
+
+	unlock()
+	if len(m.locks) != 0 {
+		t.Errorf("%d locks left after release, want none", len(m.locks))
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"github.com/krezefal/eng-tg-bot/internal/domain"
 )
 
@@ -47,6 +49,7 @@ func toDomainLearningWord(scanner rowScanner) (*domain.LearningWord, error) {
 		&w.Transcription,
 		&w.Audio,
 		&w.RUTranslation,
+		pq.Array(&w.Examples),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert into learning word: %w", err)
@@ -58,6 +61,7 @@ func toDomainLearningWord(scanner rowScanner) (*domain.LearningWord, error) {
 func toDomainReviewWord(scanner rowScanner) (*domain.ReviewWord, error) {
 	var w domain.ReviewWord
 	var nextReviewAt sql.NullTime
+	var direction string
 
 	err := scanner.Scan(
 		&w.ID,
@@ -66,6 +70,8 @@ func toDomainReviewWord(scanner rowScanner) (*domain.ReviewWord, error) {
 		&w.Transcription,
 		&w.Audio,
 		&w.RUTranslation,
+		pq.Array(&w.Examples),
+		&direction,
 		&w.EF,
 		&w.IntervalDays,
 		&w.Repetition,
@@ -75,9 +81,253 @@ func toDomainReviewWord(scanner rowScanner) (*domain.ReviewWord, error) {
 		return nil, fmt.Errorf("failed to convert into review word: %w", err)
 	}
 
+	w.Direction = domain.CardDirection(direction)
 	if nextReviewAt.Valid {
 		w.NextReviewAt = &nextReviewAt.Time
 	}
 
 	return &w, nil
 }
+
+func toDomainWordStateSnapshot(scanner rowScanner) (*domain.WordStateSnapshot, error) {
+	var s domain.WordStateSnapshot
+	var direction string
+	var lastResult sql.NullInt64
+	var lastReviewAt, nextReviewAt sql.NullTime
+
+	err := scanner.Scan(
+		&s.UserID,
+		&s.DictWordID,
+		&direction,
+		&s.EF,
+		&s.IntervalDays,
+		&s.Repetition,
+		&lastResult,
+		&lastReviewAt,
+		&nextReviewAt,
+		&s.Version,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert into word state snapshot: %w", err)
+	}
+
+	s.Direction = domain.CardDirection(direction)
+	if lastResult.Valid {
+		v := int(lastResult.Int64)
+		s.LastResult = &v
+	}
+	if lastReviewAt.Valid {
+		s.LastReviewAt = &lastReviewAt.Time
+	}
+	if nextReviewAt.Valid {
+		s.NextReviewAt = &nextReviewAt.Time
+	}
+
+	return &s, nil
+}
+
+// toDomainUserWordRow scans a row of a word joined with one of its card
+// states; the state is nil for a word that isn't tracked.
+func toDomainUserWordRow(scanner rowScanner) (*domain.UserWord, *domain.WordCardState, error) {
+	var w domain.UserWord
+	var status, direction sql.NullString
+	var ef sql.NullFloat64
+	var intervalDays, repetition, lastResult sql.NullInt64
+	var lastReviewAt, nextReviewAt sql.NullTime
+
+	err := scanner.Scan(
+		&w.ID,
+		&w.DictionaryID,
+		&w.DictionaryTitle,
+		&w.Spelling,
+		&w.Transcription,
+		&w.RUTranslation,
+		&status,
+		&direction,
+		&ef,
+		&intervalDays,
+		&repetition,
+		&lastResult,
+		&lastReviewAt,
+		&nextReviewAt,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert into user word: %w", err)
+	}
+
+	if !status.Valid {
+		return &w, nil, nil
+	}
+
+	w.Status = domain.UserWordStatus(status.String)
+	card := &domain.WordCardState{
+		Direction:    domain.CardDirection(direction.String),
+		EF:           ef.Float64,
+		IntervalDays: int(intervalDays.Int64),
+		Repetition:   int(repetition.Int64),
+	}
+	if lastResult.Valid {
+		v := int(lastResult.Int64)
+		card.LastResult = &v
+	}
+	if lastReviewAt.Valid {
+		card.LastReviewAt = &lastReviewAt.Time
+	}
+	if nextReviewAt.Valid {
+		card.NextReviewAt = &nextReviewAt.Time
+	}
+
+	return &w, card, nil
+}
+
+func toDomainReviewHistoryEntry(scanner rowScanner) (*domain.ReviewHistoryEntry, error) {
+	var e domain.ReviewHistoryEntry
+	var direction string
+
+	err := scanner.Scan(
+		&direction,
+		&e.Grade,
+		&e.EF,
+		&e.IntervalDays,
+		&e.ReviewedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert into review history entry: %w", err)
+	}
+
+	e.Direction = domain.CardDirection(direction)
+
+	return &e, nil
+}
+
+func toDomainReminderCandidate(scanner rowScanner) (*domain.ReminderCandidate, error) {
+	var c domain.ReminderCandidate
+	var quietStart, quietEnd sql.NullInt64
+
+	err := scanner.Scan(
+		&c.UserID,
+		&c.LanguageCode,
+		&c.Settings.Timezone,
+		&c.Settings.Time,
+		&quietStart,
+		&quietEnd,
+		&c.DueCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert into reminder candidate: %w", err)
+	}
+
+	c.Settings.Enabled = true
+	c.Settings.QuietStart, c.Settings.QuietEnd = nullIntPtr(quietStart), nullIntPtr(quietEnd)
+
+	return &c, nil
+}
+
+func toDomainDigestCandidate(scanner rowScanner) (*domain.DigestCandidate, error) {
+	var c domain.DigestCandidate
+	var quietStart, quietEnd sql.NullInt64
+
+	err := scanner.Scan(
+		&c.UserID,
+		&c.LanguageCode,
+		&c.Settings.Timezone,
+		&c.Settings.Time,
+		&quietStart,
+		&quietEnd,
+		&c.Week,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert into digest candidate: %w", err)
+	}
+
+	c.Settings.QuietStart, c.Settings.QuietEnd = nullIntPtr(quietStart), nullIntPtr(quietEnd)
+
+	return &c, nil
+}
+
+func toDomainNotification(scanner rowScanner) (*domain.Notification, error) {
+	var n domain.Notification
+	var quietStart, quietEnd sql.NullInt64
+	var kind string
+
+	err := scanner.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.LanguageCode,
+		&n.Settings.Timezone,
+		&quietStart,
+		&quietEnd,
+		&kind,
+		&n.DictionaryID,
+		&n.DictionaryTitle,
+		&n.Words,
+		&n.Attempts,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert into notification: %w", err)
+	}
+
+	n.Kind = domain.NotificationKind(kind)
+	n.Settings.QuietStart, n.Settings.QuietEnd = nullIntPtr(quietStart), nullIntPtr(quietEnd)
+
+	return &n, nil
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+
+	i := int(v.Int64)
+	return &i
+}
+
+func toDomainUserSettings(scanner rowScanner) (*domain.UserSettings, error) {
+	var s domain.UserSettings
+	var quietStart, quietEnd sql.NullInt64
+	var reviewMode, reviewKeyboard, cardDirections, scheduler, goalKind string
+
+	err := scanner.Scan(
+		&s.Language,
+		&s.Reminder.Enabled,
+		&s.Reminder.Timezone,
+		&s.Reminder.Time,
+		&quietStart,
+		&quietEnd,
+		&s.NewWordsPerDay,
+		&s.ReviewsPerDay,
+		&reviewMode,
+		&reviewKeyboard,
+		&cardDirections,
+		&scheduler,
+		&goalKind,
+		&s.DailyGoal.Target,
+		&s.StreakFreeze,
+		&s.Digest,
+		&s.Notifications,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert into user settings: %w", err)
+	}
+
+	s.Reminder.QuietStart, s.Reminder.QuietEnd = nullIntPtr(quietStart), nullIntPtr(quietEnd)
+
+	var ok bool
+	if s.ReviewMode, ok = domain.ParseReviewMode(reviewMode); !ok {
+		return nil, fmt.Errorf("failed to convert into user settings: unknown review mode %q", reviewMode)
+	}
+	if s.ReviewKeyboard, ok = domain.ParseReviewKeyboard(reviewKeyboard); !ok {
+		return nil, fmt.Errorf("failed to convert into user settings: %w: %q", domain.ErrInvalidReviewKeyboard, reviewKeyboard)
+	}
+	if s.CardDirections, ok = domain.ParseCardDirections(cardDirections); !ok {
+		return nil, fmt.Errorf("failed to convert into user settings: %w: %q", domain.ErrInvalidCardDirections, cardDirections)
+	}
+	if s.Scheduler, ok = domain.ParseScheduler(scheduler); !ok {
+		return nil, fmt.Errorf("failed to convert into user settings: unknown scheduler %q", scheduler)
+	}
+	if s.DailyGoal.Kind, ok = domain.ParseGoalKind(goalKind); !ok {
+		return nil, fmt.Errorf("failed to convert into user settings: unknown daily goal kind %q", goalKind)
+	}
+
+	return &s, nil
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// NotificationRepo is the outbox of the notifications to subscribers. The
+// seeder enqueues the notifications about new words itself, in the
+// transaction that adds them.
+type NotificationRepo struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewNotificationRepo(db *sql.DB, parentLogger *zerolog.Logger) *NotificationRepo {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "notification_repo").Logger()
+
+	return &NotificationRepo{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// EnqueueUnlockedBatches enqueues a notification for every subscriber of an
+// on_schedule dictionary whose batch became available in (since, now], and
+// returns how many were enqueued. Batches available right on subscription
+// are left out, and a batch is told about once.
+func (r *NotificationRepo) EnqueueUnlockedBatches(ctx context.Context, since, now time.Time) (int, error) {
+	const op = "EnqueueUnlockedBatches"
+
+	const query = `
+		INSERT INTO notifications (user_id, kind, dictionary_id, batch_id, words)
+		SELECT ud.user_id, 'batch_unlocked', b.dictionary_id, b.id, words.count
+		FROM dictionary_schedule_batch b
+		JOIN dictionaries d ON d.id = b.dictionary_id
+		JOIN user_dictionaries ud ON ud.dictionary_id = b.dictionary_id
+		JOIN users u ON u.tg_id = ud.user_id
+		CROSS JOIN LATERAL (
+			SELECT count(*) AS count
+			FROM dictionary_words dw
+			WHERE dw.batch_id = b.id
+		) words
+		WHERE d.mode = 'on_schedule'
+			AND b.delay_days > 0
+			AND u.notifications_enabled
+			AND words.count > 0
+			AND ud.subscribed_at + make_interval(days => b.delay_days) > $1
+			AND ud.subscribed_at + make_interval(days => b.delay_days) <= $2
+		ON CONFLICT (user_id, batch_id) WHERE batch_id IS NOT NULL DO NOTHING;
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, since, now)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
+
+// ListDueNotifications returns the unsent notifications of users who have
+// them on, due by the moment and not given up on, ordered by ID after
+// afterID.
+func (r *NotificationRepo) ListDueNotifications(
+	ctx context.Context,
+	now time.Time,
+	afterID int64,
+	limit int,
+) ([]domain.Notification, error) {
+	const op = "ListDueNotifications"
+
+	const query = `
+		SELECT n.id, n.user_id, COALESCE(u.language_code, ''), u.timezone,
+		       u.quiet_hours_start, u.quiet_hours_end, n.kind, n.dictionary_id,
+		       d.title, n.words, n.attempts
+		FROM notifications n
+		JOIN users u ON u.tg_id = n.user_id
+		JOIN dictionaries d ON d.id = n.dictionary_id
+		WHERE n.sent_at IS NULL
+			AND n.next_attempt_at <= $1
+			AND n.attempts < $4
+			AND n.id > $2
+			AND u.notifications_enabled
+		ORDER BY n.id
+		LIMIT $3;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now, afterID, limit, domain.NotificationMaxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	notifications := make([]domain.Notification, 0, limit)
+	for rows.Next() {
+		notification, scanErr := toDomainNotification(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("%s: %w", op, scanErr)
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepo) MarkNotificationSent(ctx context.Context, id int64, at time.Time) error {
+	const op = "MarkNotificationSent"
+
+	const query = `
+		UPDATE notifications
+		SET sent_at = $2
+		WHERE id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id, at); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MarkNotificationFailed counts a failed attempt and puts the next one off
+// until the moment.
+func (r *NotificationRepo) MarkNotificationFailed(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	reason string,
+) error {
+	const op = "MarkNotificationFailed"
+
+	const query = `
+		UPDATE notifications
+		SET attempts = attempts + 1,
+			next_attempt_at = $2,
+			last_error = $3
+		WHERE id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id, nextAttemptAt, reason); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DropDisabledNotifications deletes the unsent notifications of users who
+// turned them off, so that they don't come in a bunch once turned back on.
+func (r *NotificationRepo) DropDisabledNotifications(ctx context.Context) (int, error) {
+	const op = "DropDisabledNotifications"
+
+	const query = `
+		DELETE FROM notifications n
+		USING users u
+		WHERE u.tg_id = n.user_id
+			AND n.sent_at IS NULL
+			AND NOT u.notifications_enabled;
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
+
+func (r *NotificationRepo) SetNotificationsEnabled(ctx context.Context, userID int64, enabled bool) error {
+	const op = "SetNotificationsEnabled"
+
+	const query = `
+		UPDATE users
+		SET notifications_enabled = $2
+		WHERE tg_id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, enabled); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// SessionStore keeps the sessions of users as JSON, so that they survive
+// restarts and are shared by the bot instances.
+type SessionStore struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewSessionStore(db *sql.DB, parentLogger *zerolog.Logger) *SessionStore {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "session_store").Logger()
+
+	return &SessionStore{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// LoadSession decodes the session into dst and reports whether there was an
+// unexpired one.
+func (s *SessionStore) LoadSession(ctx context.Context, userID int64, kind domain.SessionKind, dst any) (bool, error) {
+	const op = "LoadSession"
+
+	const query = `
+		SELECT data
+		FROM user_sessions
+		WHERE user_id = $1
+			AND kind = $2
+			AND expires_at > $3;
+	`
+
+	var data []byte
+	err := conn(ctx, s.db).QueryRowContext(ctx, query, userID, string(kind), time.Now()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = json.Unmarshal(data, dst); err != nil {
+		return false, fmt.Errorf("%s: decode %s session: %w", op, kind, err)
+	}
+
+	return true, nil
+}
+
+// SaveSession stores the session and keeps it for domain.SessionTTL more.
+func (s *SessionStore) SaveSession(ctx context.Context, userID int64, kind domain.SessionKind, session any) error {
+	const op = "SaveSession"
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("%s: encode %s session: %w", op, kind, err)
+	}
+
+	const query = `
+		INSERT INTO user_sessions (user_id, kind, data, updated_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, kind) DO UPDATE
+		SET data = EXCLUDED.data,
+			updated_at = EXCLUDED.updated_at,
+			expires_at = EXCLUDED.expires_at;
+	`
+
+	now := time.Now()
+	if _, err = conn(ctx, s.db).ExecContext(ctx, query, userID, string(kind), data, now, now.Add(domain.SessionTTL)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *SessionStore) DeleteSession(ctx context.Context, userID int64, kind domain.SessionKind) error {
+	const op = "DeleteSession"
+
+	const query = `
+		DELETE FROM user_sessions
+		WHERE user_id = $1
+			AND kind = $2;
+	`
+
+	if _, err := conn(ctx, s.db).ExecContext(ctx, query, userID, string(kind)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredSessions deletes the sessions expired by the moment and
+// returns how many there were.
+func (s *SessionStore) DeleteExpiredSessions(ctx context.Context, now time.Time) (int, error) {
+	const op = "DeleteExpiredSessions"
+
+	const query = `
+		DELETE FROM user_sessions
+		WHERE expires_at <= $1;
+	`
+
+	res, err := conn(ctx, s.db).ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// SettingsRepo reads and writes the preferences kept in users as a whole.
+type SettingsRepo struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewSettingsRepo(db *sql.DB, parentLogger *zerolog.Logger) *SettingsRepo {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "settings_repo").Logger()
+
+	return &SettingsRepo{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// GetSettings returns the defaults for an unknown user.
+func (r *SettingsRepo) GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error) {
+	const op = "GetSettings"
+
+	const query = `
+		SELECT COALESCE(language_code, ''), reminders_enabled, timezone, reminder_time,
+		       quiet_hours_start, quiet_hours_end, new_words_per_day, reviews_per_day,
+		       review_mode, review_keyboard, card_directions, scheduler,
+		       daily_goal_kind, daily_goal, streak_freeze_enabled, digest_enabled,
+		       notifications_enabled
+		FROM users
+		WHERE tg_id = $1;
+	`
+
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, userID)
+	settings, err := toDomainUserSettings(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DefaultUserSettings(), nil
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings writes all the settings but the language, which is only
+// changed with SetLanguageCode of UserRepo.
+func (r *SettingsRepo) UpdateSettings(ctx context.Context, userID int64, settings *domain.UserSettings) error {
+	const op = "UpdateSettings"
+
+	if settings == nil {
+		return fmt.Errorf("%s: settings is nil", op)
+	}
+
+	const query = `
+		UPDATE users
+		SET reminders_enabled = $2,
+			timezone = $3,
+			reminder_time = $4,
+			quiet_hours_start = $5,
+			quiet_hours_end = $6,
+			new_words_per_day = $7,
+			reviews_per_day = $8,
+			review_mode = $9,
+			review_keyboard = $10,
+			card_directions = $11,
+			scheduler = $12,
+			daily_goal_kind = $13,
+			daily_goal = $14,
+			streak_freeze_enabled = $15,
+			digest_enabled = $16,
+			notifications_enabled = $17
+		WHERE tg_id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		userID,
+		settings.Reminder.Enabled,
+		settings.Reminder.Timezone,
+		settings.Reminder.Time,
+		settings.Reminder.QuietStart,
+		settings.Reminder.QuietEnd,
+		settings.NewWordsPerDay,
+		settings.ReviewsPerDay,
+		string(settings.ReviewMode),
+		string(settings.ReviewKeyboard),
+		string(settings.CardDirections),
+		string(settings.Scheduler),
+		string(settings.DailyGoal.Kind),
+		settings.DailyGoal.Target,
+		settings.StreakFreeze,
+		settings.Digest,
+		settings.Notifications,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// StatsRepo aggregates the progress of users. An empty dictionary ID stands
+// for all dictionaries of the user.
+type StatsRepo struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewStatsRepo(db *sql.DB, parentLogger *zerolog.Logger) *StatsRepo {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "stats_repo").Logger()
+
+	return &StatsRepo{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// CountWordsByStatus counts the tracked words of the user by status; a word
+// whose suspension is over counts as learning.
+func (r *StatsRepo) CountWordsByStatus(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+) (map[domain.UserWordStatus]int, error) {
+	const op = "CountWordsByStatus"
+
+	const query = `
+		SELECT
+			CASE
+				WHEN uws.status = 'suspended' AND uws.next_review_at <= now() THEN 'learning'
+				ELSE uws.status::text
+			END AS status,
+			count(DISTINCT uws.dict_word_id)
+		FROM user_words_state uws
+		JOIN dictionary_words dw ON dw.id = uws.dict_word_id
+		WHERE uws.user_id = $1
+			AND ($2::uuid IS NULL OR dw.dictionary_id = $2::uuid)
+		GROUP BY 1;
+	`
+
+	dictID := sql.NullString{String: dictionaryID, Valid: dictionaryID != ""}
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, dictID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.UserWordStatus]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err = rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		counts[domain.UserWordStatus(status)] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// CountRetention counts the grades given since the moment and the recalls
+// among them.
+func (r *StatsRepo) CountRetention(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	since time.Time,
+) (domain.Retention, error) {
+	const op = "CountRetention"
+
+	const query = `
+		SELECT count(*), count(*) FILTER (WHERE rh.passed)
+		FROM review_history rh
+		JOIN dictionary_words dw ON dw.id = rh.dict_word_id
+		WHERE rh.user_id = $1
+			AND ($2::uuid IS NULL OR dw.dictionary_id = $2::uuid)
+			AND rh.reviewed_at >= $3;
+	`
+
+	dictID := sql.NullString{String: dictionaryID, Valid: dictionaryID != ""}
+
+	var retention domain.Retention
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, dictID, since).
+		Scan(&retention.Reviews, &retention.Recalled)
+	if err != nil {
+		return domain.Retention{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return retention, nil
+}
+
+// ListReviewsPerDay counts the grades given since the moment by local date of
+// the time zone; days without grades are left out.
+func (r *StatsRepo) ListReviewsPerDay(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	timezone string,
+	since time.Time,
+) ([]domain.DayCount, error) {
+	const op = "ListReviewsPerDay"
+
+	const query = `
+		SELECT (rh.reviewed_at AT TIME ZONE $3)::date AS day, count(*)
+		FROM review_history rh
+		JOIN dictionary_words dw ON dw.id = rh.dict_word_id
+		WHERE rh.user_id = $1
+			AND ($2::uuid IS NULL OR dw.dictionary_id = $2::uuid)
+			AND rh.reviewed_at >= $4
+		GROUP BY 1
+		ORDER BY 1;
+	`
+
+	dictID := sql.NullString{String: dictionaryID, Valid: dictionaryID != ""}
+
+	return r.listDayCounts(ctx, op, query, userID, dictID, timezone, since)
+}
+
+// ListDueForecast counts the learning words due before the moment by local
+// date of the time zone; overdue words count for today, the local date
+// given. Days without due words are left out.
+func (r *StatsRepo) ListDueForecast(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	timezone string,
+	today time.Time,
+	until time.Time,
+) ([]domain.DayCount, error) {
+	const op = "ListDueForecast"
+
+	const query = `
+		SELECT GREATEST((uws.next_review_at AT TIME ZONE $3)::date, $4::date) AS day,
+		       count(DISTINCT uws.dict_word_id)
+		FROM user_words_state uws
+		JOIN dictionary_words dw ON dw.id = uws.dict_word_id
+		WHERE uws.user_id = $1
+			AND ($2::uuid IS NULL OR dw.dictionary_id = $2::uuid)
+			-- a suspended word is due once its suspension is over
+			AND uws.status IN ('learning', 'suspended')
+			AND uws.next_review_at < $5
+		GROUP BY 1
+		ORDER BY 1;
+	`
+
+	dictID := sql.NullString{String: dictionaryID, Valid: dictionaryID != ""}
+
+	return r.listDayCounts(ctx, op, query, userID, dictID, timezone, today.Format(time.DateOnly), until)
+}
+
+func (r *StatsRepo) listDayCounts(ctx context.Context, op, query string, args ...any) ([]domain.DayCount, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make([]domain.DayCount, 0)
+	for rows.Next() {
+		var count domain.DayCount
+		if err = rows.Scan(&count.Day, &count.Count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		counts = append(counts, count)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return counts, nil
+}
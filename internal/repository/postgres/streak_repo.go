@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// StreakRepo reads the daily activity of users and keeps their streak
+// freezes.
+type StreakRepo struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewStreakRepo(db *sql.DB, parentLogger *zerolog.Logger) *StreakRepo {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "streak_repo").Logger()
+
+	return &StreakRepo{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// ListDailyActivity returns the grades, the new words and the frozen days of
+// the user by local date of the time zone, oldest first, since the moment.
+// Days without any are left out.
+func (r *StreakRepo) ListDailyActivity(
+	ctx context.Context,
+	userID int64,
+	timezone string,
+	since time.Time,
+) ([]domain.DayActivity, error) {
+	const op = "ListDailyActivity"
+
+	const query = `
+		WITH reviews AS (
+			SELECT (reviewed_at AT TIME ZONE $2)::date AS day, count(*) AS n
+			FROM review_history
+			WHERE user_id = $1
+				AND reviewed_at >= $3
+			GROUP BY 1
+		), added AS (
+			SELECT (added_at AT TIME ZONE $2)::date AS day, count(DISTINCT dict_word_id) AS n
+			FROM user_words_state
+			WHERE user_id = $1
+				AND status <> 'blocked'
+				AND added_at >= $3
+			GROUP BY 1
+		), frozen AS (
+			SELECT day
+			FROM streak_frozen_days
+			WHERE user_id = $1
+				AND day >= ($3::timestamptz AT TIME ZONE $2)::date
+		)
+		SELECT d.day, COALESCE(r.n, 0), COALESCE(a.n, 0), f.day IS NOT NULL
+		FROM (
+			SELECT day FROM reviews
+			UNION
+			SELECT day FROM added
+			UNION
+			SELECT day FROM frozen
+		) d
+		LEFT JOIN reviews r ON r.day = d.day
+		LEFT JOIN added a ON a.day = d.day
+		LEFT JOIN frozen f ON f.day = d.day
+		ORDER BY d.day;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, timezone, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	days := make([]domain.DayActivity, 0)
+	for rows.Next() {
+		var day domain.DayActivity
+		if err = rows.Scan(&day.Day, &day.Reviews, &day.NewWords, &day.Frozen); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		days = append(days, day)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return days, nil
+}
+
+func (r *StreakRepo) GetStreakState(ctx context.Context, userID int64) (*domain.StreakState, error) {
+	const op = "GetStreakState"
+
+	const query = `
+		SELECT streak_freezes, streak_settled_through
+		FROM users
+		WHERE tg_id = $1;
+	`
+
+	var state domain.StreakState
+	var settledThrough sql.NullTime
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&state.Freezes, &settledThrough); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if settledThrough.Valid {
+		state.SettledThrough = &settledThrough.Time
+	}
+
+	return &state, nil
+}
+
+// SaveStreakSettlement stores the settlement unless the streak has been
+// settled since prev was read, in which case false is returned.
+func (r *StreakRepo) SaveStreakSettlement(
+	ctx context.Context,
+	userID int64,
+	prev *time.Time,
+	settlement domain.StreakSettlement,
+) (bool, error) {
+	const op = "SaveStreakSettlement"
+
+	const query = `
+		WITH settled AS (
+			UPDATE users
+			SET streak_freezes = $3,
+				streak_settled_through = $4::date
+			WHERE tg_id = $1
+				AND streak_settled_through IS NOT DISTINCT FROM $2::date
+			RETURNING tg_id
+		), frozen AS (
+			INSERT INTO streak_frozen_days (user_id, day)
+			SELECT s.tg_id, d
+			FROM settled s
+			CROSS JOIN unnest($5::date[]) AS d
+			ON CONFLICT (user_id, day) DO NOTHING
+		)
+		SELECT count(*) FROM settled;
+	`
+
+	var prevDate *string
+	if prev != nil {
+		date := prev.Format(time.DateOnly)
+		prevDate = &date
+	}
+
+	// pq has no date array type, so the dates are passed as text.
+	frozen := make([]string, 0, len(settlement.Frozen))
+	for _, day := range settlement.Frozen {
+		frozen = append(frozen, day.Format(time.DateOnly))
+	}
+
+	var settled int
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		userID,
+		prevDate,
+		settlement.Freezes,
+		settlement.SettledThrough.Format(time.DateOnly),
+		pq.Array(frozen),
+	).Scan(&settled)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return settled > 0, nil
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/rs/zerolog"
@@ -28,16 +29,21 @@ func NewSubscriptionsRepo(db *sql.DB, parentLogger *zerolog.Logger) *Subscriptio
 	}
 }
 
-func (r *SubscriptionsRepo) Subscribe(ctx context.Context, userID int64, dictionaryID string) (bool, error) {
+func (r *SubscriptionsRepo) Subscribe(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	directions domain.CardDirections,
+) (bool, error) {
 	const op = "Subscribe"
 
 	const query = `
-		INSERT INTO user_dictionaries (user_id, dictionary_id)
-		VALUES ($1, $2)
+		INSERT INTO user_dictionaries (user_id, dictionary_id, card_directions)
+		VALUES ($1, $2, $3)
 		ON CONFLICT (user_id, dictionary_id) DO NOTHING;
 	`
 
-	res, err := r.db.ExecContext(ctx, query, userID, dictionaryID)
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, userID, dictionaryID, directions)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
@@ -66,36 +72,35 @@ func (r *SubscriptionsRepo) Unsubscribe(ctx context.Context, userID int64, dicti
 			AND dw.dictionary_id = $2;
 	`
 
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
-	}
-	defer func() {
-		_ = tx.Rollback()
-	}()
+	var unsubscribed bool
+	err := inTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
 
-	res, err := tx.ExecContext(ctx, deleteSubscriptionQuery, userID, dictionaryID)
-	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
-	}
+		res, err := tx.ExecContext(ctx, deleteSubscriptionQuery, userID, dictionaryID)
+		if err != nil {
+			return err
+		}
 
-	rows, err := res.RowsAffected()
-	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
-	}
-	if rows == 0 {
-		return false, nil
-	}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return nil
+		}
 
-	if _, err = tx.ExecContext(ctx, deleteProgressQuery, userID, dictionaryID); err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
-	}
+		if _, err = tx.ExecContext(ctx, deleteProgressQuery, userID, dictionaryID); err != nil {
+			return err
+		}
+		unsubscribed = true
 
-	if err = tx.Commit(); err != nil {
+		return nil
+	})
+	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return rows > 0, nil
+	return unsubscribed, nil
 }
 
 func (r *SubscriptionsRepo) ListByUser(ctx context.Context, userID int64) ([]domain.Dictionary, error) {
@@ -109,7 +114,7 @@ func (r *SubscriptionsRepo) ListByUser(ctx context.Context, userID int64) ([]dom
 		ORDER BY ud.subscribed_at ASC, d.title ASC;
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -144,7 +149,7 @@ func (r *SubscriptionsRepo) IsSubscribedByUser(ctx context.Context, userID int64
 	`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, userID, dictionaryID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, dictionaryID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
@@ -164,7 +169,68 @@ func (r *SubscriptionsRepo) MarkLearningStarted(ctx context.Context, userID int6
 		WHERE user_id = $1 AND dictionary_id = $2;
 	`
 
-	res, err := r.db.ExecContext(ctx, query, userID, dictionaryID)
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, userID, dictionaryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rows == 0 {
+		return domain.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *SubscriptionsRepo) GetCardDirections(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+) (domain.CardDirections, error) {
+	const op = "GetCardDirections"
+
+	const query = `
+		SELECT card_directions
+		FROM user_dictionaries
+		WHERE user_id = $1 AND dictionary_id = $2;
+	`
+
+	var raw string
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, dictionaryID).Scan(&raw)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrSubscriptionNotFound
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	directions, ok := domain.ParseCardDirections(raw)
+	if !ok {
+		return "", fmt.Errorf("%s: unsupported card directions: %q", op, raw)
+	}
+
+	return directions, nil
+}
+
+func (r *SubscriptionsRepo) SetCardDirections(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	directions domain.CardDirections,
+) error {
+	const op = "SetCardDirections"
+
+	const query = `
+		UPDATE user_dictionaries
+		SET card_directions = $3
+		WHERE user_id = $1 AND dictionary_id = $2;
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, userID, dictionaryID, string(directions))
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// querier is what the repos run their queries on: the database or the
+// transaction of the context.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// conn returns the transaction started by TxManager.Do for ctx, or db when
+// there is none.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// inTx runs fn in a transaction kept in the context passed to it; the
+// transaction is committed if fn succeeds and rolled back otherwise. If ctx
+// already carries a transaction, fn joins it and the outermost call commits.
+func inTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// TxManager lets usecases run several repo calls atomically: the postgres
+// repos called with the context passed to the closure work on one
+// transaction.
+type TxManager struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewTxManager(db *sql.DB, parentLogger *zerolog.Logger) *TxManager {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "tx_manager").Logger()
+
+	return &TxManager{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// Do runs fn in a transaction, committed if fn returns nil and rolled back
+// otherwise. The error of fn is returned as is.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	const op = "Do"
+
+	err := inTx(ctx, m.db, fn)
+	if err != nil {
+		m.logger.Debug().Err(err).Msgf("%s: transaction rolled back", op)
+	}
+
+	return err
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// UpdatesRepo remembers the Telegram updates already handled, so that an
+// update delivered again is handled once.
+type UpdatesRepo struct {
+	db     *sql.DB
+	logger *zerolog.Logger
+}
+
+func NewUpdatesRepo(db *sql.DB, parentLogger *zerolog.Logger) *UpdatesRepo {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "updates_repo").Logger()
+
+	return &UpdatesRepo{
+		db:     db,
+		logger: &logger,
+	}
+}
+
+// MarkUpdateProcessed records the update and reports whether it's recorded
+// for the first time; recording it again is a no-op that reports false.
+func (r *UpdatesRepo) MarkUpdateProcessed(ctx context.Context, updateID int) (bool, error) {
+	const op = "MarkUpdateProcessed"
+
+	const query = `
+		INSERT INTO processed_updates (update_id)
+		VALUES ($1)
+		ON CONFLICT (update_id) DO NOTHING;
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, updateID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteProcessedUpdates forgets the updates recorded before the moment and
+// returns how many there were.
+func (r *UpdatesRepo) DeleteProcessedUpdates(ctx context.Context, before time.Time) (int, error) {
+	const op = "DeleteProcessedUpdates"
+
+	const query = `
+		DELETE FROM processed_updates
+		WHERE processed_at < $1;
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(affected), nil
+}
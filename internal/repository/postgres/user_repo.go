@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
 )
 
 type UserRepo struct {
@@ -38,7 +41,7 @@ func (r *UserRepo) CreateUser(ctx context.Context, id int64, username string) er
 			created_at = COALESCE(users.created_at, EXCLUDED.created_at);
 	`
 
-	if _, err := r.db.ExecContext(ctx, query, id, username); err != nil {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id, username); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -53,7 +56,7 @@ func (r *UserRepo) DeleteUser(ctx context.Context, id int64) error {
 		WHERE tg_id = $1;
 	`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -81,7 +84,7 @@ func (r *UserRepo) SetActiveDictionaryID(ctx context.Context, userID int64, dict
 		WHERE tg_id = $1;
 	`
 
-	_, err := r.db.ExecContext(ctx, query, userID, dictionaryID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, dictionaryID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -99,7 +102,7 @@ func (r *UserRepo) GetActiveDictionaryID(ctx context.Context, userID int64) (str
 	`
 
 	var dictionaryID string
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(&dictionaryID)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&dictionaryID)
 	if err != nil {
 		// TODO: return error
 		if errors.Is(err, sql.ErrNoRows) {
@@ -121,8 +124,266 @@ func (r *UserRepo) ClearActiveDictionaryID(ctx context.Context, userID int64) er
 		WHERE tg_id = $1;
 	`
 
-	_, err := r.db.ExecContext(ctx, query, userID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetLanguageCode returns the UI language explicitly chosen by the user or an
+// empty string if the user has not chosen one yet.
+func (r *UserRepo) GetLanguageCode(ctx context.Context, userID int64) (string, error) {
+	const op = "GetLanguageCode"
+
+	const query = `
+		SELECT COALESCE(language_code, '')
+		FROM users
+		WHERE tg_id = $1;
+	`
+
+	var code string
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+func (r *UserRepo) SetLanguageCode(ctx context.Context, userID int64, code string) error {
+	const op = "SetLanguageCode"
+
+	const query = `
+		UPDATE users
+		SET language_code = $2
+		WHERE tg_id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, code)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetReviewKeyboard(ctx context.Context, userID int64) (domain.ReviewKeyboard, error) {
+	const op = "GetReviewKeyboard"
+
+	const query = `
+		SELECT review_keyboard
+		FROM users
+		WHERE tg_id = $1;
+	`
+
+	var raw string
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&raw)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ReviewKeyboardReply, nil
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	keyboard, ok := domain.ParseReviewKeyboard(raw)
+	if !ok {
+		return "", fmt.Errorf("%s: %w: %q", op, domain.ErrInvalidReviewKeyboard, raw)
+	}
+
+	return keyboard, nil
+}
+
+func (r *UserRepo) SetReviewKeyboard(ctx context.Context, userID int64, keyboard domain.ReviewKeyboard) error {
+	const op = "SetReviewKeyboard"
+
+	const query = `
+		UPDATE users
+		SET review_keyboard = $2
+		WHERE tg_id = $1;
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, string(keyboard))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetReminderSettings(ctx context.Context, userID int64) (*domain.ReminderSettings, error) {
+	const op = "GetReminderSettings"
+
+	const query = `
+		SELECT reminders_enabled, timezone, reminder_time, quiet_hours_start, quiet_hours_end
+		FROM users
+		WHERE tg_id = $1;
+	`
+
+	var s domain.ReminderSettings
+	var quietStart, quietEnd sql.NullInt64
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&s.Enabled, &s.Timezone, &s.Time, &quietStart, &quietEnd)
 	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.QuietStart, s.QuietEnd = nullIntPtr(quietStart), nullIntPtr(quietEnd)
+
+	return &s, nil
+}
+
+func (r *UserRepo) SetRemindersEnabled(ctx context.Context, userID int64, enabled bool) error {
+	const op = "SetRemindersEnabled"
+
+	const query = `
+		UPDATE users
+		SET reminders_enabled = $2
+		WHERE tg_id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, enabled); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *UserRepo) SetReminderTime(ctx context.Context, userID int64, minute int) error {
+	const op = "SetReminderTime"
+
+	const query = `
+		UPDATE users
+		SET reminder_time = $2
+		WHERE tg_id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, minute); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *UserRepo) SetTimezone(ctx context.Context, userID int64, timezone string) error {
+	const op = "SetTimezone"
+
+	const query = `
+		UPDATE users
+		SET timezone = $2
+		WHERE tg_id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, timezone); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetQuietHours sets the quiet hours; nil start and end turn them off.
+func (r *UserRepo) SetQuietHours(ctx context.Context, userID int64, start, end *int) error {
+	const op = "SetQuietHours"
+
+	const query = `
+		UPDATE users
+		SET quiet_hours_start = $2,
+			quiet_hours_end = $3
+		WHERE tg_id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, start, end); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ListReminderCandidates returns the users with reminders on and due words who
+// haven't been reminded on the current local day yet, with ids above
+// afterUserID, in id order. Words are due as in ListAllDueReviewWords, with
+// the local day of the user.
+func (r *UserRepo) ListReminderCandidates(
+	ctx context.Context,
+	now time.Time,
+	afterUserID int64,
+	limit int,
+) ([]domain.ReminderCandidate, error) {
+	const op = "ListReminderCandidates"
+
+	const query = `
+		SELECT u.tg_id, COALESCE(u.language_code, ''), u.timezone, u.reminder_time,
+		       u.quiet_hours_start, u.quiet_hours_end, due.words
+		FROM users u
+		CROSS JOIN LATERAL (
+			SELECT count(DISTINCT uws.dict_word_id) AS words
+			FROM user_words_state uws
+			INNER JOIN dictionary_words dw ON dw.id = uws.dict_word_id
+			INNER JOIN user_dictionaries ud
+				ON ud.user_id = uws.user_id AND ud.dictionary_id = dw.dictionary_id
+			WHERE uws.user_id = u.tg_id
+				-- a suspended word is due once its suspension is over
+				AND uws.status IN ('learning', 'suspended')
+				AND (ud.card_directions = 'both' OR ud.card_directions::text = uws.direction::text)
+				AND (uws.next_review_at IS NULL OR uws.next_review_at <= $1)
+				AND NOT EXISTS (
+					SELECT 1
+					FROM user_words_state sib
+					WHERE sib.user_id = uws.user_id
+						AND sib.dict_word_id = uws.dict_word_id
+						AND sib.direction <> uws.direction
+						AND sib.last_review_at >= date_trunc('day', $1::timestamptz AT TIME ZONE u.timezone)
+							AT TIME ZONE u.timezone
+				)
+		) due
+		WHERE u.reminders_enabled
+			AND u.tg_id > $2
+			AND due.words > 0
+			AND (
+				u.last_reminded_at IS NULL
+				OR (u.last_reminded_at AT TIME ZONE u.timezone)::date
+					< ($1::timestamptz AT TIME ZONE u.timezone)::date
+			)
+		ORDER BY u.tg_id
+		LIMIT $3;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now, afterUserID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	candidates := make([]domain.ReminderCandidate, 0, limit)
+	for rows.Next() {
+		candidate, scanErr := toDomainReminderCandidate(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("%s: %w", op, scanErr)
+		}
+		candidates = append(candidates, *candidate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return candidates, nil
+}
+
+func (r *UserRepo) MarkReminded(ctx context.Context, userID int64, at time.Time) error {
+	const op = "MarkReminded"
+
+	const query = `
+		UPDATE users
+		SET last_reminded_at = $2
+		WHERE tg_id = $1;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, at); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
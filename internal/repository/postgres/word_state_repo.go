@@ -3,9 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 
 	"github.com/krezefal/eng-tg-bot/internal/domain"
@@ -37,14 +39,52 @@ func (r *WordsStateRepo) UpsertStatus(
 ) error {
 	const op = "UpsertStatus"
 
+	// Rows for every card direction are created at once: directions the user
+	// hasn't enabled for the dictionary are filtered out on review.
 	const query = `
-		INSERT INTO user_words_state (user_id, dict_word_id, status)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (user_id, dict_word_id) DO UPDATE
-		SET status = EXCLUDED.status;
+		INSERT INTO user_words_state (user_id, dict_word_id, status, direction)
+		SELECT $1, $2, $3, d
+		FROM unnest(enum_range(NULL::card_direction)) AS d
+		ON CONFLICT (user_id, dict_word_id, direction) DO UPDATE
+		SET status = EXCLUDED.status,
+			version = user_words_state.version + 1;
 	`
 
-	if _, err := r.db.ExecContext(ctx, query, userID, dictWordID, string(status)); err != nil {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, dictWordID, string(status)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddLearnedWords starts learning the words recalled in a batch quiz. Every
+// card direction is due after the short-term step of its word (see
+// domain.LearnedReviewAt); words that got tracked meanwhile are left as is.
+func (r *WordsStateRepo) AddLearnedWords(
+	ctx context.Context,
+	userID int64,
+	words []domain.LearnedWord,
+	learnedAt time.Time,
+) error {
+	const op = "AddLearnedWords"
+
+	const query = `
+		INSERT INTO user_words_state (user_id, dict_word_id, status, direction, next_review_at)
+		SELECT $1, w.dict_word_id, 'learning', d, w.next_review_at
+		FROM unnest($2::uuid[], $3::timestamptz[]) AS w(dict_word_id, next_review_at)
+		CROSS JOIN unnest(enum_range(NULL::card_direction)) AS d
+		ON CONFLICT (user_id, dict_word_id, direction) DO NOTHING;
+	`
+
+	ids := make([]string, 0, len(words))
+	// pq has no timestamp array type, so the times are passed as text.
+	reviewAt := make([]string, 0, len(words))
+	for _, w := range words {
+		ids = append(ids, w.DictWordID)
+		reviewAt = append(reviewAt, domain.LearnedReviewAt(w.Mistakes, learnedAt).Format(time.RFC3339Nano))
+	}
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, pq.Array(ids), pq.Array(reviewAt)); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -66,34 +106,135 @@ func (r *WordsStateRepo) HasReviewWords(ctx context.Context, userID int64, dicti
 	`
 
 	var has bool
-	if err := r.db.QueryRowContext(ctx, query, userID, dictionaryID).Scan(&has); err != nil {
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, dictionaryID).Scan(&has); err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return has, nil
 }
 
+// HasAnyReviewWords is HasReviewWords across all subscribed dictionaries.
+func (r *WordsStateRepo) HasAnyReviewWords(ctx context.Context, userID int64) (bool, error) {
+	const op = "HasAnyReviewWords"
+
+	const query = `
+		SELECT EXISTS(
+			SELECT 1
+			FROM user_words_state uws
+			INNER JOIN dictionary_words dw ON dw.id = uws.dict_word_id
+			INNER JOIN user_dictionaries ud
+				ON ud.user_id = uws.user_id AND ud.dictionary_id = dw.dictionary_id
+			WHERE uws.user_id = $1
+				AND uws.status = 'learning'
+		);
+	`
+
+	var has bool
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&has); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return has, nil
+}
+
+// ListDueReviewWords returns due cards of the directions enabled for the
+// dictionary. Sibling cards are buried: only one direction of a word is
+// returned, and none if another direction was already reviewed since dayStart.
 func (r *WordsStateRepo) ListDueReviewWords(
 	ctx context.Context,
 	userID int64,
 	dictionaryID string,
 	now time.Time,
+	dayStart time.Time,
 ) ([]*domain.ReviewWord, error) {
 	const op = "ListDueReviewWords"
 
 	const query = `
-		SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation,
-		       uws.ef, uws.interval_days, uws.repetition, uws.next_review_at
+		SELECT id, dictionary_id, spelling, transcription, audio, ru_translation, examples,
+		       direction, ef, interval_days, repetition, next_review_at
+		FROM (
+			SELECT DISTINCT ON (dw.id)
+			       dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation, dw.examples,
+			       uws.direction, uws.ef, uws.interval_days, uws.repetition, uws.next_review_at
+			FROM user_words_state uws
+			INNER JOIN dictionary_words dw ON dw.id = uws.dict_word_id
+			INNER JOIN user_dictionaries ud
+				ON ud.user_id = uws.user_id AND ud.dictionary_id = dw.dictionary_id
+			WHERE uws.user_id = $1
+				AND dw.dictionary_id = $2
+				AND uws.status = 'learning'
+				AND (ud.card_directions = 'both' OR ud.card_directions::text = uws.direction::text)
+				AND (uws.next_review_at IS NULL OR uws.next_review_at <= $3)
+				AND NOT EXISTS (
+					SELECT 1
+					FROM user_words_state sib
+					WHERE sib.user_id = uws.user_id
+						AND sib.dict_word_id = uws.dict_word_id
+						AND sib.direction <> uws.direction
+						AND sib.last_review_at >= $4
+				)
+			ORDER BY dw.id, uws.next_review_at NULLS FIRST, uws.direction
+		) due
+		ORDER BY next_review_at NULLS FIRST, spelling ASC;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, dictionaryID, now, dayStart)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	words := make([]*domain.ReviewWord, 0, 16)
+	for rows.Next() {
+		word, scanErr := toDomainReviewWord(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("%s: %w", op, scanErr)
+		}
+
+		words = append(words, word)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return words, nil
+}
+
+// ListAllDueReviewWords is ListDueReviewWords across all subscribed
+// dictionaries. The order is left to the caller.
+func (r *WordsStateRepo) ListAllDueReviewWords(
+	ctx context.Context,
+	userID int64,
+	now time.Time,
+	dayStart time.Time,
+) ([]*domain.ReviewWord, error) {
+	const op = "ListAllDueReviewWords"
+
+	const query = `
+		SELECT DISTINCT ON (dw.id)
+		       dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation, dw.examples,
+		       uws.direction, uws.ef, uws.interval_days, uws.repetition, uws.next_review_at
 		FROM user_words_state uws
 		INNER JOIN dictionary_words dw ON dw.id = uws.dict_word_id
+		INNER JOIN user_dictionaries ud
+			ON ud.user_id = uws.user_id AND ud.dictionary_id = dw.dictionary_id
 		WHERE uws.user_id = $1
-			AND dw.dictionary_id = $2
 			AND uws.status = 'learning'
-			AND (uws.next_review_at IS NULL OR uws.next_review_at <= $3)
-		ORDER BY uws.next_review_at NULLS FIRST, dw.spelling ASC;
+			AND (ud.card_directions = 'both' OR ud.card_directions::text = uws.direction::text)
+			AND (uws.next_review_at IS NULL OR uws.next_review_at <= $2)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM user_words_state sib
+				WHERE sib.user_id = uws.user_id
+					AND sib.dict_word_id = uws.dict_word_id
+					AND sib.direction <> uws.direction
+					AND sib.last_review_at >= $3
+			)
+		ORDER BY dw.id, uws.next_review_at NULLS FIRST, uws.direction;
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, dictionaryID, now)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, now, dayStart)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -116,6 +257,40 @@ func (r *WordsStateRepo) ListDueReviewWords(
 	return words, nil
 }
 
+// CountDueReviewWords counts words with a card of an enabled direction due by
+// until; a suspended word counts if its suspension is over by then. An empty
+// dictionaryID means all subscribed dictionaries.
+func (r *WordsStateRepo) CountDueReviewWords(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	until time.Time,
+) (int, error) {
+	const op = "CountDueReviewWords"
+
+	const query = `
+		SELECT COUNT(DISTINCT dw.id)
+		FROM user_words_state uws
+		INNER JOIN dictionary_words dw ON dw.id = uws.dict_word_id
+		INNER JOIN user_dictionaries ud
+			ON ud.user_id = uws.user_id AND ud.dictionary_id = dw.dictionary_id
+		WHERE uws.user_id = $1
+			AND ($2::uuid IS NULL OR dw.dictionary_id = $2::uuid)
+			AND uws.status IN ('learning', 'suspended')
+			AND (ud.card_directions = 'both' OR ud.card_directions::text = uws.direction::text)
+			AND (uws.next_review_at IS NULL OR uws.next_review_at < $3);
+	`
+
+	dictID := sql.NullString{String: dictionaryID, Valid: dictionaryID != ""}
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, dictID, until).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
 func (r *WordsStateRepo) ListAllReviewWordsByNearest(
 	ctx context.Context,
 	userID int64,
@@ -125,17 +300,20 @@ func (r *WordsStateRepo) ListAllReviewWordsByNearest(
 	const op = "ListAllReviewWordsByNearest"
 
 	const query = `
-		SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation,
-		       uws.ef, uws.interval_days, uws.repetition, uws.next_review_at
+		SELECT dw.id, dw.dictionary_id, dw.spelling, dw.transcription, dw.audio, dw.ru_translation, dw.examples,
+		       uws.direction, uws.ef, uws.interval_days, uws.repetition, uws.next_review_at
 		FROM user_words_state uws
 		INNER JOIN dictionary_words dw ON dw.id = uws.dict_word_id
+		INNER JOIN user_dictionaries ud
+			ON ud.user_id = uws.user_id AND ud.dictionary_id = dw.dictionary_id
 		WHERE uws.user_id = $1
 			AND dw.dictionary_id = $2
 			AND uws.status = 'learning'
+			AND (ud.card_directions = 'both' OR ud.card_directions::text = uws.direction::text)
 		ORDER BY COALESCE(uws.next_review_at, $3) ASC, dw.spelling ASC;
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, dictionaryID, now)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, dictionaryID, now)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -158,6 +336,9 @@ func (r *WordsStateRepo) ListAllReviewWordsByNearest(
 	return words, nil
 }
 
+// ApplyReviewResult stores the result if the state of the card is still at
+// in.Version, the one the result is computed from; otherwise
+// ErrWordStateChanged is returned.
 func (r *WordsStateRepo) ApplyReviewResult(
 	ctx context.Context,
 	in *domain.ApplyReviewResultInput,
@@ -171,19 +352,29 @@ func (r *WordsStateRepo) ApplyReviewResult(
 		return fmt.Errorf("%s: result is nil", op)
 	}
 
+	// the grade is logged to the history in the same statement
 	const query = `
-		UPDATE user_words_state
-		SET ef = $3,
-			interval_days = $4,
-			repetition = $5,
-			last_result = $6,
-			last_review_at = $7,
-			next_review_at = $8
-		WHERE user_id = $1
-			AND dict_word_id = $2;
+		WITH updated AS (
+			UPDATE user_words_state
+			SET ef = $3,
+				interval_days = $4,
+				repetition = $5,
+				last_result = $6,
+				last_review_at = $7,
+				next_review_at = $8,
+				version = version + 1
+			WHERE user_id = $1
+				AND dict_word_id = $2
+				AND direction = $9
+				AND version = $10
+			RETURNING user_id, dict_word_id, direction
+		)
+		INSERT INTO review_history (user_id, dict_word_id, direction, grade, passed, ef, interval_days, reviewed_at)
+		SELECT user_id, dict_word_id, direction, $6, $11, $3, $4, $7
+		FROM updated;
 	`
 
-	res, err := r.db.ExecContext(
+	res, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		in.UserID,
@@ -194,6 +385,9 @@ func (r *WordsStateRepo) ApplyReviewResult(
 		in.Grade,
 		in.ReviewedAt,
 		in.Result.NextReviewAt,
+		string(in.Direction),
+		in.Version,
+		in.Passed,
 	)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -204,8 +398,502 @@ func (r *WordsStateRepo) ApplyReviewResult(
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	if rows == 0 {
-		return domain.ErrReviewNotStarted
+		return domain.ErrWordStateChanged
+	}
+
+	return nil
+}
+
+func (r *WordsStateRepo) GetStateSnapshot(
+	ctx context.Context,
+	userID int64,
+	dictWordID string,
+	direction domain.CardDirection,
+) (*domain.WordStateSnapshot, error) {
+	const op = "GetStateSnapshot"
+
+	const query = `
+		SELECT user_id, dict_word_id, direction, ef, interval_days, repetition,
+		       last_result, last_review_at, next_review_at, version
+		FROM user_words_state
+		WHERE user_id = $1
+			AND dict_word_id = $2
+			AND direction = $3;
+	`
+
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, userID, dictWordID, string(direction))
+	snapshot, err := toDomainWordStateSnapshot(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrReviewNotStarted
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snapshot, nil
+}
+
+// RestoreState overwrites the scheduling state of a card with a snapshot and
+// drops the latest grade of the card from the history, as it is the one being
+// undone. ErrWordStateChanged is returned if the card has changed since the
+// grade, which is then no longer the latest.
+func (r *WordsStateRepo) RestoreState(ctx context.Context, snapshot *domain.WordStateSnapshot) error {
+	const op = "RestoreState"
+
+	if snapshot == nil {
+		return fmt.Errorf("%s: snapshot is nil", op)
+	}
+
+	const query = `
+		WITH restored AS (
+			UPDATE user_words_state
+			SET ef = $4,
+				interval_days = $5,
+				repetition = $6,
+				last_result = $7,
+				last_review_at = $8,
+				next_review_at = $9,
+				version = version + 1
+			WHERE user_id = $1
+				AND dict_word_id = $2
+				AND direction = $3
+				AND version = $10
+			RETURNING user_id, dict_word_id, direction
+		), dropped AS (
+			DELETE FROM review_history
+			WHERE id = (
+				SELECT max(rh.id)
+				FROM review_history rh
+				INNER JOIN restored r
+					USING (user_id, dict_word_id, direction)
+			)
+		)
+		SELECT count(*) FROM restored;
+	`
+
+	var rows int
+	err := conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		snapshot.UserID,
+		snapshot.DictWordID,
+		string(snapshot.Direction),
+		snapshot.EF,
+		snapshot.IntervalDays,
+		snapshot.Repetition,
+		snapshot.LastResult,
+		snapshot.LastReviewAt,
+		snapshot.NextReviewAt,
+		snapshot.Version+1,
+	).Scan(&rows)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rows == 0 {
+		return domain.ErrWordStateChanged
 	}
 
 	return nil
 }
+
+const userWordsSelect = `
+	SELECT dw.id, dw.dictionary_id, d.title, dw.spelling, dw.transcription, dw.ru_translation,
+	       uws.status, uws.direction, uws.ef, uws.interval_days, uws.repetition,
+	       uws.last_result, uws.last_review_at, uws.next_review_at
+	FROM dictionary_words dw
+	INNER JOIN dictionaries d ON d.id = dw.dictionary_id
+	INNER JOIN user_dictionaries ud
+		ON ud.dictionary_id = dw.dictionary_id AND ud.user_id = $1
+	LEFT JOIN user_words_state uws
+		ON uws.dict_word_id = dw.id AND uws.user_id = $1
+`
+
+// ListUserWordsBySpelling finds the word in the subscribed dictionaries,
+// case-insensitively.
+func (r *WordsStateRepo) ListUserWordsBySpelling(
+	ctx context.Context,
+	userID int64,
+	spelling string,
+) ([]*domain.UserWord, error) {
+	const op = "ListUserWordsBySpelling"
+
+	const query = userWordsSelect + `
+		WHERE lower(dw.spelling) = lower($2)
+		ORDER BY d.title, dw.id, uws.direction;
+	`
+
+	words, err := r.listUserWords(ctx, query, userID, spelling)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return words, nil
+}
+
+// GetUserWord returns nil if the word is not in a subscribed dictionary.
+func (r *WordsStateRepo) GetUserWord(ctx context.Context, userID int64, dictWordID string) (*domain.UserWord, error) {
+	const op = "GetUserWord"
+
+	const query = userWordsSelect + `
+		WHERE dw.id = $2
+		ORDER BY uws.direction;
+	`
+
+	words, err := r.listUserWords(ctx, query, userID, dictWordID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	return words[0], nil
+}
+
+// ListUserWordsByStatus returns up to limit words of the subscribed
+// dictionaries in one of the statuses, alphabetically.
+func (r *WordsStateRepo) ListUserWordsByStatus(
+	ctx context.Context,
+	userID int64,
+	statuses []domain.UserWordStatus,
+	limit int,
+) ([]*domain.UserWord, error) {
+	const op = "ListUserWordsByStatus"
+
+	const query = userWordsSelect + `
+		WHERE dw.id IN (
+			SELECT w.id
+			FROM dictionary_words w
+			INNER JOIN user_dictionaries sub
+				ON sub.dictionary_id = w.dictionary_id AND sub.user_id = $1
+			WHERE EXISTS (
+				SELECT 1
+				FROM user_words_state s
+				WHERE s.user_id = $1
+					AND s.dict_word_id = w.id
+					AND s.status::text = ANY($2)
+			)
+			ORDER BY lower(w.spelling), w.id
+			LIMIT $3
+		)
+		ORDER BY lower(dw.spelling), dw.id, uws.direction;
+	`
+
+	raw := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		raw = append(raw, string(s))
+	}
+
+	words, err := r.listUserWords(ctx, query, userID, pq.Array(raw), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return words, nil
+}
+
+// listUserWords groups the card rows of userWordsSelect ordered by word.
+func (r *WordsStateRepo) listUserWords(ctx context.Context, query string, args ...any) ([]*domain.UserWord, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := make([]*domain.UserWord, 0, 1)
+	for rows.Next() {
+		word, card, scanErr := toDomainUserWordRow(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+
+		if n := len(words); n == 0 || words[n-1].ID != word.ID {
+			words = append(words, word)
+		}
+		if card != nil {
+			last := words[len(words)-1]
+			last.Status = word.Status
+			last.Cards = append(last.Cards, *card)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// ListWordReviewHistory returns up to limit latest grades of every card
+// direction of the word, oldest first. The grades given before the word was
+// last started, e.g. before an unsubscription, are left out.
+func (r *WordsStateRepo) ListWordReviewHistory(
+	ctx context.Context,
+	userID int64,
+	dictWordID string,
+	limit int,
+) ([]domain.ReviewHistoryEntry, error) {
+	const op = "ListWordReviewHistory"
+
+	const query = `
+		SELECT direction, grade, ef, interval_days, reviewed_at
+		FROM (
+			SELECT rh.direction, rh.grade, rh.ef, rh.interval_days, rh.reviewed_at,
+			       row_number() OVER (PARTITION BY rh.direction ORDER BY rh.reviewed_at DESC) AS rn
+			FROM review_history rh
+			JOIN user_words_state uws
+				ON uws.user_id = rh.user_id
+				AND uws.dict_word_id = rh.dict_word_id
+				AND uws.direction = rh.direction
+			WHERE rh.user_id = $1
+				AND rh.dict_word_id = $2
+				AND (uws.added_at IS NULL OR rh.reviewed_at >= uws.added_at)
+		) h
+		WHERE rn <= $3
+		ORDER BY direction, reviewed_at;
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, dictWordID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	history := make([]domain.ReviewHistoryEntry, 0, limit)
+	for rows.Next() {
+		entry, scanErr := toDomainReviewHistoryEntry(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("%s: %w", op, scanErr)
+		}
+		history = append(history, *entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return history, nil
+}
+
+// UnblockWord starts learning a blocked word from scratch. false is returned
+// if the word is not blocked.
+func (r *WordsStateRepo) UnblockWord(ctx context.Context, userID int64, dictWordID string) (bool, error) {
+	const op = "UnblockWord"
+
+	const query = `
+		UPDATE user_words_state
+		SET status = 'learning',
+			ef = DEFAULT,
+			interval_days = DEFAULT,
+			repetition = DEFAULT,
+			last_result = NULL,
+			last_review_at = NULL,
+			next_review_at = NULL,
+			version = version + 1
+		WHERE user_id = $1
+			AND dict_word_id = $2
+			AND status = 'blocked';
+	`
+
+	updated, err := r.execAffected(ctx, query, userID, dictWordID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated, nil
+}
+
+// ResetWord starts learning a learning or suspended word from scratch. false
+// is returned if the word is neither.
+func (r *WordsStateRepo) ResetWord(ctx context.Context, userID int64, dictWordID string) (bool, error) {
+	const op = "ResetWord"
+
+	const query = `
+		UPDATE user_words_state
+		SET status = 'learning',
+			ef = DEFAULT,
+			interval_days = DEFAULT,
+			repetition = DEFAULT,
+			last_result = NULL,
+			last_review_at = NULL,
+			next_review_at = NULL,
+			version = version + 1
+		WHERE user_id = $1
+			AND dict_word_id = $2
+			AND status IN ('learning', 'suspended');
+	`
+
+	updated, err := r.execAffected(ctx, query, userID, dictWordID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated, nil
+}
+
+// SuspendWord hides a learning or suspended word from review until the time;
+// a card due later keeps its date. false is returned if the word is neither.
+func (r *WordsStateRepo) SuspendWord(
+	ctx context.Context,
+	userID int64,
+	dictWordID string,
+	until time.Time,
+) (bool, error) {
+	const op = "SuspendWord"
+
+	const query = `
+		UPDATE user_words_state
+		SET status = 'suspended',
+			next_review_at = GREATEST(COALESCE(next_review_at, $3), $3),
+			version = version + 1
+		WHERE user_id = $1
+			AND dict_word_id = $2
+			AND status IN ('learning', 'suspended');
+	`
+
+	updated, err := r.execAffected(ctx, query, userID, dictWordID, until)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated, nil
+}
+
+// RescheduleWord makes a learning or suspended word due at the time, resuming
+// it if suspended. false is returned if the word is neither.
+func (r *WordsStateRepo) RescheduleWord(
+	ctx context.Context,
+	userID int64,
+	dictWordID string,
+	at time.Time,
+) (bool, error) {
+	const op = "RescheduleWord"
+
+	const query = `
+		UPDATE user_words_state
+		SET status = 'learning',
+			next_review_at = $3,
+			version = version + 1
+		WHERE user_id = $1
+			AND dict_word_id = $2
+			AND status IN ('learning', 'suspended');
+	`
+
+	updated, err := r.execAffected(ctx, query, userID, dictWordID, at)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated, nil
+}
+
+// MarkWordKnown schedules a learning or suspended word as recalled with the
+// interval: the next successful review multiplies the interval by EF. false is
+// returned if the word is neither.
+func (r *WordsStateRepo) MarkWordKnown(
+	ctx context.Context,
+	userID int64,
+	dictWordID string,
+	intervalDays int,
+	now time.Time,
+) (bool, error) {
+	const op = "MarkWordKnown"
+
+	const query = `
+		UPDATE user_words_state
+		SET status = 'learning',
+			interval_days = $3,
+			repetition = GREATEST(repetition, 2),
+			last_review_at = $4,
+			next_review_at = $4 + make_interval(days => $3),
+			version = version + 1
+		WHERE user_id = $1
+			AND dict_word_id = $2
+			AND status IN ('learning', 'suspended');
+	`
+
+	updated, err := r.execAffected(ctx, query, userID, dictWordID, intervalDays, now)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated, nil
+}
+
+// ResumeSuspended brings the suspended words whose time has come back to
+// learning; they are due right away. It runs before a round is started: the
+// counts of due words outside of rounds, e.g. for the reminders, take the
+// suspended words whose time has come as due without it.
+func (r *WordsStateRepo) ResumeSuspended(ctx context.Context, userID int64, now time.Time) error {
+	const op = "ResumeSuspended"
+
+	const query = `
+		UPDATE user_words_state
+		SET status = 'learning',
+			version = version + 1
+		WHERE user_id = $1
+			AND status = 'suspended'
+			AND next_review_at <= $2;
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, now); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *WordsStateRepo) execAffected(ctx context.Context, query string, args ...any) (bool, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// CountWordsAddedSince counts the words the user started learning since the
+// moment; skipped words don't count.
+func (r *WordsStateRepo) CountWordsAddedSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	const op = "CountWordsAddedSince"
+
+	const query = `
+		SELECT count(DISTINCT dict_word_id)
+		FROM user_words_state
+		WHERE user_id = $1
+			AND status <> 'blocked'
+			AND added_at >= $2;
+	`
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// CountReviewsSince counts the grades the user gave since the moment.
+func (r *WordsStateRepo) CountReviewsSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	const op = "CountReviewsSince"
+
+	const query = `
+		SELECT count(*)
+		FROM review_history
+		WHERE user_id = $1
+			AND reviewed_at >= $2;
+	`
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,293 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// testDBEnv is the DSN of a database migrated with `migrator --up`; the tests
+// that need one are skipped without it. They add their own user and
+// dictionary and delete them afterwards.
+const testDBEnv = "TEST_DB_DSN"
+
+type testCard struct {
+	userID     int64
+	dictWordID string
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDBEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDBEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err = db.PingContext(context.Background()); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+
+	return db
+}
+
+// addTestCard adds a user learning a word of a new dictionary in the forward
+// direction.
+func addTestCard(t *testing.T, db *sql.DB) testCard {
+	t.Helper()
+
+	ctx := context.Background()
+	card := testCard{userID: -time.Now().UnixNano()}
+
+	var dictionaryID string
+	err := db.QueryRowContext(ctx, `
+		INSERT INTO dictionaries (title, mode)
+		VALUES ($1, 'random_pool')
+		RETURNING id;
+	`, fmt.Sprintf("test %d", -card.userID%1e12)).Scan(&dictionaryID)
+	if err != nil {
+		t.Fatalf("add dictionary: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.ExecContext(ctx, `DELETE FROM dictionaries WHERE id = $1;`, dictionaryID)
+		_, _ = db.ExecContext(ctx, `DELETE FROM users WHERE tg_id = $1;`, card.userID)
+	})
+
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO dictionary_words (dictionary_id, spelling)
+		VALUES ($1, 'word')
+		RETURNING id;
+	`, dictionaryID).Scan(&card.dictWordID)
+	if err != nil {
+		t.Fatalf("add word: %v", err)
+	}
+
+	if _, err = db.ExecContext(ctx, `INSERT INTO users (tg_id) VALUES ($1);`, card.userID); err != nil {
+		t.Fatalf("add user: %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO user_dictionaries (user_id, dictionary_id)
+		VALUES ($1, $2);
+	`, card.userID, dictionaryID)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO user_words_state (user_id, dict_word_id, direction)
+		VALUES ($1, $2, 'forward');
+	`, card.userID, card.dictWordID)
+	if err != nil {
+		t.Fatalf("add word state: %v", err)
+	}
+
+	return card
+}
+
+func newTestWordsStateRepo(db *sql.DB) *WordsStateRepo {
+	logger := zerolog.Nop()
+	return NewWordsStateRepo(db, &logger)
+}
+
+func snapshotOf(t *testing.T, repo *WordsStateRepo, card testCard) *domain.WordStateSnapshot {
+	t.Helper()
+
+	snapshot, err := repo.GetStateSnapshot(context.Background(), card.userID, card.dictWordID, domain.CardDirectionForward)
+	if err != nil {
+		t.Fatalf("GetStateSnapshot: %v", err)
+	}
+
+	return snapshot
+}
+
+// gradeInput is the result of grade given to the card at version.
+func gradeInput(card testCard, grade, version int) *domain.ApplyReviewResultInput {
+	now := time.Now().Truncate(time.Microsecond)
+	next := now.AddDate(0, 0, grade)
+
+	return &domain.ApplyReviewResultInput{
+		UserID:     card.userID,
+		DictWordID: card.dictWordID,
+		Direction:  domain.CardDirectionForward,
+		Grade:      grade,
+		Passed:     grade >= domain.MaxGrade,
+		Result: &domain.SM2Result{
+			EF:           2.5,
+			IntervalDays: grade,
+			Repetition:   1,
+			NextReviewAt: next,
+		},
+		ReviewedAt: now,
+		Version:    version,
+	}
+}
+
+// historyGrades returns the grades of the card in the history, oldest first.
+func historyGrades(t *testing.T, db *sql.DB, card testCard) []int {
+	t.Helper()
+
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT grade
+		FROM review_history
+		WHERE user_id = $1 AND dict_word_id = $2
+		ORDER BY id;
+	`, card.userID, card.dictWordID)
+	if err != nil {
+		t.Fatalf("list history: %v", err)
+	}
+	defer rows.Close()
+
+	var grades []int
+	for rows.Next() {
+		var grade int
+		if err = rows.Scan(&grade); err != nil {
+			t.Fatalf("scan history: %v", err)
+		}
+		grades = append(grades, grade)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatalf("list history: %v", err)
+	}
+
+	return grades
+}
+
+func TestApplyReviewResultStaleVersion(t *testing.T) {
+	db := openTestDB(t)
+	repo := newTestWordsStateRepo(db)
+	card := addTestCard(t, db)
+	ctx := context.Background()
+
+	version := snapshotOf(t, repo, card).Version
+	if err := repo.ApplyReviewResult(ctx, gradeInput(card, 3, version)); err != nil {
+		t.Fatalf("ApplyReviewResult: %v", err)
+	}
+
+	err := repo.ApplyReviewResult(ctx, gradeInput(card, 1, version))
+	if !errors.Is(err, domain.ErrWordStateChanged) {
+		t.Fatalf("ApplyReviewResult at a stale version = %v, want %v", err, domain.ErrWordStateChanged)
+	}
+
+	snapshot := snapshotOf(t, repo, card)
+	if snapshot.Version != version+1 || snapshot.LastResult == nil || *snapshot.LastResult != 3 {
+		t.Errorf("state = version %d, last result %v, want version %d, last result 3",
+			snapshot.Version, snapshot.LastResult, version+1)
+	}
+	if grades := historyGrades(t, db, card); len(grades) != 1 || grades[0] != 3 {
+		t.Errorf("history = %v, want [3]", grades)
+	}
+}
+
+func TestApplyReviewResultDoubleGrade(t *testing.T) {
+	db := openTestDB(t)
+	repo := newTestWordsStateRepo(db)
+	card := addTestCard(t, db)
+
+	const graders = 8
+	version := snapshotOf(t, repo, card).Version
+
+	var (
+		wg      sync.WaitGroup
+		start   = make(chan struct{})
+		applied = make(chan int, graders)
+	)
+	for i := range graders {
+		grade := i%domain.MaxGrade + 1
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			err := repo.ApplyReviewResult(context.Background(), gradeInput(card, grade, version))
+			switch {
+			case err == nil:
+				applied <- grade
+			case !errors.Is(err, domain.ErrWordStateChanged):
+				t.Errorf("ApplyReviewResult: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(applied)
+
+	var grades []int
+	for grade := range applied {
+		grades = append(grades, grade)
+	}
+	if len(grades) != 1 {
+		t.Fatalf("grades applied = %v, want exactly one", grades)
+	}
+
+	if history := historyGrades(t, db, card); len(history) != 1 || history[0] != grades[0] {
+		t.Errorf("history = %v, want [%d]", history, grades[0])
+	}
+	if snapshot := snapshotOf(t, repo, card); snapshot.Version != version+1 {
+		t.Errorf("version = %d, want %d", snapshot.Version, version+1)
+	}
+}
+
+func TestRestoreState(t *testing.T) {
+	db := openTestDB(t)
+	repo := newTestWordsStateRepo(db)
+	card := addTestCard(t, db)
+	ctx := context.Background()
+
+	// the card is graded 2, then 3; each snapshot is the state before its
+	// grade
+	first := snapshotOf(t, repo, card)
+	if err := repo.ApplyReviewResult(ctx, gradeInput(card, 2, first.Version)); err != nil {
+		t.Fatalf("ApplyReviewResult: %v", err)
+	}
+	second := snapshotOf(t, repo, card)
+	if err := repo.ApplyReviewResult(ctx, gradeInput(card, 3, second.Version)); err != nil {
+		t.Fatalf("ApplyReviewResult: %v", err)
+	}
+
+	// the first grade is no longer the latest one and can't be undone
+	err := repo.RestoreState(ctx, first)
+	if !errors.Is(err, domain.ErrWordStateChanged) {
+		t.Fatalf("RestoreState of a re-graded card = %v, want %v", err, domain.ErrWordStateChanged)
+	}
+	if grades := historyGrades(t, db, card); len(grades) != 2 {
+		t.Fatalf("history after a failed undo = %v, want [2 3]", grades)
+	}
+
+	if err = repo.RestoreState(ctx, second); err != nil {
+		t.Fatalf("RestoreState: %v", err)
+	}
+
+	restored := snapshotOf(t, repo, card)
+	if restored.LastResult == nil || *restored.LastResult != 2 || restored.IntervalDays != second.IntervalDays {
+		t.Errorf("restored state = last result %v, interval %d, want last result 2, interval %d",
+			restored.LastResult, restored.IntervalDays, second.IntervalDays)
+	}
+	if restored.Version != second.Version+2 {
+		t.Errorf("restored version = %d, want %d", restored.Version, second.Version+2)
+	}
+	if grades := historyGrades(t, db, card); len(grades) != 1 || grades[0] != 2 {
+		t.Errorf("history after undo = %v, want [2]", grades)
+	}
+
+	// the same grade can't be undone twice
+	err = repo.RestoreState(ctx, second)
+	if !errors.Is(err, domain.ErrWordStateChanged) {
+		t.Errorf("second RestoreState = %v, want %v", err, domain.ErrWordStateChanged)
+	}
+}
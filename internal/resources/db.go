@@ -16,12 +16,36 @@ const dbPingTimeout = 5 * time.Second
 func (r *Resources) initDb() error {
 	const op = "resources.initDb"
 
+	db, err := r.openDb(r.Env.DBMaxOpenConns, r.Env.DBMaxIdleConns)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	lockDb, err := r.openDb(r.Env.DBMaxLockConns, r.Env.DBMaxLockConns)
+	if err != nil {
+		_ = db.Close()
+		return fmt.Errorf("%s: locks: %w", op, err)
+	}
+
+	r.Db = db
+	r.LockDb = lockDb
+	log.Logger.Info().Msg("init db connection success")
+
+	return nil
+}
+
+// openDb opens a pool of at most maxOpen connections and checks that the
+// database is reachable.
+func (r *Resources) openDb(maxOpen, maxIdle int) (*sql.DB, error) {
 	db, err := sql.Open("postgres", r.Env.DSN)
 	if err != nil {
-		return fmt.Errorf("%s: open db: %w", op, err)
+		return nil, fmt.Errorf("open db: %w", err)
 	}
 
-	// TODO: adjust db conn settings (idle conn, max conn, etc...)
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(r.Env.DBConnMaxLifetime)
+	db.SetConnMaxIdleTime(r.Env.DBConnMaxIdleTime)
 
 	// TODO: move to readiness probe in app.go
 	ctx, cancel := context.WithTimeout(context.Background(), dbPingTimeout)
@@ -29,11 +53,8 @@ func (r *Resources) initDb() error {
 
 	if err = db.PingContext(ctx); err != nil {
 		_ = db.Close()
-		return fmt.Errorf("%s: ping db: %w", op, err)
+		return nil, fmt.Errorf("ping db: %w", err)
 	}
 
-	r.Db = db
-	log.Logger.Info().Msg("init db connection success")
-
-	return nil
+	return db, nil
 }
@@ -13,6 +13,38 @@ type Env struct {
 	Token   string        `envconfig:"TOKEN" required:"true"`
 	Timeout time.Duration `envconfig:"POLLING_TIMEOUT" default:"10s"`
 	DSN     string        `envconfig:"DB_DSN" required:"true"`
+	// DBMaxOpenConns and DBMaxIdleConns bound the connection pool of the
+	// queries; DBMaxLockConns bounds the separate pool of the advisory locks,
+	// see Resources.LockDb. A lock is held per user being handled and per
+	// running job, so DBMaxLockConns should exceed WebhookMaxConnections.
+	DBMaxOpenConns    int           `envconfig:"DB_MAX_OPEN_CONNS" default:"20"`
+	DBMaxIdleConns    int           `envconfig:"DB_MAX_IDLE_CONNS" default:"10"`
+	DBMaxLockConns    int           `envconfig:"DB_MAX_LOCK_CONNS" default:"50"`
+	DBConnMaxLifetime time.Duration `envconfig:"DB_CONN_MAX_LIFETIME" default:"30m"`
+	DBConnMaxIdleTime time.Duration `envconfig:"DB_CONN_MAX_IDLE_TIME" default:"5m"`
+	// SessionStore is where review and learning sessions are kept: postgres
+	// or memory.
+	SessionStore string `envconfig:"SESSION_STORE" default:"postgres"`
+
+	// Mode is how updates are received: polling or webhook.
+	Mode string `envconfig:"BOT_MODE" default:"polling"`
+	// HTTPListen is the address of the HTTP server with the health
+	// endpoints and, in webhook mode, the webhook.
+	HTTPListen string `envconfig:"HTTP_LISTEN" default:":8080"`
+	// WebhookURL is the public https URL Telegram posts updates to; its path
+	// is served by the HTTP server.
+	WebhookURL    string `envconfig:"WEBHOOK_URL"`
+	WebhookSecret string `envconfig:"WEBHOOK_SECRET"`
+	// WebhookTLSCert and WebhookTLSKey make the HTTP server serve TLS itself
+	// rather than behind a reverse proxy.
+	WebhookTLSCert string `envconfig:"WEBHOOK_TLS_CERT"`
+	WebhookTLSKey  string `envconfig:"WEBHOOK_TLS_KEY"`
+	// WebhookSelfSigned uploads WebhookTLSCert to Telegram, which then trusts
+	// the self-signed certificate.
+	WebhookSelfSigned bool `envconfig:"WEBHOOK_SELF_SIGNED" default:"false"`
+	// WebhookMaxConnections limits the concurrent webhook requests of
+	// Telegram.
+	WebhookMaxConnections int `envconfig:"WEBHOOK_MAX_CONNECTIONS" default:"40"`
 }
 
 func init() {
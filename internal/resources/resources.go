@@ -12,6 +12,10 @@ import (
 type Resources struct {
 	Env *Env
 	Db  *sql.DB
+	// LockDb is the pool the advisory locks hold their connections on, so
+	// that updates waiting for a lock can't take the connections the
+	// handlers run their queries on.
+	LockDb *sql.DB
 }
 
 func MustGet() *Resources {
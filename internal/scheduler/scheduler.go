@@ -0,0 +1,152 @@
+// Package scheduler runs the background jobs of the bot process.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// intervalSlack is the share of Interval a run may start early by: the ticks
+// of an instance don't come exactly Interval after the recorded run.
+const intervalSlack = 0.1
+
+// Job is run every Interval, first right when the scheduler starts. A run
+// isn't started before the previous one is over, in any bot instance, nor
+// sooner than Interval after the last successful run of any instance.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context, now time.Time) error
+}
+
+// Locker elects the instance to run a job when several bot instances run, so
+// that a job isn't run by two of them at once.
+type Locker interface {
+	TryLockJob(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// RunLog keeps the time of the last successful run of every job, shared by
+// the bot instances; the zero time means the job has never run.
+type RunLog interface {
+	LastJobRun(ctx context.Context, name string) (time.Time, error)
+	SaveJobRun(ctx context.Context, name string, at time.Time) error
+}
+
+type Scheduler struct {
+	jobs   []Job
+	locker Locker
+	runs   RunLog
+	logger *zerolog.Logger
+}
+
+func New(locker Locker, runs RunLog, parentLogger *zerolog.Logger) *Scheduler {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+	if locker == nil {
+		panic("Locker cannot be nil")
+	}
+	if runs == nil {
+		panic("RunLog cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "scheduler").Logger()
+
+	return &Scheduler{
+		locker: locker,
+		runs:   runs,
+		logger: &logger,
+	}
+}
+
+// Add registers the job; jobs added after Run are not run.
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Run runs the jobs until ctx is done and waits for the runs in progress.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	logger := s.logger.With().Str("job", job.Name).Logger()
+	logger.Info().Dur("interval", job.Interval).Msg("job started")
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.runElected(ctx, job, &logger); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error().Err(err).Msg("job failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runElected runs the job unless another instance is running it or has run
+// it less than Interval ago; that instance's run stands for this one.
+func (s *Scheduler) runElected(ctx context.Context, job Job, logger *zerolog.Logger) error {
+	unlock, ok, err := s.locker.TryLockJob(ctx, job.Name)
+	if err != nil {
+		return fmt.Errorf("elect instance: %w", err)
+	}
+	if !ok {
+		logger.Debug().Msg("job is run by another instance, skipped")
+		return nil
+	}
+	defer unlock()
+
+	last, err := s.runs.LastJobRun(ctx, job.Name)
+	if err != nil {
+		return fmt.Errorf("load last run: %w", err)
+	}
+
+	now := time.Now()
+	early := time.Duration(float64(job.Interval) * intervalSlack)
+	if !last.IsZero() && now.Before(last.Add(job.Interval-early)) {
+		logger.Debug().Time("last_run", last).Msg("job was run recently, skipped")
+		return nil
+	}
+
+	if err = s.runOnce(ctx, job, now); err != nil {
+		return err
+	}
+
+	if err = s.runs.SaveJobRun(ctx, job.Name, now); err != nil {
+		return fmt.Errorf("save run: %w", err)
+	}
+
+	return nil
+}
+
+// runOnce runs the job, turning a panic into an error so that one bad run
+// doesn't take the bot down.
+func (s *Scheduler) runOnce(ctx context.Context, job Job, now time.Time) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return job.Run(ctx, now)
+}
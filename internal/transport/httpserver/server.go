@@ -0,0 +1,172 @@
+// Package httpserver serves the Telegram webhook and the health endpoints of
+// the bot process.
+package httpserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	tele "gopkg.in/telebot.v4"
+)
+
+const (
+	// secretTokenHeader carries the secret token given to setWebhook in
+	// every webhook request.
+	secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+	readyTimeout    = 2 * time.Second
+	shutdownTimeout = 5 * time.Second
+	// maxUpdateSize bounds the body of a webhook request.
+	maxUpdateSize = 1 << 20
+)
+
+// Processor handles an update; an error means the update is to be delivered
+// again.
+type Processor interface {
+	Process(update tele.Update) error
+}
+
+// Pinger checks that a dependency the bot can't work without is reachable.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type Config struct {
+	// Listen is the address to listen on, e.g. ":8080".
+	Listen string
+	// WebhookPath is where Telegram posts updates; the webhook isn't served
+	// if it's empty.
+	WebhookPath string
+	SecretToken string
+	// TLSCert and TLSKey are the files to serve TLS with; plain HTTP is
+	// served if they are empty, e.g. behind a reverse proxy.
+	TLSCert string
+	TLSKey  string
+}
+
+type Server struct {
+	cfg       Config
+	processor Processor
+	db        Pinger
+	srv       *http.Server
+	logger    *zerolog.Logger
+}
+
+// New builds the server; the webhook hands updates to processor and answers
+// Telegram once an update is handled.
+func New(cfg Config, processor Processor, db Pinger, parentLogger *zerolog.Logger) *Server {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "http_server").Logger()
+
+	s := &Server{
+		cfg:       cfg,
+		processor: processor,
+		db:        db,
+		logger:    &logger,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.live)
+	mux.HandleFunc("GET /readyz", s.ready)
+	if cfg.WebhookPath != "" {
+		mux.HandleFunc("POST "+cfg.WebhookPath, s.webhook)
+	}
+
+	s.srv = &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// Run serves until ctx is done, then shuts the server down gracefully.
+func (s *Server) Run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := s.srv.Shutdown(shutdownCtx); err != nil {
+			s.logger.Error().Err(err).Msg("shutdown failed")
+		}
+	}()
+
+	s.logger.Info().
+		Str("listen", s.cfg.Listen).
+		Bool("tls", s.cfg.TLSCert != "").
+		Bool("webhook", s.cfg.WebhookPath != "").
+		Msg("http server started")
+
+	var err error
+	if s.cfg.TLSCert != "" {
+		err = s.srv.ListenAndServeTLS(s.cfg.TLSCert, s.cfg.TLSKey)
+	} else {
+		err = s.srv.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Error().Err(err).Msg("http server failed")
+		return
+	}
+
+	s.logger.Info().Msg("http server stopped")
+}
+
+// webhook handles an update posted by Telegram. A request without the secret
+// token is rejected; an update that failed is answered with 503, and Telegram
+// delivers it again later.
+func (s *Server) webhook(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get(secretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.SecretToken)) != 1 {
+		s.logger.Warn().Str("remote_addr", r.RemoteAddr).Msg("webhook: invalid secret token")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var update tele.Update
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUpdateSize)).Decode(&update); err != nil {
+		s.logger.Warn().Err(err).Msg("webhook: failed to decode update")
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.processor.Process(update); err != nil {
+		s.logger.Warn().Err(err).Int("update_id", update.ID).Msg("webhook: update failed, to be delivered again")
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// live reports that the process is up.
+func (s *Server) live(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// ready reports whether the bot can handle updates, i.e. the database is
+// reachable.
+func (s *Server) ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	if err := s.db.PingContext(ctx); err != nil {
+		s.logger.Warn().Err(err).Msg("readiness: database unreachable")
+		http.Error(w, "database unreachable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
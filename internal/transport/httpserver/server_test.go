@@ -0,0 +1,121 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	tele "gopkg.in/telebot.v4"
+)
+
+const (
+	testWebhookPath = "/webhook"
+	testSecretToken = "secret"
+)
+
+type processorFunc func(update tele.Update) error
+
+func (f processorFunc) Process(update tele.Update) error {
+	return f(update)
+}
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) PingContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+func newTestServer(processor Processor, db Pinger) *Server {
+	logger := zerolog.Nop()
+	cfg := Config{WebhookPath: testWebhookPath, SecretToken: testSecretToken}
+
+	return New(cfg, processor, db, &logger)
+}
+
+func serve(s *Server, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.srv.Handler.ServeHTTP(rec, r)
+
+	return rec
+}
+
+func TestWebhook(t *testing.T) {
+	errProcess := errors.New("process")
+
+	tests := []struct {
+		name       string
+		token      string
+		body       string
+		processErr error
+		wantStatus int
+		wantUpdate bool
+	}{
+		{"missing secret token", "", `{"update_id":1}`, nil, http.StatusUnauthorized, false},
+		{"wrong secret token", "wrong", `{"update_id":1}`, nil, http.StatusUnauthorized, false},
+		{"correct secret token", testSecretToken, `{"update_id":1}`, nil, http.StatusOK, true},
+		{"malformed update", testSecretToken, `{"update_id":`, nil, http.StatusBadRequest, false},
+		{
+			"update over the size limit", testSecretToken,
+			`{"update_id":1,"message":{"text":"` + strings.Repeat("a", maxUpdateSize) + `"}}`,
+			nil, http.StatusBadRequest, false,
+		},
+		{"update failed", testSecretToken, `{"update_id":1}`, errProcess, http.StatusServiceUnavailable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processed := false
+			s := newTestServer(processorFunc(func(update tele.Update) error {
+				processed = true
+				if update.ID != 1 {
+					t.Errorf("update ID = %d, want 1", update.ID)
+				}
+				return tt.processErr
+			}), nil)
+
+			r := httptest.NewRequest(http.MethodPost, testWebhookPath, strings.NewReader(tt.body))
+			if tt.token != "" {
+				r.Header.Set(secretTokenHeader, tt.token)
+			}
+
+			rec := serve(s, r)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if processed != tt.wantUpdate {
+				t.Errorf("update processed = %v, want %v", processed, tt.wantUpdate)
+			}
+		})
+	}
+}
+
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		pingErr    error
+		wantStatus int
+	}{
+		{"live", "/healthz", nil, http.StatusOK},
+		{"live without the database", "/healthz", errors.New("ping"), http.StatusOK},
+		{"ready", "/readyz", nil, http.StatusOK},
+		{"not ready without the database", "/readyz", errors.New("ping"), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(nil, pingerFunc(func(context.Context) error {
+				return tt.pingErr
+			}))
+
+			rec := serve(s, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
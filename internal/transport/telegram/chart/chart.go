@@ -0,0 +1,15 @@
+// Package chart renders the charts sent to users as PNG images, with the
+// standard library only.
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+var background = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+
+func fill(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
@@ -0,0 +1,66 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+	// glyphScale is how many pixels a dot of a glyph takes on each side.
+	glyphScale = 2
+	// glyphAdvance is the width a character takes, the spacing included.
+	glyphAdvance = (glyphWidth + 1) * glyphScale
+	// textHeight is the height of a line of text.
+	textHeight = glyphHeight * glyphScale
+)
+
+// glyphs are the dots of the characters labels are made of, a row per
+// string. Other characters are drawn as spaces.
+var glyphs = map[rune][glyphHeight]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", ".##", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	'-': {"...", "...", "###", "...", "..."},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+}
+
+// textWidth is the width the text takes when drawn.
+func textWidth(text string) int {
+	return len([]rune(text))*glyphAdvance - glyphScale
+}
+
+// drawText draws the text with its top left corner at the point.
+func drawText(img draw.Image, at image.Point, text string, c color.Color) {
+	ink := &image.Uniform{C: c}
+
+	for i, r := range []rune(text) {
+		glyph, ok := glyphs[r]
+		if !ok {
+			continue
+		}
+
+		x0 := at.X + i*glyphAdvance
+		for row, dots := range glyph {
+			for col, dot := range dots {
+				if dot != '#' {
+					continue
+				}
+
+				x, y := x0+col*glyphScale, at.Y+row*glyphScale
+				draw.Draw(img, image.Rect(x, y, x+glyphScale, y+glyphScale), ink, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+const (
+	heatmapCell    = 12
+	heatmapGap     = 3
+	heatmapPadding = 12
+)
+
+var (
+	// heatmapLevels shade the days from no activity to twice the goal.
+	heatmapLevels = []color.RGBA{
+		{R: 0xeb, G: 0xed, B: 0xf0, A: 0xff},
+		{R: 0x9b, G: 0xe9, B: 0xa8, A: 0xff},
+		{R: 0x40, G: 0xc4, B: 0x63, A: 0xff},
+		{R: 0x30, G: 0xa1, B: 0x4e, A: 0xff},
+		{R: 0x21, G: 0x6e, B: 0x39, A: 0xff},
+	}
+	heatmapFrozen = color.RGBA{R: 0x9e, G: 0xd4, B: 0xf7, A: 0xff}
+)
+
+// Heatmap draws the days as a GitHub-style activity calendar: a column per
+// week and a row per weekday from Monday, shaded by the progress towards the
+// goal. Days covered by a streak freeze are blue. The days must be
+// consecutive, starting on a Monday.
+func Heatmap(days []domain.DayActivity, goal domain.DailyGoal) ([]byte, error) {
+	weeks := (len(days) + 6) / 7
+	width := 2*heatmapPadding + weeks*(heatmapCell+heatmapGap) - heatmapGap
+	height := 2*heatmapPadding + 7*(heatmapCell+heatmapGap) - heatmapGap
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fill(img, img.Bounds(), background)
+
+	for i, day := range days {
+		x := heatmapPadding + i/7*(heatmapCell+heatmapGap)
+		y := heatmapPadding + i%7*(heatmapCell+heatmapGap)
+
+		shade := heatmapLevels[heatmapLevel(goal.Progress(day), goal.Target)]
+		if day.Frozen && !goal.Met(day) {
+			shade = heatmapFrozen
+		}
+		fill(img, image.Rect(x, y, x+heatmapCell, y+heatmapCell), shade)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode heatmap: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// heatmapLevel is the shade of the progress: none, under half of the goal,
+// under the goal, under twice the goal and beyond.
+func heatmapLevel(progress, target int) int {
+	switch {
+	case progress <= 0:
+		return 0
+	case 2*progress < target:
+		return 1
+	case progress < target:
+		return 2
+	case progress < 2*target:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,102 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+const (
+	barWidth = 14
+	barGap   = 4
+	// barLabelEvery is how many bars apart the dates are labelled.
+	barLabelEvery = 7
+	// barLabelLayout is the layout of the date labels.
+	barLabelLayout = "02.01"
+
+	panelHeight  = 150
+	panelPadding = 14
+	// axisWidth leaves room for the labels of the value axis.
+	axisWidth = 5*glyphAdvance + 6
+)
+
+var (
+	axisColor     = color.RGBA{R: 0xc8, G: 0xcc, B: 0xd0, A: 0xff}
+	labelColor    = color.RGBA{R: 0x57, G: 0x60, B: 0x6a, A: 0xff}
+	reviewsColor  = color.RGBA{R: 0x40, G: 0xc4, B: 0x63, A: 0xff}
+	forecastColor = color.RGBA{R: 0x4a, G: 0x90, B: 0xd9, A: 0xff}
+)
+
+// Stats draws the grades per day above the forecast of due words, each as a
+// bar chart with the dates under every barLabelEvery-th bar.
+func Stats(stats *domain.UserStats) ([]byte, error) {
+	panels := []struct {
+		days  []domain.DayCount
+		color color.RGBA
+	}{
+		{stats.ReviewsPerDay, reviewsColor},
+		{stats.DueForecast, forecastColor},
+	}
+
+	bars := 0
+	for _, p := range panels {
+		bars = max(bars, len(p.days))
+	}
+
+	width := 2*panelPadding + axisWidth + bars*(barWidth+barGap)
+	rowHeight := panelHeight + textHeight + 2*panelPadding
+	height := len(panels) * rowHeight
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fill(img, img.Bounds(), background)
+
+	for i, p := range panels {
+		origin := image.Point{X: panelPadding, Y: i*rowHeight + panelPadding}
+		drawBars(img, origin, p.days, p.color)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode stats: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// drawBars draws a bar chart of the days with its top left corner at origin.
+// The value axis runs from 0 to the largest count.
+func drawBars(img draw.Image, origin image.Point, days []domain.DayCount, shade color.RGBA) {
+	top := 1
+	for _, d := range days {
+		top = max(top, d.Count)
+	}
+
+	plotLeft := origin.X + axisWidth
+	baseline := origin.Y + panelHeight
+
+	topLabel := strconv.Itoa(top)
+	drawText(img, image.Point{X: plotLeft - 6 - textWidth(topLabel), Y: origin.Y}, topLabel, labelColor)
+	drawText(img, image.Point{X: plotLeft - 6 - textWidth("0"), Y: baseline - textHeight}, "0", labelColor)
+
+	plotRight := plotLeft + len(days)*(barWidth+barGap)
+	fill(img, image.Rect(plotLeft, baseline, plotRight, baseline+1), axisColor)
+	fill(img, image.Rect(plotLeft, origin.Y, plotRight, origin.Y+1), axisColor)
+
+	for i, d := range days {
+		x := plotLeft + i*(barWidth+barGap) + barGap/2
+		if d.Count > 0 {
+			h := max(d.Count*panelHeight/top, 1)
+			fill(img, image.Rect(x, baseline-h, x+barWidth, baseline), shade)
+		}
+
+		if i%barLabelEvery == 0 {
+			drawText(img, image.Point{X: x, Y: baseline + 6}, d.Day.Format(barLabelLayout), labelColor)
+		}
+	}
+}
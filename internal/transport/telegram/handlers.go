@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"strconv"
@@ -12,21 +13,32 @@ import (
 	tele "gopkg.in/telebot.v4"
 
 	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/chart"
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/i18n"
 	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/ui"
 )
 
 const handlerCtxTimeout = 5 * time.Second
 
+// reviewAllArg is the /review argument for a cross-dictionary round.
+const reviewAllArg = "all"
+
 var _ Handlers = (*BotHandlers)(nil)
 
 // TODO: add recovering from panics somewhere
 type BotHandlers struct {
-	onboardUC OnboardingUsecase
-	catalogUC CatalogUsecase
-	subsUC    SubscriptionUsecase
-	learnUC   LearningUsecase
-	reviewUC  ReviewUsecase
-	logger    *zerolog.Logger
+	onboardUC  OnboardingUsecase
+	catalogUC  CatalogUsecase
+	subsUC     SubscriptionUsecase
+	learnUC    LearningUsecase
+	reviewUC   ReviewUsecase
+	wordsUC    WordsUsecase
+	reminderUC ReminderUsecase
+	settingsUC SettingsUsecase
+	streakUC   StreakUsecase
+	statsUC    StatsUsecase
+	digestUC   DigestUsecase
+	logger     *zerolog.Logger
 }
 
 func NewHandler(
@@ -35,6 +47,12 @@ func NewHandler(
 	subsUC SubscriptionUsecase,
 	learnUC LearningUsecase,
 	reviewUC ReviewUsecase,
+	wordsUC WordsUsecase,
+	reminderUC ReminderUsecase,
+	settingsUC SettingsUsecase,
+	streakUC StreakUsecase,
+	statsUC StatsUsecase,
+	digestUC DigestUsecase,
 	parentLogger *zerolog.Logger,
 ) *BotHandlers {
 	if parentLogger == nil {
@@ -56,16 +74,40 @@ func NewHandler(
 	if reviewUC == nil {
 		panic("ReviewUsecase cannot be nil")
 	}
+	if wordsUC == nil {
+		panic("WordsUsecase cannot be nil")
+	}
+	if reminderUC == nil {
+		panic("ReminderUsecase cannot be nil")
+	}
+	if settingsUC == nil {
+		panic("SettingsUsecase cannot be nil")
+	}
+	if streakUC == nil {
+		panic("StreakUsecase cannot be nil")
+	}
+	if statsUC == nil {
+		panic("StatsUsecase cannot be nil")
+	}
+	if digestUC == nil {
+		panic("DigestUsecase cannot be nil")
+	}
 
 	logger := parentLogger.With().Str("component", "telegram_handler").Logger()
 
 	return &BotHandlers{
-		onboardUC: onboardUC,
-		catalogUC: catalogUC,
-		subsUC:    subsUC,
-		learnUC:   learnUC,
-		reviewUC:  reviewUC,
-		logger:    &logger,
+		onboardUC:  onboardUC,
+		catalogUC:  catalogUC,
+		subsUC:     subsUC,
+		learnUC:    learnUC,
+		reviewUC:   reviewUC,
+		wordsUC:    wordsUC,
+		reminderUC: reminderUC,
+		settingsUC: settingsUC,
+		streakUC:   streakUC,
+		statsUC:    statsUC,
+		digestUC:   digestUC,
+		logger:     &logger,
 	}
 }
 
@@ -78,6 +120,7 @@ func (h *BotHandlers) Start(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	// TODO: remove personal data from logs after alfa-test
 	ctxLogger := h.logger.With().
@@ -91,16 +134,18 @@ func (h *BotHandlers) Start(c tele.Context) error {
 	if err := h.onboardUC.Start(ctx, userID, username); err != nil {
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	ctxLogger.Debug().Msgf("%s handled", op)
 
-	return c.Send(ui.WelcomeMsg, ui.BuildMainMenuReplyKb())
+	return c.Send(loc.T(ui.WelcomeMsg), ui.BuildMainMenuReplyKb(loc))
 }
 
 func (h *BotHandlers) Help(c tele.Context) error {
-	return c.Send(ui.HelpMsg, ui.BuildMainMenuReplyKb())
+	loc := i18n.From(c)
+
+	return c.Send(loc.T(ui.HelpMsg), ui.BuildMainMenuReplyKb(loc))
 }
 
 func (h *BotHandlers) RemoveMe(c tele.Context) error {
@@ -112,6 +157,7 @@ func (h *BotHandlers) RemoveMe(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	// TODO: remove personal data from logs after alfa-test
 	ctxLogger := h.logger.With().
@@ -125,12 +171,100 @@ func (h *BotHandlers) RemoveMe(c tele.Context) error {
 	if err := h.onboardUC.RemoveMe(ctx, userID); err != nil {
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	ctxLogger.Info().Msgf("%s handled", op)
 
-	return c.Send(ui.RemoveMsg, ui.BuildMainMenuReplyKb())
+	return c.Send(loc.T(ui.RemoveMsg), ui.BuildMainMenuReplyKb(loc))
+}
+
+func (h *BotHandlers) Language(c tele.Context) error {
+	const op = "Language"
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	args := c.Args()
+	if len(args) == 0 {
+		return c.Send(
+			loc.T(ui.LanguageChooseMsg),
+			&tele.SendOptions{ReplyMarkup: ui.BuildLanguageInlineKb()},
+		)
+	}
+	if len(args) != 1 {
+		ctxLogger.Debug().Int("args", len(args)).Msgf("%s: incorrect num of args", op)
+
+		return c.Send(loc.Tf(ui.LanguageUsageMsg, strings.Join(i18n.Supported(), ", ")), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return h.setLanguage(c, strings.Trim(strings.TrimSpace(args[0]), "<>"), ctxLogger, op)
+}
+
+func (h *BotHandlers) SetLanguage(c tele.Context) error {
+	const op = "SetLanguage"
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	if c.Callback() != nil {
+		defer func() {
+			_ = c.Respond()
+			if err := c.Delete(); err != nil {
+				ctxLogger.Warn().Err(err).Msgf("%s: failed to delete language message", op)
+			}
+		}()
+	}
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	return h.setLanguage(c, extractCallbackData(c), ctxLogger, op)
+}
+
+func (h *BotHandlers) setLanguage(c tele.Context, rawCode string, ctxLogger zerolog.Logger, op string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	loc := i18n.From(c)
+
+	code, ok := i18n.Normalize(rawCode)
+	if !ok {
+		ctxLogger.Debug().Str("language_code", rawCode).Msgf("%s: unsupported language", op)
+
+		return c.Send(loc.Tf(ui.LanguageUsageMsg, strings.Join(i18n.Supported(), ", ")), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	if err := h.onboardUC.SetLanguage(ctx, c.Sender().ID, c.Sender().Username, code); err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	loc = i18n.For(code)
+	i18n.Set(c, loc)
+
+	ctxLogger.Debug().Str("language_code", code).Msgf("%s handled", op)
+
+	return c.Send(loc.T(ui.LanguageChangedMsg), ui.BuildMainMenuReplyKb(loc))
 }
 
 func (h *BotHandlers) Dict(c tele.Context) error {
@@ -147,6 +281,7 @@ func (h *BotHandlers) Dict(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	// TODO: remove personal data from logs after alfa-test
 	ctxLogger := h.logger.With().
@@ -161,30 +296,30 @@ func (h *BotHandlers) Dict(c tele.Context) error {
 	if err != nil {
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	if len(dictionaries) == 0 {
 		ctxLogger.Warn().Msgf("%s: no public dicts found", op)
 
-		return c.Send(ui.PublicDictionariesEmptyMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.PublicDictionariesEmptyMsg), ui.BuildMainMenuReplyKb(loc))
 	}
-	if err = c.Send(ui.PublicDictionariesHeaderMsg, ui.BuildMainMenuReplyKb()); err != nil {
+	if err = c.Send(loc.T(ui.PublicDictionariesHeaderMsg), ui.BuildMainMenuReplyKb(loc)); err != nil {
 		ctxLogger.Error().Err(err).Msgf("%s failed sent main_menu_kb", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 	for _, d := range dictionaries {
 		if err = c.Send(
-			ui.FormatDictionaryCard(d),
+			ui.FormatDictionaryCard(loc, d),
 			&tele.SendOptions{
 				ParseMode:   tele.ModeHTML,
-				ReplyMarkup: ui.BuildPublicDictionaryInlineKb(d.ID),
+				ReplyMarkup: ui.BuildPublicDictionaryInlineKb(loc, d.ID),
 			},
 		); err != nil {
 			ctxLogger.Error().Err(err).Msgf("%s failed send dict", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 	}
 
@@ -207,6 +342,7 @@ func (h *BotHandlers) MyDict(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	// TODO: remove personal data from logs after alfa-test
 	ctxLogger := h.logger.With().
@@ -221,30 +357,30 @@ func (h *BotHandlers) MyDict(c tele.Context) error {
 	if err != nil {
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	if len(dictionaries) == 0 {
 		ctxLogger.Debug().Msgf("%s: user doesn't have subscribed dicts", op)
 
-		return c.Send(ui.UserDictionariesEmptyMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.UserDictionariesEmptyMsg), ui.BuildMainMenuReplyKb(loc))
 	}
-	if err = c.Send(ui.UserDictionariesHeaderMsg, ui.BuildMainMenuReplyKb()); err != nil {
+	if err = c.Send(loc.T(ui.UserDictionariesHeaderMsg), ui.BuildMainMenuReplyKb(loc)); err != nil {
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 	for i, d := range dictionaries {
 		if err = c.Send(
-			ui.FormatSubscribedDictionaryCard(i+1, d),
+			ui.FormatSubscribedDictionaryCard(loc, i+1, d),
 			&tele.SendOptions{
 				ParseMode:   tele.ModeHTML,
-				ReplyMarkup: ui.BuildUserDictionaryInlineKb(d.ID),
+				ReplyMarkup: ui.BuildUserDictionaryInlineKb(loc, d.ID),
 			},
 		); err != nil {
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 	}
 
@@ -267,6 +403,7 @@ func (h *BotHandlers) DictDetails(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -281,7 +418,7 @@ func (h *BotHandlers) DictDetails(c tele.Context) error {
 		// TODO: alert here
 		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
 
-		return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	ctxLogger = ctxLogger.With().Str("dictionary_id", dictionaryID).Logger()
@@ -293,22 +430,22 @@ func (h *BotHandlers) DictDetails(c tele.Context) error {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s: unable to find dictionary by dictionary_id", op)
 
-			return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 
 		default:
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 	}
 
 	ctxLogger.Debug().Msgf("%s handled", op)
 
 	return c.Send(
-		ui.FormatDictionaryDetails(*details.Dictionary, details.Words),
+		ui.FormatDictionaryDetails(loc, *details.Dictionary, details.Words),
 		&tele.SendOptions{
 			ParseMode:   tele.ModeHTML,
-			ReplyMarkup: ui.BuildDictionaryDetailsInlineKb(details.Dictionary.ID),
+			ReplyMarkup: ui.BuildDictionaryDetailsInlineKb(loc, details.Dictionary.ID),
 		},
 	)
 }
@@ -327,6 +464,7 @@ func (h *BotHandlers) Subscribe(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	// TODO: remove personal data from logs after alfa-test
 	ctxLogger := h.logger.With().
@@ -342,7 +480,7 @@ func (h *BotHandlers) Subscribe(c tele.Context) error {
 		// TODO: alert here
 		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
 
-		return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	ctxLogger = ctxLogger.With().Str("dictionary_id", dictionaryID).Logger()
@@ -353,23 +491,23 @@ func (h *BotHandlers) Subscribe(c tele.Context) error {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s: unable to find dictionary by dictionary_id", op)
 
-			return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 
 		case errors.Is(err, domain.ErrAlreadySubscribed):
 			ctxLogger.Debug().Msgf("%s: already subscribed", op)
 
-			return c.Send(ui.DictionaryAlreadySubscribedMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.DictionaryAlreadySubscribedMsg), ui.BuildMainMenuReplyKb(loc))
 
 		default:
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 	}
 
 	ctxLogger.Debug().Msgf("%s handled", op)
 
-	return c.Send(ui.DictionarySubscribedMsg, ui.BuildMainMenuReplyKb())
+	return c.Send(loc.T(ui.DictionarySubscribedMsg), ui.BuildMainMenuReplyKb(loc))
 }
 
 func (h *BotHandlers) Unsubscribe(c tele.Context) error {
@@ -386,6 +524,7 @@ func (h *BotHandlers) Unsubscribe(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	// TODO: remove personal data from logs after alfa-test
 	ctxLogger := h.logger.With().
@@ -401,7 +540,7 @@ func (h *BotHandlers) Unsubscribe(c tele.Context) error {
 		// TODO: alert here
 		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
 
-		return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	ctxLogger = ctxLogger.With().Str("dictionary_id", dictionaryID).Logger()
@@ -412,25 +551,25 @@ func (h *BotHandlers) Unsubscribe(c tele.Context) error {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s: unable to find dictionary by dictionary_id", op)
 
-			return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 
 		case errors.Is(err, domain.ErrSubscriptionNotFound):
 			ctxLogger.Debug().Msgf("%s: not subscribed", op)
 
-			return c.Send(ui.DictionarySubscriptionNotFoundMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.DictionarySubscriptionNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 
 		default:
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 	}
 
 	ctxLogger.Debug().Msgf("%s confirmation requested", op)
 
 	return c.Send(
-		ui.DictionaryUnsubscribeConfirmMsg,
-		&tele.SendOptions{ReplyMarkup: ui.BuildUnsubscribeConfirmInlineKb(dictionaryID)},
+		loc.T(ui.DictionaryUnsubscribeConfirmMsg),
+		&tele.SendOptions{ReplyMarkup: ui.BuildUnsubscribeConfirmInlineKb(loc, dictionaryID)},
 	)
 }
 
@@ -443,6 +582,7 @@ func (h *BotHandlers) ConfirmUnsubscribe(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	// TODO: remove personal data from logs after alfa-test
 	ctxLogger := h.logger.With().
@@ -467,7 +607,7 @@ func (h *BotHandlers) ConfirmUnsubscribe(c tele.Context) error {
 		// TODO: alert here
 		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
 
-		return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	ctxLogger = ctxLogger.With().Str("dictionary_id", dictionaryID).Logger()
@@ -481,23 +621,23 @@ func (h *BotHandlers) ConfirmUnsubscribe(c tele.Context) error {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s: unable to find dictionary by dictionary_id", op)
 
-			return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 
 		case errors.Is(err, domain.ErrSubscriptionNotFound):
 			ctxLogger.Debug().Msgf("%s: not subscribed", op)
 
-			return c.Send(ui.DictionarySubscriptionNotFoundMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.DictionarySubscriptionNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 
 		default:
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 	}
 
 	ctxLogger.Debug().Msgf("%s handled", op)
 
-	return c.Send(ui.DictionaryUnsubscribedMsg, ui.BuildMainMenuReplyKb())
+	return c.Send(loc.T(ui.DictionaryUnsubscribedMsg), ui.BuildMainMenuReplyKb(loc))
 }
 
 func (h *BotHandlers) RejectUnsubscribe(c tele.Context) error {
@@ -508,6 +648,7 @@ func (h *BotHandlers) RejectUnsubscribe(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	// TODO: remove personal data from logs after alfa-test
 	ctxLogger := h.logger.With().
@@ -527,7 +668,127 @@ func (h *BotHandlers) RejectUnsubscribe(c tele.Context) error {
 
 	ctxLogger.Debug().Msgf("%s handled", op)
 
-	return c.Send(ui.DictionaryUnsubscribeCanceledMsg, ui.BuildMainMenuReplyKb())
+	return c.Send(loc.T(ui.DictionaryUnsubscribeCanceledMsg), ui.BuildMainMenuReplyKb(loc))
+}
+
+func (h *BotHandlers) CardDirections(c tele.Context) error {
+	const op = "CardDirections"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+	if c.Callback() != nil {
+		defer func() {
+			_ = c.Respond()
+		}()
+	}
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	dictionaryID := extractCallbackDictionaryID(c)
+	if dictionaryID == "" {
+		// TODO: alert here
+		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
+
+		return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger = ctxLogger.With().Str("dictionary_id", dictionaryID).Logger()
+
+	directions, err := h.subsUC.CardDirections(ctx, userID, dictionaryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSubscriptionNotFound):
+			ctxLogger.Debug().Msgf("%s: not subscribed", op)
+
+			return c.Send(loc.T(ui.DictionarySubscriptionNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
+
+		default:
+			ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+		}
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Send(
+		loc.T(ui.CardDirectionsChooseMsg),
+		&tele.SendOptions{ReplyMarkup: ui.BuildCardDirectionsInlineKb(loc, dictionaryID, directions)},
+	)
+}
+
+func (h *BotHandlers) SetCardDirections(c tele.Context) error {
+	const op = "SetCardDirections"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	args := c.Args()
+	if c.Callback() == nil || len(args) != 2 {
+		// TODO: alert here
+		ctxLogger.Error().Int("args", len(args)).Msgf("%s: unexpected callback data", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	dictionaryID := strings.TrimSpace(args[0])
+	directions := domain.CardDirections(strings.TrimSpace(args[1]))
+
+	ctxLogger = ctxLogger.With().
+		Str("dictionary_id", dictionaryID).
+		Str("card_directions", string(directions)).
+		Logger()
+
+	if err := h.subsUC.SetCardDirections(ctx, userID, dictionaryID, directions); err != nil {
+		_ = c.Respond()
+
+		switch {
+		case errors.Is(err, domain.ErrSubscriptionNotFound):
+			ctxLogger.Debug().Msgf("%s: not subscribed", op)
+
+			return c.Send(loc.T(ui.DictionarySubscriptionNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
+
+		default:
+			ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+		}
+	}
+
+	_ = c.Respond(&tele.CallbackResponse{Text: loc.T(ui.CardDirectionsChangedMsg)})
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Edit(
+		loc.T(ui.CardDirectionsChooseMsg),
+		&tele.SendOptions{ReplyMarkup: ui.BuildCardDirectionsInlineKb(loc, dictionaryID, directions)},
+	)
 }
 
 func (h *BotHandlers) LearnByDictNum(c tele.Context) error {
@@ -544,6 +805,7 @@ func (h *BotHandlers) LearnByDictNum(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -557,7 +819,7 @@ func (h *BotHandlers) LearnByDictNum(c tele.Context) error {
 	if len(args) != 1 {
 		ctxLogger.Debug().Int("args", len(args)).Msgf("%s: incorrect num of args", op)
 
-		return c.Send(ui.LearnUsageMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.LearnUsageMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	trimmed := strings.Trim(strings.TrimSpace(args[0]), "<>")
@@ -568,7 +830,7 @@ func (h *BotHandlers) LearnByDictNum(c tele.Context) error {
 			Str("args[0]", args[0]).
 			Msgf("%s: error converting arg to int", op)
 
-		return c.Send(ui.LearnUsageMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.LearnUsageMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	word, dictionaryID, err := h.learnUC.LearnByDictionaryNumber(ctx, userID, number)
@@ -586,14 +848,14 @@ func (h *BotHandlers) LearnByDictNum(c tele.Context) error {
 		// TODO: alert here
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	return c.Send(
-		ui.FormatLearningWordCard(*word),
+		ui.FormatLearningWordCard(loc, *word),
 		&tele.SendOptions{
 			ParseMode:   tele.ModeHTML,
-			ReplyMarkup: ui.BuildLearningReplyKb(),
+			ReplyMarkup: ui.BuildLearningReplyKb(loc),
 		},
 	)
 }
@@ -612,6 +874,7 @@ func (h *BotHandlers) LearnByDictID(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -626,7 +889,7 @@ func (h *BotHandlers) LearnByDictID(c tele.Context) error {
 		// TODO: alert here
 		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
 
-		return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	word, err := h.learnUC.LearnByDictionaryID(ctx, userID, dictionaryID)
@@ -643,14 +906,14 @@ func (h *BotHandlers) LearnByDictID(c tele.Context) error {
 
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	return c.Send(
-		ui.FormatLearningWordCard(*word),
+		ui.FormatLearningWordCard(loc, *word),
 		&tele.SendOptions{
 			ParseMode:   tele.ModeHTML,
-			ReplyMarkup: ui.BuildLearningReplyKb(),
+			ReplyMarkup: ui.BuildLearningReplyKb(loc),
 		},
 	)
 }
@@ -669,6 +932,7 @@ func (h *BotHandlers) LearningAction(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -678,7 +942,8 @@ func (h *BotHandlers) LearningAction(c tele.Context) error {
 
 	ctxLogger.Debug().Msgf("handling %s", op)
 
-	switch c.Text() {
+	key, _ := i18n.KeyByText(c.Text())
+	switch key {
 	case ui.LearnAddText:
 		return h.handleLearningDecision(ctx, userID, h.learnUC.AddCurrentWord, ctxLogger, op, c)
 	case ui.LearnBlockText:
@@ -690,13 +955,13 @@ func (h *BotHandlers) LearningAction(c tele.Context) error {
 		if err != nil {
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 		if dictionaryID == "" {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s: unable to define active dictionary", op)
 
-			return c.Send(ui.ActiveDictMissingMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.ActiveDictMissingMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
 		if err = h.reviewUC.PrepareByDictionaryID(ctx, userID, dictionaryID); err != nil {
@@ -713,18 +978,18 @@ func (h *BotHandlers) LearningAction(c tele.Context) error {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
-		return c.Send(ui.ReviewIntroMsg, ui.BuildReviewIntroReplyKb())
+		return c.Send(loc.T(ui.ReviewIntroMsg), ui.BuildReviewIntroReplyKb(loc))
 	case ui.ToMainMenuText:
 		if err := h.learnUC.Back(ctx, userID); err != nil {
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
-		return c.Send(ui.ToMainMenuMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.ToMainMenuMsg), ui.BuildMainMenuReplyKb(loc))
 	default:
 		return nil
 	}
@@ -738,19 +1003,21 @@ func (h *BotHandlers) handleLearningDecision(
 	op string,
 	c tele.Context,
 ) error {
+	loc := i18n.From(c)
+
 	word, err := decisionFn(ctx, userID)
 	if err != nil {
 		dictionaryID, errActDict := h.learnUC.ActiveDictionaryID(ctx, userID)
 		if errActDict != nil {
 			ctxLogger.Error().Err(errActDict).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 		if dictionaryID == "" {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s: unable to define active dictionary", op)
 
-			return c.Send(ui.ActiveDictMissingMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.ActiveDictMissingMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
 		mapped := mapper.MapLearningErrorToUI(err)
@@ -765,32 +1032,30 @@ func (h *BotHandlers) handleLearningDecision(
 
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
 	return c.Send(
-		ui.FormatLearningWordCard(*word),
+		ui.FormatLearningWordCard(loc, *word),
 		&tele.SendOptions{
 			ParseMode:   tele.ModeHTML,
-			ReplyMarkup: ui.BuildLearningReplyKb(),
+			ReplyMarkup: ui.BuildLearningReplyKb(loc),
 		},
 	)
 }
 
-func (h *BotHandlers) ReviewByDictNum(c tele.Context) error {
-	const op = "ReviewByDictNum"
+// LearnBatch shows a batch of new words of the active dictionary, to be
+// quizzed right after.
+func (h *BotHandlers) LearnBatch(c tele.Context) error {
+	const op = "LearnBatch"
 
 	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
 	defer cancel()
-	if c.Callback() != nil {
-		defer func() {
-			_ = c.Respond()
-		}()
-	}
 
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -800,59 +1065,40 @@ func (h *BotHandlers) ReviewByDictNum(c tele.Context) error {
 
 	ctxLogger.Debug().Msgf("handling %s", op)
 
-	args := c.Args()
-	if len(args) != 1 {
-		ctxLogger.Debug().Int("args", len(args)).Msgf("%s: incorrect num of args", op)
-
-		return c.Send(ui.ReviewUsageMsg, ui.BuildMainMenuReplyKb())
-	}
-
-	trimmed := strings.Trim(strings.TrimSpace(args[0]), "<>")
-	number, convErr := strconv.Atoi(trimmed)
-	if convErr != nil {
-		ctxLogger.Debug().
-			Err(convErr).
-			Str("args[0]", args[0]).
-			Msgf("%s: error converting arg to int", op)
-
-		return c.Send(ui.ReviewUsageMsg, ui.BuildMainMenuReplyKb())
-	}
-
-	dictionaryID, err := h.reviewUC.PrepareByDictionaryNumber(ctx, userID, number)
+	batch, err := h.learnUC.StartBatch(ctx, userID)
 	if err != nil {
-		mapped := mapper.MapReviewErrorToUI(err)
-		if mapped.State() != mapper.ReviewUIUnknown {
-			ctxLogger.Debug().
-				Err(err).
-				Str("dictionary_id", dictionaryID).
-				Msgf("%s handled with mapped review error", op)
+		mapped := mapper.MapLearningErrorToUI(err)
+		if mapped.State() != mapper.LearningUIUnknown {
+			ctxLogger.Debug().Err(err).Msgf("%s handled with mapped learning error", op)
 
-			return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+			return mapper.SendLearningMappedError(c, mapped, "")
 		}
 
-		// TODO: alert here
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
-	return c.Send(ui.ReviewIntroMsg, ui.BuildReviewIntroReplyKb())
+	return c.Send(
+		ui.FormatLearnBatch(loc, batch),
+		&tele.SendOptions{
+			ParseMode:   tele.ModeHTML,
+			ReplyMarkup: ui.BuildLearnBatchInlineKb(loc, batch.ID),
+		},
+	)
 }
 
-func (h *BotHandlers) ReviewByDictID(c tele.Context) error {
-	const op = "ReviewByDictID"
+// LearnBatchQuiz hides the batch list and starts the quiz on it.
+func (h *BotHandlers) LearnBatchQuiz(c tele.Context) error {
+	const op = "LearnBatchQuiz"
 
 	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
 	defer cancel()
-	if c.Callback() != nil {
-		defer func() {
-			_ = c.Respond()
-		}()
-	}
 
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -862,48 +1108,38 @@ func (h *BotHandlers) ReviewByDictID(c tele.Context) error {
 
 	ctxLogger.Debug().Msgf("handling %s", op)
 
-	dictionaryID := extractCallbackDictionaryID(c)
-	if dictionaryID == "" {
-		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
-
-		return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
-	}
-
-	err := h.reviewUC.PrepareByDictionaryID(ctx, userID, dictionaryID)
+	card, err := h.learnUC.StartBatchQuiz(ctx, userID, extractCallbackData(c))
 	if err != nil {
-		mapped := mapper.MapReviewErrorToUI(err)
-		if mapped.State() != mapper.ReviewUIUnknown {
-			ctxLogger.Debug().
-				Err(err).
-				Str("dictionary_id", dictionaryID).
-				Msgf("%s handled with mapped review error", op)
+		if errors.Is(err, domain.ErrStaleLearnCard) {
+			ctxLogger.Debug().Msgf("%s: stale batch button", op)
 
-			return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+			return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
 		}
+		_ = c.Respond()
 
-		// TODO: alert here
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	_ = c.Respond()
+
+	if editErr := c.Edit(loc.Tf(ui.LearnBatchQuizStartedMsg, card.Left)); editErr != nil {
+		ctxLogger.Error().Err(editErr).Msgf("%s: failed to hide batch words", op)
 	}
 
-	return c.Send(ui.ReviewIntroMsg, ui.BuildReviewIntroReplyKb())
+	return sendLearnBatchCard(c, loc, card)
 }
 
-func (h *BotHandlers) ReviewForce(c tele.Context) error {
-	const op = "ReviewForce"
+func (h *BotHandlers) LearnBatchAnswer(c tele.Context) error {
+	const op = "LearnBatchAnswer"
 
 	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
 	defer cancel()
-	if c.Callback() != nil {
-		defer func() {
-			_ = c.Respond()
-		}()
-	}
 
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -913,45 +1149,271 @@ func (h *BotHandlers) ReviewForce(c tele.Context) error {
 
 	ctxLogger.Debug().Msgf("handling %s", op)
 
-	dictionaryID, err := h.reviewUC.ActiveDictionaryID(ctx, userID)
-	if err != nil {
-		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+	args := c.Args()
+	if len(args) != 2 {
+		ctxLogger.Error().Msgf("%s: unexpected callback data %q", op, c.Data())
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
 	}
-	if dictionaryID == "" {
-		ctxLogger.Debug().Msgf("%s: active dictionary is empty", op)
+	option, err := strconv.Atoi(args[1])
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s: unexpected callback data %q", op, c.Data())
 
-		return c.Send(ui.ActiveDictMissingMsg, ui.BuildMainMenuReplyKb())
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
 	}
 
-	word, err := h.reviewUC.StartForceRound(ctx, userID, dictionaryID)
-	if err != nil {
-		mapped := mapper.MapReviewErrorToUI(err)
-		if mapped.State() != mapper.ReviewUIUnknown {
-			ctxLogger.Debug().
-				Err(err).
-				Str("dictionary_id", dictionaryID).
-				Msgf("%s handled with mapped review error", op)
+	result, card, err := h.learnUC.AnswerBatchQuiz(ctx, userID, args[0], option)
+	if errors.Is(err, domain.ErrStaleLearnCard) {
+		ctxLogger.Debug().Msgf("%s: stale quiz button", op)
 
-			return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	_ = c.Respond()
+
+	if result != nil {
+		editErr := c.Edit(ui.FormatQuizAnswerFeedback(loc, result), &tele.SendOptions{ParseMode: tele.ModeHTML})
+		if editErr != nil {
+			ctxLogger.Error().Err(editErr).Msgf("%s: failed to edit quiz card", op)
 		}
+	}
 
-		// TODO: alert here
+	switch {
+	case errors.Is(err, domain.ErrLearnBatchFinished):
+		ctxLogger.Debug().Msgf("%s: batch learned", op)
+
+		return c.Send(loc.T(ui.LearnBatchCompletedMsg), ui.BuildLearningReplyKb(loc))
+	case err != nil:
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
-	return c.Send(
-		ui.FormatReviewWordCard(word),
+	return sendLearnBatchCard(c, loc, card)
+}
+
+func sendLearnBatchCard(c tele.Context, loc *i18n.Localizer, card *domain.LearnBatchCard) error {
+	return c.Send(
+		ui.FormatLearnBatchCard(loc, card),
 		&tele.SendOptions{
 			ParseMode:   tele.ModeHTML,
-			ReplyMarkup: ui.BuildReviewRateReplyKb(),
+			ReplyMarkup: ui.BuildLearnBatchQuizInlineKb(card),
 		},
 	)
 }
 
+func (h *BotHandlers) ReviewByDictNum(c tele.Context) error {
+	const op = "ReviewByDictNum"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+	if c.Callback() != nil {
+		defer func() {
+			_ = c.Respond()
+		}()
+	}
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	args := c.Args()
+	if len(args) != 1 {
+		ctxLogger.Debug().Int("args", len(args)).Msgf("%s: incorrect num of args", op)
+
+		return c.Send(loc.T(ui.ReviewUsageMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	trimmed := strings.Trim(strings.TrimSpace(args[0]), "<>")
+	if strings.EqualFold(trimmed, reviewAllArg) {
+		return h.ReviewAll(c)
+	}
+
+	number, convErr := strconv.Atoi(trimmed)
+	if convErr != nil {
+		ctxLogger.Debug().
+			Err(convErr).
+			Str("args[0]", args[0]).
+			Msgf("%s: error converting arg to int", op)
+
+		return c.Send(loc.T(ui.ReviewUsageMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	dictionaryID, err := h.reviewUC.PrepareByDictionaryNumber(ctx, userID, number)
+	if err != nil {
+		mapped := mapper.MapReviewErrorToUI(err)
+		if mapped.State() != mapper.ReviewUIUnknown {
+			ctxLogger.Debug().
+				Err(err).
+				Str("dictionary_id", dictionaryID).
+				Msgf("%s handled with mapped review error", op)
+
+			return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+		}
+
+		// TODO: alert here
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return c.Send(loc.T(ui.ReviewIntroMsg), ui.BuildReviewIntroReplyKb(loc))
+}
+
+func (h *BotHandlers) ReviewByDictID(c tele.Context) error {
+	const op = "ReviewByDictID"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+	if c.Callback() != nil {
+		defer func() {
+			_ = c.Respond()
+		}()
+	}
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	dictionaryID := extractCallbackDictionaryID(c)
+	if dictionaryID == "" {
+		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
+
+		return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	err := h.reviewUC.PrepareByDictionaryID(ctx, userID, dictionaryID)
+	if err != nil {
+		mapped := mapper.MapReviewErrorToUI(err)
+		if mapped.State() != mapper.ReviewUIUnknown {
+			ctxLogger.Debug().
+				Err(err).
+				Str("dictionary_id", dictionaryID).
+				Msgf("%s handled with mapped review error", op)
+
+			return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+		}
+
+		// TODO: alert here
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return c.Send(loc.T(ui.ReviewIntroMsg), ui.BuildReviewIntroReplyKb(loc))
+}
+
+// ReviewAll prepares a round over due words of all subscribed dictionaries.
+func (h *BotHandlers) ReviewAll(c tele.Context) error {
+	const op = "ReviewAll"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	if err := h.reviewUC.PrepareAll(ctx, userID); err != nil {
+		mapped := mapper.MapReviewErrorToUI(err)
+		if mapped.State() != mapper.ReviewUIUnknown {
+			ctxLogger.Debug().Err(err).Msgf("%s handled with mapped review error", op)
+
+			return mapper.SendReviewMappedError(c, mapped, "")
+		}
+
+		// TODO: alert here
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return c.Send(loc.T(ui.ReviewIntroAllMsg), ui.BuildReviewIntroReplyKb(loc))
+}
+
+func (h *BotHandlers) ReviewForce(c tele.Context) error {
+	const op = "ReviewForce"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+	if c.Callback() != nil {
+		defer func() {
+			_ = c.Respond()
+		}()
+	}
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	dictionaryID, err := h.reviewUC.ActiveDictionaryID(ctx, userID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	if dictionaryID == "" {
+		ctxLogger.Debug().Msgf("%s: active dictionary is empty", op)
+
+		return c.Send(loc.T(ui.ActiveDictMissingMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	card, err := h.reviewUC.StartForceRound(ctx, userID, dictionaryID, domain.ReviewModePreferred)
+	if err != nil {
+		mapped := mapper.MapReviewErrorToUI(err)
+		if mapped.State() != mapper.ReviewUIUnknown {
+			ctxLogger.Debug().
+				Err(err).
+				Str("dictionary_id", dictionaryID).
+				Msgf("%s handled with mapped review error", op)
+
+			return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+		}
+
+		// TODO: alert here
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return startReviewRound(c, loc, card)
+}
+
 func (h *BotHandlers) ReviewForceByCallback(c tele.Context) error {
 	const op = "ReviewForceByCallback"
 
@@ -966,6 +1428,7 @@ func (h *BotHandlers) ReviewForceByCallback(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -979,10 +1442,10 @@ func (h *BotHandlers) ReviewForceByCallback(c tele.Context) error {
 	if dictionaryID == "" {
 		ctxLogger.Error().Msgf("%s: dictionary_id is empty", op)
 
-		return c.Send(ui.DictionaryNotFoundMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.DictionaryNotFoundMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
-	word, err := h.reviewUC.StartForceRound(ctx, userID, dictionaryID)
+	card, err := h.reviewUC.StartForceRound(ctx, userID, dictionaryID, domain.ReviewModePreferred)
 	if err != nil {
 		mapped := mapper.MapReviewErrorToUI(err)
 		if mapped.State() != mapper.ReviewUIUnknown {
@@ -997,16 +1460,10 @@ func (h *BotHandlers) ReviewForceByCallback(c tele.Context) error {
 		// TODO: alert here
 		ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-		return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 	}
 
-	return c.Send(
-		ui.FormatReviewWordCard(word),
-		&tele.SendOptions{
-			ParseMode:   tele.ModeHTML,
-			ReplyMarkup: ui.BuildReviewRateReplyKb(),
-		},
-	)
+	return startReviewRound(c, loc, card)
 }
 
 func (h *BotHandlers) ReviewAction(c tele.Context) error {
@@ -1023,6 +1480,7 @@ func (h *BotHandlers) ReviewAction(c tele.Context) error {
 	userID := c.Sender().ID
 	updateID := c.Update().ID
 	username := c.Sender().Username
+	loc := i18n.From(c)
 
 	ctxLogger := h.logger.With().
 		Int("update_id", updateID).
@@ -1032,9 +1490,23 @@ func (h *BotHandlers) ReviewAction(c tele.Context) error {
 
 	ctxLogger.Debug().Msgf("handling %s", op)
 
-	switch c.Text() {
-	case ui.ReviewStartText:
-		word, dictionaryID, err := h.reviewUC.StartDueRound(ctx, userID)
+	key, _ := i18n.KeyByText(c.Text())
+	switch key {
+	case ui.ReviewStartText, ui.ReviewStartTypedText, ui.ReviewStartQuizText, ui.ReviewStartClozeText,
+		ui.ReviewStartListeningText:
+		mode := domain.ReviewModePreferred
+		switch key {
+		case ui.ReviewStartTypedText:
+			mode = domain.ReviewModeTyped
+		case ui.ReviewStartQuizText:
+			mode = domain.ReviewModeQuiz
+		case ui.ReviewStartClozeText:
+			mode = domain.ReviewModeCloze
+		case ui.ReviewStartListeningText:
+			mode = domain.ReviewModeListening
+		}
+
+		card, dictionaryID, err := h.reviewUC.StartDueRound(ctx, userID, mode)
 		if err != nil {
 			mapped := mapper.MapReviewErrorToUI(err)
 			if mapped.State() != mapper.ReviewUIUnknown {
@@ -1049,36 +1521,51 @@ func (h *BotHandlers) ReviewAction(c tele.Context) error {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
-		return c.Send(
-			ui.FormatReviewWordCard(word),
-			&tele.SendOptions{
-				ParseMode:   tele.ModeHTML,
-				ReplyMarkup: ui.BuildReviewRateReplyKb(),
-			},
-		)
+		return startReviewRound(c, loc, card)
 
 	case ui.ToMainMenuText, ui.ReviewStopText:
 		if err := h.reviewUC.Stop(ctx, userID); err != nil {
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+		}
+
+		return c.Send(loc.T(ui.ToMainMenuMsg), ui.BuildMainMenuReplyKb(loc))
+
+	case ui.ReviewUndoText:
+		card, dictionaryID, err := h.reviewUC.Undo(ctx, userID)
+		if err != nil {
+			mapped := mapper.MapReviewErrorToUI(err)
+			if mapped.State() != mapper.ReviewUIUnknown {
+				ctxLogger.Debug().
+					Err(err).
+					Str("dictionary_id", dictionaryID).
+					Msgf("%s handled with mapped review error", op)
+
+				return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+			}
+
+			// TODO: alert here
+			ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
-		return c.Send(ui.ToMainMenuMsg, ui.BuildMainMenuReplyKb())
+		return sendReviewCard(c, loc, card)
 
 	case ui.ReviewRate1Text, ui.ReviewRate2Text, ui.ReviewRate3Text, ui.ReviewRate4Text:
-		grade, ok := mapper.ReviewGradeByText[c.Text()]
+		grade, ok := mapper.ReviewGradeByKey[key]
 		if !ok {
 			// TODO: alert here
 			ctxLogger.Error().Msgf("%s: error mapping user text %s on SM2 grade", op, c.Text())
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
-		word, dictionaryID, err := h.reviewUC.RateCurrent(ctx, userID, grade)
+		card, dictionaryID, err := h.reviewUC.RateCurrent(ctx, userID, grade)
 		if err != nil {
 			mapped := mapper.MapReviewErrorToUI(err)
 			if mapped.State() != mapper.ReviewUIUnknown {
@@ -1093,23 +1580,1242 @@ func (h *BotHandlers) ReviewAction(c tele.Context) error {
 			// TODO: alert here
 			ctxLogger.Error().Err(err).Msgf("%s failed", op)
 
-			return c.Send(ui.InternalErrorMsg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
-		return c.Send(
-			ui.FormatReviewWordCard(word),
-			&tele.SendOptions{
-				ParseMode:   tele.ModeHTML,
-				ReplyMarkup: ui.BuildReviewRateReplyKb(),
-			},
-		)
+		return sendReviewCard(c, loc, card)
 
 	default:
 		return nil
 	}
 }
 
-func extractCallbackDictionaryID(c tele.Context) string {
+// ReviewAnswer handles free text: in a typed review round it is the answer to
+// the current card, otherwise it is ignored.
+func (h *BotHandlers) ReviewAnswer(c tele.Context) error {
+	const op = "ReviewAnswer"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	answer := c.Text()
+	if key, ok := i18n.KeyByText(answer); ok && key == ui.ReviewDontKnowText {
+		answer = ""
+	}
+
+	result, card, dictionaryID, err := h.reviewUC.AnswerCurrent(ctx, userID, answer)
+	if errors.Is(err, domain.ErrAnswerNotExpected) {
+		return nil
+	}
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	if result != nil {
+		sendErr := c.Send(
+			ui.FormatTypedAnswerFeedback(loc, result),
+			&tele.SendOptions{ParseMode: tele.ModeHTML},
+		)
+		if sendErr != nil {
+			ctxLogger.Error().Err(sendErr).Msgf("%s: failed to send answer feedback", op)
+		}
+	}
+
+	if err != nil {
+		mapped := mapper.MapReviewErrorToUI(err)
+		if mapped.State() != mapper.ReviewUIUnknown {
+			ctxLogger.Debug().
+				Err(err).
+				Str("dictionary_id", dictionaryID).
+				Msgf("%s handled with mapped review error", op)
+
+			return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+		}
+
+		// TODO: alert here
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return sendReviewCard(c, loc, card)
+}
+
+func (h *BotHandlers) ReviewQuizAnswer(c tele.Context) error {
+	return h.reviewQuizAnswer(c, false)
+}
+
+// ReviewQuizAnswerInline is ReviewQuizAnswer of a round reviewed in one
+// message: the feedback and the next card replace the answered card.
+func (h *BotHandlers) ReviewQuizAnswerInline(c tele.Context) error {
+	return h.reviewQuizAnswer(c, true)
+}
+
+func (h *BotHandlers) reviewQuizAnswer(c tele.Context, inline bool) error {
+	const op = "ReviewQuizAnswer"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	args := c.Args()
+	if len(args) != 2 {
+		ctxLogger.Error().Msgf("%s: unexpected callback data %q", op, c.Data())
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	option, err := strconv.Atoi(args[1])
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s: unexpected callback data %q", op, c.Data())
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	token := args[0]
+
+	result, card, dictionaryID, err := h.reviewUC.AnswerQuiz(ctx, userID, token, option)
+	if errors.Is(err, domain.ErrStaleReviewCard) {
+		ctxLogger.Debug().Msgf("%s: stale quiz button", op)
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	_ = c.Respond()
+
+	if inline {
+		feedback := ""
+		if result != nil {
+			feedback = ui.FormatQuizAnswerFeedback(loc, result)
+		}
+
+		return h.editReviewCard(c, loc, feedback, card, dictionaryID, token, err, ctxLogger, op)
+	}
+
+	if result != nil {
+		editErr := c.Edit(ui.FormatQuizAnswerFeedback(loc, result), &tele.SendOptions{ParseMode: tele.ModeHTML})
+		if editErr != nil {
+			ctxLogger.Error().Err(editErr).Msgf("%s: failed to edit quiz card", op)
+		}
+	}
+
+	if err != nil {
+		mapped := mapper.MapReviewErrorToUI(err)
+		if mapped.State() != mapper.ReviewUIUnknown {
+			ctxLogger.Debug().
+				Err(err).
+				Str("dictionary_id", dictionaryID).
+				Msgf("%s handled with mapped review error", op)
+
+			return mapper.SendReviewMappedError(c, mapped, dictionaryID)
+		}
+
+		// TODO: alert here
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return sendReviewCard(c, loc, card)
+}
+
+func (h *BotHandlers) ReviewKeyboard(c tele.Context) error {
+	const op = "ReviewKeyboard"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	keyboard, err := h.reviewUC.ReviewKeyboard(ctx, userID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Send(
+		loc.T(ui.ReviewKeyboardChooseMsg),
+		&tele.SendOptions{ReplyMarkup: ui.BuildReviewKeyboardInlineKb(loc, keyboard)},
+	)
+}
+
+func (h *BotHandlers) SetReviewKeyboard(c tele.Context) error {
+	const op = "SetReviewKeyboard"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	keyboard := domain.ReviewKeyboard(extractCallbackData(c))
+	ctxLogger = ctxLogger.With().Str("review_keyboard", string(keyboard)).Logger()
+
+	if err := h.reviewUC.SetReviewKeyboard(ctx, userID, keyboard); err != nil {
+		_ = c.Respond()
+
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	_ = c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewKeyboardChangedMsg)})
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Edit(
+		loc.T(ui.ReviewKeyboardChooseMsg),
+		&tele.SendOptions{ReplyMarkup: ui.BuildReviewKeyboardInlineKb(loc, keyboard)},
+	)
+}
+
+// ReviewRateInline grades the card of a round reviewed in one message and
+// shows the next card in its place.
+func (h *BotHandlers) ReviewRateInline(c tele.Context) error {
+	const op = "ReviewRateInline"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	args := c.Args()
+	if len(args) != 2 {
+		ctxLogger.Error().Msgf("%s: unexpected callback data %q", op, c.Data())
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	grade, err := strconv.Atoi(args[1])
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s: unexpected callback data %q", op, c.Data())
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	token := args[0]
+
+	card, dictionaryID, err := h.reviewUC.RateCard(ctx, userID, token, grade)
+	if errors.Is(err, domain.ErrStaleReviewCard) {
+		ctxLogger.Debug().Msgf("%s: stale rate button", op)
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	_ = c.Respond()
+
+	return h.editReviewCard(c, loc, "", card, dictionaryID, token, err, ctxLogger, op)
+}
+
+// ReviewUndoInline is the undo button of a round reviewed in one message,
+// also under its summary.
+func (h *BotHandlers) ReviewUndoInline(c tele.Context) error {
+	const op = "ReviewUndoInline"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	token := extractCallbackData(c)
+	card, dictionaryID, err := h.reviewUC.UndoCard(ctx, userID, token)
+	if errors.Is(err, domain.ErrStaleReviewCard) {
+		ctxLogger.Debug().Msgf("%s: stale undo button", op)
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	if errors.Is(err, domain.ErrNothingToUndo) {
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewNothingToUndoMsg)})
+	}
+	_ = c.Respond()
+
+	return h.editReviewCard(c, loc, "", card, dictionaryID, token, err, ctxLogger, op)
+}
+
+// ReviewStopInline is the stop button of a round reviewed in one message.
+func (h *BotHandlers) ReviewStopInline(c tele.Context) error {
+	const op = "ReviewStopInline"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	err := h.reviewUC.StopCard(ctx, userID, extractCallbackData(c))
+	if errors.Is(err, domain.ErrStaleReviewCard) {
+		ctxLogger.Debug().Msgf("%s: stale stop button", op)
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	_ = c.Respond()
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return c.Edit(loc.T(ui.ToMainMenuMsg))
+}
+
+// editReviewCard shows the card in place of the message of a round reviewed
+// in one message, below the HTML header (feedback to the previous card).
+// err is the error of getting the card; a finished round turns the message
+// into the summary.
+func (h *BotHandlers) editReviewCard(
+	c tele.Context,
+	loc *i18n.Localizer,
+	header string,
+	card *domain.ReviewCard,
+	dictionaryID, token string,
+	err error,
+	ctxLogger zerolog.Logger,
+	op string,
+) error {
+	if err != nil {
+		mapped := mapper.MapReviewErrorToUI(err)
+		if mapped.State() != mapper.ReviewUIUnknown {
+			ctxLogger.Debug().
+				Err(err).
+				Str("dictionary_id", dictionaryID).
+				Msgf("%s handled with mapped review error", op)
+
+			return mapper.EditReviewMappedError(c, mapped, dictionaryID, token, header)
+		}
+
+		// TODO: alert here
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	if !card.Inline() {
+		return sendReviewCard(c, loc, card)
+	}
+
+	text := ui.FormatReviewCard(loc, card)
+	if card.PreviousChanged {
+		text = loc.T(ui.ReviewCardChangedMsg) + "\n\n" + text
+	}
+	if header != "" {
+		text = header + "\n\n" + text
+	}
+
+	return c.Edit(text, &tele.SendOptions{ParseMode: tele.ModeHTML, ReplyMarkup: reviewCardKb(loc, card)})
+}
+
+// startReviewRound sends the first card of a round. A message carries either
+// an inline or a reply keyboard, so a round with inline cards or quiz options
+// first sets the reply keyboard by a separate message.
+func startReviewRound(c tele.Context, loc *i18n.Localizer, card *domain.ReviewCard) error {
+	switch {
+	case card.Inline():
+		if err := c.Send(loc.T(ui.ReviewInlineIntroMsg), ui.BuildMainMenuReplyKb(loc)); err != nil {
+			return err
+		}
+	case card.Mode == domain.ReviewModeQuiz:
+		if err := c.Send(loc.T(ui.ReviewQuizIntroMsg), ui.BuildReviewQuizReplyKb(loc)); err != nil {
+			return err
+		}
+	}
+
+	return sendReviewCard(c, loc, card)
+}
+
+func sendReviewCard(c tele.Context, loc *i18n.Localizer, card *domain.ReviewCard) error {
+	if card.PreviousChanged {
+		if err := c.Send(loc.T(ui.ReviewCardChangedMsg)); err != nil {
+			return err
+		}
+	}
+
+	if card.Mode == domain.ReviewModeListening {
+		audio := &tele.Audio{
+			File:    audioFile(card.Word.Audio),
+			Caption: ui.FormatListeningReviewCaption(loc),
+		}
+
+		err := c.Send(audio, &tele.SendOptions{
+			ParseMode:   tele.ModeHTML,
+			ReplyMarkup: ui.BuildReviewTypedReplyKb(loc),
+		})
+		if err == nil {
+			return nil
+		}
+
+		// Broken audio reference: the answer is typed anyway, so show the
+		// translation instead.
+		return c.Send(
+			ui.FormatTypedReviewWordCard(loc, card.Word),
+			&tele.SendOptions{
+				ParseMode:   tele.ModeHTML,
+				ReplyMarkup: ui.BuildReviewTypedReplyKb(loc),
+			},
+		)
+	}
+
+	return c.Send(
+		ui.FormatReviewCard(loc, card),
+		&tele.SendOptions{
+			ParseMode:   tele.ModeHTML,
+			ReplyMarkup: reviewCardKb(loc, card),
+		},
+	)
+}
+
+func reviewCardKb(loc *i18n.Localizer, card *domain.ReviewCard) *tele.ReplyMarkup {
+	switch {
+	case card.Mode == domain.ReviewModeTyped, card.Mode == domain.ReviewModeCloze:
+		return ui.BuildReviewTypedReplyKb(loc)
+	case card.Mode == domain.ReviewModeQuiz:
+		return ui.BuildReviewQuizInlineKb(loc, card)
+	case card.Inline():
+		return ui.BuildReviewRateInlineKb(loc, card)
+	default:
+		return ui.BuildReviewRateReplyKb(loc)
+	}
+}
+
+// audioFile resolves the audio reference of a word: a URL or a local path.
+func audioFile(ref string) tele.File {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return tele.FromURL(ref)
+	}
+
+	return tele.FromDisk(ref)
+}
+
+// Word shows the user's progress on every word spelled as the payload, with
+// the actions that apply to it. Without a payload, the hidden and paused
+// words are listed to open their cards, as they can't be met on review.
+func (h *BotHandlers) Word(c tele.Context) error {
+	const op = "Word"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	spelling := strings.Trim(strings.TrimSpace(c.Message().Payload), "<>")
+	if spelling == "" {
+		setAside, err := h.wordsUC.ListSetAside(ctx, userID)
+		if err != nil {
+			ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+		}
+		if len(setAside) == 0 {
+			return c.Send(loc.T(ui.WordUsageMsg), ui.BuildMainMenuReplyKb(loc))
+		}
+
+		ctxLogger.Debug().Int("count", len(setAside)).Msgf("%s: set aside words listed", op)
+
+		return c.Send(loc.T(ui.WordSetAsideMsg), ui.BuildSetAsideWordsInlineKb(loc, setAside))
+	}
+
+	words, err := h.wordsUC.Find(ctx, userID, spelling)
+	if errors.Is(err, domain.ErrWordNotFound) {
+		ctxLogger.Debug().Str("spelling", spelling).Msgf("%s: word not found", op)
+
+		return c.Send(loc.Tf(ui.WordNotFoundMsg, spelling), ui.BuildMainMenuReplyKb(loc))
+	}
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	settings, err := h.settingsUC.Settings(ctx, userID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	tz := settings.Reminder.Location()
+
+	for _, word := range words {
+		err = c.Send(
+			ui.FormatUserWord(loc, word, tz),
+			&tele.SendOptions{
+				ParseMode:   tele.ModeHTML,
+				ReplyMarkup: ui.BuildUserWordInlineKb(loc, word),
+			},
+		)
+		if err != nil {
+			ctxLogger.Error().Err(err).Msgf("%s: failed to send word card", op)
+
+			return err
+		}
+	}
+
+	ctxLogger.Debug().Int("count", len(words)).Msgf("%s handled", op)
+
+	return nil
+}
+
+// WordOpen shows the card of a word picked from the list of set aside words.
+func (h *BotHandlers) WordOpen(c tele.Context) error {
+	const op = "WordOpen"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	args := c.Args()
+	if len(args) != 1 {
+		ctxLogger.Error().Msgf("%s: unexpected callback data %q", op, c.Data())
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+
+	ctxLogger = ctxLogger.With().Str("dict_word_id", args[0]).Logger()
+
+	word, err := h.wordsUC.Get(ctx, userID, args[0])
+	if errors.Is(err, domain.ErrWordNotFound) {
+		ctxLogger.Debug().Msgf("%s: stale word button", op)
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	_ = c.Respond()
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	settings, err := h.settingsUC.Settings(ctx, userID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	tz := settings.Reminder.Location()
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Send(
+		ui.FormatUserWord(loc, word, tz),
+		&tele.SendOptions{
+			ParseMode:   tele.ModeHTML,
+			ReplyMarkup: ui.BuildUserWordInlineKb(loc, word),
+		},
+	)
+}
+
+// WordAction applies an action from a word card and shows the card updated.
+func (h *BotHandlers) WordAction(c tele.Context) error {
+	const op = "WordAction"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	args := c.Args()
+	if len(args) != 3 {
+		ctxLogger.Error().Msgf("%s: unexpected callback data %q", op, c.Data())
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+	action, ok := domain.ParseWordAction(args[1])
+	arg, err := strconv.Atoi(args[2])
+	if !ok || err != nil {
+		ctxLogger.Error().Msgf("%s: unexpected callback data %q", op, c.Data())
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	}
+
+	ctxLogger = ctxLogger.With().Str("dict_word_id", args[0]).Str("action", string(action)).Logger()
+
+	settings, err := h.settingsUC.Settings(ctx, userID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		_ = c.Respond()
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	tz := settings.Reminder.Location()
+
+	word, err := h.wordsUC.Apply(ctx, userID, args[0], action, arg)
+	switch {
+	case errors.Is(err, domain.ErrWordActionNotApplicable):
+		ctxLogger.Debug().Msgf("%s: action not applicable", op)
+
+		_ = c.Respond(&tele.CallbackResponse{Text: loc.T(ui.WordNotApplicableMsg)})
+	case errors.Is(err, domain.ErrWordNotFound), errors.Is(err, domain.ErrInvalidWordAction):
+		ctxLogger.Debug().Err(err).Msgf("%s: stale word card", op)
+
+		return c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReviewCardStaleMsg)})
+	case err != nil:
+		_ = c.Respond()
+
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	default:
+		_ = c.Respond(&tele.CallbackResponse{Text: loc.T(ui.WordAppliedMsg)})
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Edit(
+		ui.FormatUserWord(loc, word, tz),
+		&tele.SendOptions{
+			ParseMode:   tele.ModeHTML,
+			ReplyMarkup: ui.BuildUserWordInlineKb(loc, word),
+		},
+	)
+}
+
+// Remind shows the reminder settings; with an argument it turns the reminders
+// on or off or sets their time.
+func (h *BotHandlers) Remind(c tele.Context) error {
+	const op = "Remind"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	arg := strings.ToLower(strings.Trim(strings.TrimSpace(c.Message().Payload), "<>"))
+
+	var err error
+	switch arg {
+	case "":
+	case "on", "off":
+		err = h.reminderUC.SetEnabled(ctx, userID, arg == "on")
+	default:
+		err = h.reminderUC.SetTime(ctx, userID, arg)
+	}
+	if errors.Is(err, domain.ErrInvalidReminderTime) {
+		ctxLogger.Debug().Str("arg", arg).Msgf("%s: invalid reminder time", op)
+
+		return c.Send(loc.T(ui.ReminderInvalidTimeMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return h.sendReminderSettings(ctx, c, ctxLogger, op)
+}
+
+// SetReminders turns the reminders on or off from the settings message.
+func (h *BotHandlers) SetReminders(c tele.Context) error {
+	const op = "SetReminders"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	enabled := extractCallbackData(c) == "on"
+	if err := h.reminderUC.SetEnabled(ctx, userID, enabled); err != nil {
+		_ = c.Respond()
+
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	settings, err := h.reminderUC.Settings(ctx, userID)
+	if err != nil {
+		_ = c.Respond()
+
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	_ = c.Respond(&tele.CallbackResponse{Text: loc.T(ui.ReminderChangedMsg)})
+
+	ctxLogger.Debug().Bool("enabled", enabled).Msgf("%s handled", op)
+
+	return c.Edit(
+		ui.FormatReminderSettings(loc, settings),
+		&tele.SendOptions{ReplyMarkup: ui.BuildReminderSettingsInlineKb(loc, settings.Enabled)},
+	)
+}
+
+// Timezone sets the time zone reminders are scheduled in.
+func (h *BotHandlers) Timezone(c tele.Context) error {
+	const op = "Timezone"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	arg := strings.Trim(strings.TrimSpace(c.Message().Payload), "<>")
+	if arg == "" {
+		return h.sendReminderSettings(ctx, c, ctxLogger, op)
+	}
+
+	timezone, err := h.reminderUC.SetTimezone(ctx, userID, arg)
+	if errors.Is(err, domain.ErrInvalidTimezone) {
+		ctxLogger.Debug().Str("arg", arg).Msgf("%s: invalid timezone", op)
+
+		return c.Send(loc.T(ui.ReminderInvalidTimezoneMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger.Debug().Str("timezone", timezone).Msgf("%s handled", op)
+
+	return h.sendReminderSettings(ctx, c, ctxLogger, op)
+}
+
+// QuietHours sets the hours no reminder is sent in.
+func (h *BotHandlers) QuietHours(c tele.Context) error {
+	const op = "QuietHours"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	arg := strings.Trim(strings.TrimSpace(c.Message().Payload), "<>")
+	if arg == "" {
+		return h.sendReminderSettings(ctx, c, ctxLogger, op)
+	}
+
+	err := h.reminderUC.SetQuietHours(ctx, userID, arg)
+	if errors.Is(err, domain.ErrInvalidQuietHours) {
+		ctxLogger.Debug().Str("arg", arg).Msgf("%s: invalid quiet hours", op)
+
+		return c.Send(loc.T(ui.ReminderInvalidQuietMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return h.sendReminderSettings(ctx, c, ctxLogger, op)
+}
+
+// ReminderReview starts a round over all dictionaries from a reminder.
+func (h *BotHandlers) ReminderReview(c tele.Context) error {
+	_ = c.Respond()
+
+	return h.ReviewAll(c)
+}
+
+func (h *BotHandlers) sendReminderSettings(
+	ctx context.Context,
+	c tele.Context,
+	ctxLogger zerolog.Logger,
+	op string,
+) error {
+	loc := i18n.From(c)
+
+	settings, err := h.reminderUC.Settings(ctx, c.Sender().ID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	return c.Send(
+		ui.FormatReminderSettings(loc, settings),
+		&tele.SendOptions{ReplyMarkup: ui.BuildReminderSettingsInlineKb(loc, settings.Enabled)},
+	)
+}
+
+// Settings shows the settings of the user with buttons to change them.
+func (h *BotHandlers) Settings(c tele.Context) error {
+	const op = "Settings"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	settings, err := h.settingsUC.Settings(ctx, userID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Send(
+		ui.FormatSettings(loc, settings),
+		&tele.SendOptions{ReplyMarkup: ui.BuildSettingsInlineKb(loc, settings)},
+	)
+}
+
+// SettingsAdjust changes a setting from the settings message and updates the
+// message.
+func (h *BotHandlers) SettingsAdjust(c tele.Context) error {
+	const op = "SettingsAdjust"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	args := c.Args()
+	if len(args) != 2 {
+		_ = c.Respond()
+
+		ctxLogger.Error().Int("args", len(args)).Msgf("%s: incorrect num of args", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	field := domain.SettingField(args[0])
+	delta, err := strconv.Atoi(args[1])
+	if err != nil {
+		_ = c.Respond()
+
+		ctxLogger.Error().Err(err).Msgf("%s: invalid delta", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	settings, err := h.settingsUC.Adjust(ctx, userID, field, delta)
+	if err != nil {
+		_ = c.Respond()
+
+		ctxLogger.Error().Err(err).Str("field", string(field)).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	_ = c.Respond(&tele.CallbackResponse{Text: loc.T(ui.SettingsChangedMsg)})
+
+	ctxLogger.Debug().Str("field", string(field)).Int("delta", delta).Msgf("%s handled", op)
+
+	return c.Edit(
+		ui.FormatSettings(loc, settings),
+		&tele.SendOptions{ReplyMarkup: ui.BuildSettingsInlineKb(loc, settings)},
+	)
+}
+
+// SettingsPick opens the picker of a setting chosen from a list.
+func (h *BotHandlers) SettingsPick(c tele.Context) error {
+	_ = c.Respond()
+
+	loc := i18n.From(c)
+
+	switch extractCallbackData(c) {
+	case ui.SettingsPickLanguage:
+		return c.Send(
+			loc.T(ui.LanguageChooseMsg),
+			&tele.SendOptions{ReplyMarkup: ui.BuildLanguageInlineKb()},
+		)
+	case ui.SettingsPickReviewKeyboard:
+		return h.ReviewKeyboard(c)
+	default:
+		return nil
+	}
+}
+
+// Streak shows the streaks with the activity calendar.
+func (h *BotHandlers) Streak(c tele.Context) error {
+	const op = "Streak"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	stats, err := h.streakUC.Streak(ctx, userID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	heatmap, err := chart.Heatmap(stats.Days, stats.Goal)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Send(&tele.Photo{
+		File:    tele.FromReader(bytes.NewReader(heatmap)),
+		Caption: ui.FormatStreak(loc, stats),
+	})
+}
+
+// Stats shows the progress charts of a dictionary by its /mydict number or of
+// all dictionaries without one.
+func (h *BotHandlers) Stats(c tele.Context) error {
+	const op = "Stats"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	number := 0
+	if arg := strings.Trim(strings.TrimSpace(c.Message().Payload), "<>"); arg != "" {
+		var convErr error
+		number, convErr = strconv.Atoi(arg)
+		if convErr != nil {
+			ctxLogger.Debug().Err(convErr).Str("arg", arg).Msgf("%s: error converting arg to int", op)
+
+			return c.Send(loc.T(ui.StatsUsageMsg), ui.BuildMainMenuReplyKb(loc))
+		}
+	}
+
+	stats, err := h.statsUC.Stats(ctx, userID, number)
+	if errors.Is(err, domain.ErrInvalidDictionaryNumber) {
+		ctxLogger.Debug().Int("number", number).Msgf("%s: invalid dictionary number", op)
+
+		return c.Send(loc.T(ui.InvalidDictionaryNumberMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	charts, err := chart.Stats(stats)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Send(
+		&tele.Photo{
+			File:    tele.FromReader(bytes.NewReader(charts)),
+			Caption: ui.FormatStats(loc, stats),
+		},
+		&tele.SendOptions{ParseMode: tele.ModeHTML},
+	)
+}
+
+// Digest turns the weekly digest on or off and shows the digest of the last
+// week so far.
+func (h *BotHandlers) Digest(c tele.Context) error {
+	const op = "Digest"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	arg := strings.ToLower(strings.Trim(strings.TrimSpace(c.Message().Payload), "<>"))
+	switch arg {
+	case "":
+	case "on", "off":
+		if err := h.digestUC.SetEnabled(ctx, userID, arg == "on"); err != nil {
+			ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+			return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+		}
+	default:
+		return c.Send(loc.T(ui.DigestUsageMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	digest, enabled, err := h.digestUC.Preview(ctx, userID)
+	if err != nil {
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	ctxLogger.Debug().Msgf("%s handled", op)
+
+	return c.Send(
+		ui.FormatDigestPreview(loc, digest, enabled),
+		&tele.SendOptions{ParseMode: tele.ModeHTML, ReplyMarkup: ui.BuildDigestInlineKb(loc, enabled)},
+	)
+}
+
+// SetDigest turns the weekly digest on or off from the digest message.
+func (h *BotHandlers) SetDigest(c tele.Context) error {
+	const op = "SetDigest"
+
+	ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+	defer cancel()
+
+	userID := c.Sender().ID
+	updateID := c.Update().ID
+	username := c.Sender().Username
+	loc := i18n.From(c)
+
+	// TODO: remove personal data from logs after alfa-test
+	ctxLogger := h.logger.With().
+		Int("update_id", updateID).
+		Int64("user_id", userID).
+		Str("username", username).
+		Logger()
+
+	ctxLogger.Debug().Msgf("handling %s", op)
+
+	enabled := extractCallbackData(c) == "on"
+	if err := h.digestUC.SetEnabled(ctx, userID, enabled); err != nil {
+		_ = c.Respond()
+
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	digest, enabled, err := h.digestUC.Preview(ctx, userID)
+	if err != nil {
+		_ = c.Respond()
+
+		ctxLogger.Error().Err(err).Msgf("%s failed", op)
+
+		return c.Send(loc.T(ui.InternalErrorMsg), ui.BuildMainMenuReplyKb(loc))
+	}
+
+	_ = c.Respond(&tele.CallbackResponse{Text: loc.T(ui.DigestChangedMsg)})
+
+	ctxLogger.Debug().Bool("enabled", enabled).Msgf("%s handled", op)
+
+	return c.Edit(
+		ui.FormatDigestPreview(loc, digest, enabled),
+		&tele.SendOptions{ParseMode: tele.ModeHTML, ReplyMarkup: ui.BuildDigestInlineKb(loc, enabled)},
+	)
+}
+
+func extractCallbackDictionaryID(c tele.Context) string {
+	return extractCallbackData(c)
+}
+
+func extractCallbackData(c tele.Context) string {
 	if c.Callback() != nil {
 		return strings.TrimSpace(c.Data())
 	}
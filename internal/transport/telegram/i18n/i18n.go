@@ -0,0 +1,179 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	tele "gopkg.in/telebot.v4"
+)
+
+// DefaultLanguage is used when the user's language is unknown or unsupported.
+const DefaultLanguage = "ru"
+
+const contextKey = "i18n_localizer"
+
+// buttonKeyPrefix marks keys of button labels, see KeyByText.
+const buttonKeyPrefix = "btn."
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// catalog holds messages by language and by key. Loaded once from the
+// embedded locale files.
+var catalog = mustLoadCatalog()
+
+type catalogData struct {
+	messages map[string]map[string]string
+	// keyByText maps every translation of every button key back to the key.
+	// It is used for locale-independent routing of reply keyboard buttons.
+	keyByText map[string]string
+	languages []string
+}
+
+func mustLoadCatalog() *catalogData {
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: read locales dir: %v", err))
+	}
+
+	c := &catalogData{
+		messages:  make(map[string]map[string]string, len(entries)),
+		keyByText: make(map[string]string, 256),
+	}
+
+	for _, e := range entries {
+		lang := strings.TrimSuffix(e.Name(), path.Ext(e.Name()))
+
+		raw, readErr := localesFS.ReadFile(path.Join("locales", e.Name()))
+		if readErr != nil {
+			panic(fmt.Sprintf("i18n: read locale %q: %v", lang, readErr))
+		}
+
+		msgs := make(map[string]string)
+		if readErr = json.Unmarshal(raw, &msgs); readErr != nil {
+			panic(fmt.Sprintf("i18n: parse locale %q: %v", lang, readErr))
+		}
+
+		c.messages[lang] = msgs
+		c.languages = append(c.languages, lang)
+		for key, text := range msgs {
+			if !strings.HasPrefix(key, buttonKeyPrefix) {
+				continue
+			}
+			// A label shared by two buttons couldn't be routed back to its key.
+			if other, dup := c.keyByText[text]; dup && other != key {
+				panic(fmt.Sprintf("i18n: %q is the label of both %q and %q", text, other, key))
+			}
+			c.keyByText[text] = key
+		}
+	}
+
+	if _, ok := c.messages[DefaultLanguage]; !ok {
+		panic(fmt.Sprintf("i18n: default locale %q is missing", DefaultLanguage))
+	}
+	sort.Strings(c.languages)
+
+	return c
+}
+
+// Localizer translates message keys into a single language.
+type Localizer struct {
+	lang string
+}
+
+// For returns a localizer for the given language code. Codes like "en-US"
+// are reduced to the base language; unsupported codes fall back to
+// DefaultLanguage.
+func For(code string) *Localizer {
+	lang, ok := Normalize(code)
+	if !ok {
+		lang = DefaultLanguage
+	}
+
+	return &Localizer{lang: lang}
+}
+
+func (l *Localizer) Lang() string {
+	return l.lang
+}
+
+// T returns the translation of key. Missing translations fall back to the
+// default language and then to the key itself.
+func (l *Localizer) T(key string) string {
+	if msg, ok := catalog.messages[l.lang][key]; ok {
+		return msg
+	}
+	if msg, ok := catalog.messages[DefaultLanguage][key]; ok {
+		return msg
+	}
+
+	return key
+}
+
+// Tf is T followed by fmt.Sprintf.
+func (l *Localizer) Tf(key string, args ...any) string {
+	return fmt.Sprintf(l.T(key), args...)
+}
+
+// Normalize reduces a Telegram language_code to a supported language.
+func Normalize(code string) (string, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+
+	if _, ok := catalog.messages[code]; !ok {
+		return "", false
+	}
+
+	return code, true
+}
+
+// Supported returns all languages available in the catalog.
+func Supported() []string {
+	return catalog.languages
+}
+
+// Variants returns every distinct translation of key across all languages.
+func Variants(key string) []string {
+	seen := make(map[string]struct{}, len(catalog.languages))
+	variants := make([]string, 0, len(catalog.languages))
+	for _, lang := range catalog.languages {
+		text, ok := catalog.messages[lang][key]
+		if !ok {
+			continue
+		}
+		if _, dup := seen[text]; dup {
+			continue
+		}
+
+		seen[text] = struct{}{}
+		variants = append(variants, text)
+	}
+
+	return variants
+}
+
+// KeyByText resolves a translated button label back to its key.
+func KeyByText(text string) (string, bool) {
+	key, ok := catalog.keyByText[text]
+	return key, ok
+}
+
+// Set stores the localizer in the update context.
+func Set(c tele.Context, l *Localizer) {
+	c.Set(contextKey, l)
+}
+
+// From returns the localizer stored in the update context or the default one.
+func From(c tele.Context) *Localizer {
+	if l, ok := c.Get(contextKey).(*Localizer); ok && l != nil {
+		return l
+	}
+
+	return For(DefaultLanguage)
+}
@@ -6,6 +6,7 @@ import (
 	tele "gopkg.in/telebot.v4"
 
 	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/i18n"
 	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/ui"
 )
 
@@ -42,17 +43,21 @@ func MapLearningErrorToUI(err error) LearningUIResult {
 		return LearningUIResult{state: LearningUIMainMenu, msg: ui.LearnNotStartedMsg}
 	case errors.Is(err, domain.ErrNoWordsForLearning):
 		return LearningUIResult{state: LearningUICompleted, msg: ui.LearnCompletedMsg}
+	case errors.Is(err, domain.ErrDailyNewWordsLimit):
+		return LearningUIResult{state: LearningUICompleted, msg: ui.LearnDailyLimitMsg}
 	default:
 		return LearningUIResult{state: LearningUIUnknown}
 	}
 }
 
 func SendLearningMappedError(c tele.Context, mapped LearningUIResult, dictionaryID string) error {
+	loc := i18n.From(c)
+
 	switch mapped.state {
 	case LearningUIMainMenu:
-		return c.Send(mapped.msg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(mapped.msg), ui.BuildMainMenuReplyKb(loc))
 	case LearningUICompleted:
-		return c.Send(mapped.msg, ui.BuildLearningCompletedReplyKb())
+		return c.Send(loc.T(mapped.msg), ui.BuildLearningCompletedReplyKb(loc))
 	default:
 		return nil
 	}
@@ -2,10 +2,12 @@ package mapper
 
 import (
 	"errors"
+	"html"
 
 	tele "gopkg.in/telebot.v4"
 
 	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/i18n"
 	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/ui"
 )
 
@@ -16,9 +18,11 @@ const (
 	ReviewUIMainMenu
 	ReviewUINoDue
 	ReviewUIDone
+	// ReviewUINotice keeps the current keyboard.
+	ReviewUINotice
 )
 
-var ReviewGradeByText = map[string]int{
+var ReviewGradeByKey = map[string]int{
 	ui.ReviewRate1Text: 0,
 	ui.ReviewRate2Text: 1,
 	ui.ReviewRate3Text: 2,
@@ -28,6 +32,8 @@ var ReviewGradeByText = map[string]int{
 type ReviewUIResult struct {
 	state ReviewUIState
 	msg   string
+	// summary is set for a finished round.
+	summary *domain.ReviewSummary
 }
 
 func (rr ReviewUIResult) State() ReviewUIState {
@@ -51,29 +57,90 @@ func MapReviewErrorToUI(err error) *ReviewUIResult {
 		return &ReviewUIResult{state: ReviewUIMainMenu, msg: ui.ReviewEmptyWordsListMsg}
 	case errors.Is(err, domain.ErrNoWordsDueForReview):
 		return &ReviewUIResult{state: ReviewUINoDue, msg: ui.ReviewNoDueMsg}
+	case errors.Is(err, domain.ErrDailyReviewLimit):
+		return &ReviewUIResult{state: ReviewUIMainMenu, msg: ui.ReviewDailyLimitMsg}
 	case errors.Is(err, domain.ErrReviewRoundFinished):
-		return &ReviewUIResult{state: ReviewUIDone, msg: ui.ReviewCompletedMsg}
+		res := &ReviewUIResult{state: ReviewUIDone, msg: ui.ReviewCompletedMsg}
+		var finished *domain.ReviewRoundFinishedError
+		if errors.As(err, &finished) {
+			res.summary = finished.Summary
+		}
+
+		return res
+	case errors.Is(err, domain.ErrNothingToUndo):
+		return &ReviewUIResult{state: ReviewUINotice, msg: ui.ReviewNothingToUndoMsg}
+	case errors.Is(err, domain.ErrStaleReviewCard):
+		return &ReviewUIResult{state: ReviewUINotice, msg: ui.ReviewCardStaleMsg}
 	default:
 		return &ReviewUIResult{state: ReviewUIUnknown}
 	}
 }
 
 func SendReviewMappedError(c tele.Context, mapped *ReviewUIResult, dictionaryID string) error {
+	loc := i18n.From(c)
+
 	switch mapped.state {
 	case ReviewUIMainMenu:
-		return c.Send(mapped.msg, ui.BuildMainMenuReplyKb())
+		return c.Send(loc.T(mapped.msg), ui.BuildMainMenuReplyKb(loc))
 	case ReviewUINoDue:
 		if dictionaryID == "" {
-			return c.Send(mapped.msg, ui.BuildMainMenuReplyKb())
+			return c.Send(loc.T(ui.ReviewCompletedAllMsg), ui.BuildMainMenuReplyKb(loc))
 		}
 
 		return c.Send(
-			mapped.msg,
-			&tele.SendOptions{ReplyMarkup: ui.BuildReviewForceInlineKb(dictionaryID)},
+			loc.T(mapped.msg),
+			&tele.SendOptions{ReplyMarkup: ui.BuildReviewForceInlineKb(loc, dictionaryID)},
 		)
 	case ReviewUIDone:
-		return c.Send(mapped.msg, ui.BuildReviewFinishReplyKb())
+		kb := ui.BuildReviewFinishReplyKb(loc)
+		// A cross-dictionary round has no dictionary to force-review.
+		if dictionaryID == "" {
+			kb = ui.BuildMainMenuReplyKb(loc)
+		}
+
+		return c.Send(
+			reviewDoneText(loc, mapped, dictionaryID),
+			&tele.SendOptions{ParseMode: tele.ModeHTML, ReplyMarkup: kb},
+		)
+	case ReviewUINotice:
+		return c.Send(loc.T(mapped.msg))
 	default:
 		return nil
 	}
 }
+
+// EditReviewMappedError is SendReviewMappedError for a round reviewed in one
+// message: the finished round turns the message into the summary below the
+// HTML header. token is of the last card of the round.
+func EditReviewMappedError(c tele.Context, mapped *ReviewUIResult, dictionaryID, token, header string) error {
+	loc := i18n.From(c)
+
+	switch mapped.state {
+	case ReviewUIDone:
+		msg := reviewDoneText(loc, mapped, dictionaryID)
+		if header != "" {
+			msg = header + "\n\n" + msg
+		}
+
+		return c.Edit(msg, &tele.SendOptions{
+			ParseMode:   tele.ModeHTML,
+			ReplyMarkup: ui.BuildReviewFinishInlineKb(loc, token, dictionaryID),
+		})
+	default:
+		return SendReviewMappedError(c, mapped, dictionaryID)
+	}
+}
+
+// reviewDoneText is the HTML message of a finished round.
+func reviewDoneText(loc *i18n.Localizer, mapped *ReviewUIResult, dictionaryID string) string {
+	msg := loc.T(mapped.msg)
+	if dictionaryID == "" {
+		msg = loc.T(ui.ReviewCompletedAllMsg)
+	}
+	msg = html.EscapeString(msg)
+	if mapped.summary != nil {
+		msg += "\n\n" + ui.FormatReviewSummary(loc, mapped.summary)
+	}
+
+	return msg
+}
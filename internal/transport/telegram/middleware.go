@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/i18n"
+)
+
+// userLockTimeout bounds waiting for the updates of the user handled before.
+const userLockTimeout = 30 * time.Second
+
+// report passes the error of the update to Process, when the update is
+// handled by it.
+func (t *Server) report(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		err := next(c)
+		if result, ok := c.Get(updateResultKey).(*updateResult); ok {
+			result.err = err
+		}
+
+		return err
+	}
+}
+
+// skipProcessed drops an update delivered again, e.g. retried by Telegram
+// after a timeout, so that a grade or a decision isn't applied twice. The
+// update is recorded before it's handled: a handler may fail after its effect
+// is stored, e.g. on sending the reply, and its update must not be applied
+// again then. So an update is handled at most once; one that can't be
+// recorded isn't handled, and the error makes the webhook ask Telegram for it
+// again, see Process. It runs under serializeUser, so the copies of an update
+// don't race past each other.
+func (t *Server) skipProcessed(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		updateID := c.Update().ID
+
+		ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+		defer cancel()
+
+		first, err := t.updates.MarkUpdateProcessed(ctx, updateID)
+		if err != nil {
+			t.logger.Error().
+				Err(err).
+				Int("update_id", updateID).
+				Msg("skipProcessed: failed to record update, update not handled")
+			return fmt.Errorf("skipProcessed: %w", err)
+		}
+		if !first {
+			t.logger.Debug().
+				Int("update_id", updateID).
+				Msg("skipProcessed: update already processed, dropped")
+			return nil
+		}
+
+		return next(c)
+	}
+}
+
+// serializeUser handles the updates of a user one at a time, across all bot
+// instances, so that a double tap can't run two handlers on the same session
+// or word at once. The order of concurrent updates is not kept.
+func (t *Server) serializeUser(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		sender := c.Sender()
+		if sender == nil {
+			return next(c)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), userLockTimeout)
+		defer cancel()
+
+		unlock, err := t.locker.LockUser(ctx, sender.ID)
+		if err != nil {
+			// The update isn't handled unserialized; the error makes the
+			// webhook ask Telegram for it again, see Process.
+			t.logger.Error().
+				Err(err).
+				Int("update_id", c.Update().ID).
+				Int64("user_id", sender.ID).
+				Msg("serializeUser: failed to lock user, update not handled")
+			return fmt.Errorf("serializeUser: %w", err)
+		}
+		defer unlock()
+
+		return next(c)
+	}
+}
+
+// Locale resolves the UI language of the sender and stores the localizer in
+// the update context. The language chosen with /language wins over the
+// language of the Telegram client.
+func (h *BotHandlers) Locale(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		sender := c.Sender()
+		if sender == nil {
+			return next(c)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), handlerCtxTimeout)
+		defer cancel()
+
+		code, err := h.onboardUC.Language(ctx, sender.ID)
+		if err != nil {
+			h.logger.Warn().
+				Err(err).
+				Int64("user_id", sender.ID).
+				Msg("Locale: failed to get user language, using client language")
+		}
+		if code == "" {
+			code = sender.LanguageCode
+		}
+
+		i18n.Set(c, i18n.For(code))
+
+		return next(c)
+	}
+}
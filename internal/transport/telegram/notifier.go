@@ -0,0 +1,234 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	tele "gopkg.in/telebot.v4"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/i18n"
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/ui"
+)
+
+const (
+	// sendInterval spaces out the messages the bot initiates, across all the
+	// jobs sending them, to stay well under the Telegram limit of about 30
+	// messages per second.
+	sendInterval = 50 * time.Millisecond
+	// maxFloodRetries is how many times a message is sent again after
+	// Telegram asks to slow down.
+	maxFloodRetries = 3
+)
+
+// Notifier sends the messages the bot initiates itself, outside of updates.
+// One Notifier is shared by the reminders, the digests and the
+// notifications, so they keep to one pace together.
+type Notifier struct {
+	bot    *tele.Bot
+	pacer  *pacer
+	logger *zerolog.Logger
+}
+
+func NewNotifier(bot *tele.Bot, parentLogger *zerolog.Logger) *Notifier {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "telegram_notifier").Logger()
+
+	return &Notifier{
+		bot:    bot,
+		pacer:  &pacer{interval: sendInterval},
+		logger: &logger,
+	}
+}
+
+func (n *Notifier) SendReminder(ctx context.Context, candidate domain.ReminderCandidate) error {
+	const op = "SendReminder"
+
+	loc := i18n.For(candidate.LanguageCode)
+
+	err := n.send(
+		ctx,
+		candidate.UserID,
+		loc.Tf(ui.ReminderMsg, candidate.DueCount),
+		&tele.SendOptions{ReplyMarkup: ui.BuildReminderInlineKb(loc)},
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SendDigest sends the weekly digest with a button to review if words are
+// due.
+func (n *Notifier) SendDigest(ctx context.Context, candidate domain.DigestCandidate, digest *domain.WeeklyDigest) error {
+	const op = "SendDigest"
+
+	loc := i18n.For(candidate.LanguageCode)
+
+	opts := &tele.SendOptions{ParseMode: tele.ModeHTML}
+	if digest.DueAhead > 0 {
+		opts.ReplyMarkup = ui.BuildReminderInlineKb(loc)
+	}
+
+	if err := n.send(ctx, candidate.UserID, ui.FormatDigest(loc, digest), opts); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SendNotification tells about new words in a dictionary, with a button to
+// learn them.
+func (n *Notifier) SendNotification(ctx context.Context, notification domain.Notification) error {
+	const op = "SendNotification"
+
+	loc := i18n.For(notification.LanguageCode)
+
+	err := n.send(
+		ctx,
+		notification.UserID,
+		ui.FormatNotification(loc, &notification),
+		&tele.SendOptions{
+			ParseMode:   tele.ModeHTML,
+			ReplyMarkup: ui.BuildNotificationInlineKb(loc, notification.DictionaryID),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// send sends a message to the user at the pace of sendInterval. When
+// Telegram answers 429, all the messages wait as long as it asks and this one
+// is sent again, up to maxFloodRetries times.
+func (n *Notifier) send(ctx context.Context, userID int64, what any, opts *tele.SendOptions) error {
+	for attempt := 0; ; attempt++ {
+		if err := n.pacer.wait(ctx); err != nil {
+			return fmt.Errorf("%w: %w", domain.ErrMessageNotSent, err)
+		}
+
+		_, err := n.bot.Send(&tele.User{ID: userID}, what, opts)
+		var flood tele.FloodError
+		if !errors.As(err, &flood) || attempt == maxFloodRetries {
+			return mapSendError(err)
+		}
+
+		retryAfter := time.Duration(flood.RetryAfter) * time.Second
+		n.logger.Warn().
+			Int64("user_id", userID).
+			Dur("retry_after", retryAfter).
+			Msg("send: too many requests, slowing down")
+		n.pacer.pause(retryAfter)
+	}
+}
+
+// pacer spaces its calls out by the interval, whoever makes them.
+type pacer struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+	// resume is when the calls may go on after a pause.
+	resume time.Time
+}
+
+// wait takes the next free slot and waits for it. A pause made meanwhile
+// holds the call off until it's over.
+func (p *pacer) wait(ctx context.Context) error {
+	for {
+		p.mu.Lock()
+		slot := time.Now()
+		if p.next.After(slot) {
+			slot = p.next
+		}
+		if p.resume.After(slot) {
+			slot = p.resume
+		}
+		p.next = slot.Add(p.interval)
+		p.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(slot))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		p.mu.Lock()
+		paused := time.Now().Before(p.resume)
+		p.mu.Unlock()
+		if !paused {
+			return nil
+		}
+	}
+}
+
+// pause holds all the calls off for d from now.
+func (p *pacer) pause(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if resume := time.Now().Add(d); resume.After(p.resume) {
+		p.resume = resume
+	}
+}
+
+// unknownAPIError matches the errors telebot makes of the API errors it has
+// no type for: "telegram: <description> (<code>)".
+var unknownAPIError = regexp.MustCompile(`^telegram: .* \((\d{3})\)$`)
+
+// mapSendError marks the errors meaning the user can't be messaged anymore:
+// the bot is blocked, the account is deleted or the chat is gone; and the
+// ones meaning Telegram surely didn't send the message: too many requests or
+// a failure of its own. Other failures, e.g. of the network, leave it unknown
+// whether the message was sent.
+func mapSendError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := apiErrorCode(err)
+	switch {
+	case errors.Is(err, tele.ErrChatNotFound) || code == http.StatusForbidden:
+		return fmt.Errorf("%w: %w", domain.ErrRecipientUnavailable, err)
+	case code == http.StatusTooManyRequests || code >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: %w", domain.ErrMessageNotSent, err)
+	}
+
+	return err
+}
+
+// apiErrorCode returns the HTTP code of an error answered by the Bot API, or
+// 0 if the error isn't one.
+func apiErrorCode(err error) int {
+	var flood tele.FloodError
+	if errors.As(err, &flood) {
+		return http.StatusTooManyRequests
+	}
+
+	var tgErr *tele.Error
+	if errors.As(err, &tgErr) {
+		return tgErr.Code
+	}
+
+	if m := unknownAPIError.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+
+	return 0
+}
@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+func TestPacer(t *testing.T) {
+	const (
+		interval = 10 * time.Millisecond
+		callers  = 6
+	)
+	p := &pacer{interval: interval}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := p.wait(context.Background()); err != nil {
+				t.Errorf("wait: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The first call goes at once, the rest an interval apart.
+	if elapsed, want := time.Since(start), (callers-1)*interval; elapsed < want {
+		t.Errorf("%d calls took %v, want at least %v", callers, elapsed, want)
+	}
+}
+
+func TestPacerPause(t *testing.T) {
+	const pause = 50 * time.Millisecond
+	p := &pacer{interval: time.Millisecond}
+
+	start := time.Now()
+	p.pause(pause)
+	if err := p.wait(context.Background()); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < pause {
+		t.Errorf("call went after %v, want the pause of %v", elapsed, pause)
+	}
+
+	p.pause(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait in a pause: err = %v, want deadline exceeded", err)
+	}
+}
+
+func TestMapSendError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"blocked", tele.ErrBlockedByUser, domain.ErrRecipientUnavailable},
+		{"chat not found", tele.ErrChatNotFound, domain.ErrRecipientUnavailable},
+		{"too many requests", tele.NewError(http.StatusTooManyRequests, "Too Many Requests"), domain.ErrMessageNotSent},
+		{"server failure", errors.New("telegram: Internal Server Error (500)"), domain.ErrMessageNotSent},
+		{"bad request", errors.New("telegram: Bad Request: message is too long (400)"), nil},
+		{"network", fmt.Errorf("telebot: %w", &url.Error{Op: "Post", Err: io.ErrUnexpectedEOF}), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapSendError(tt.err)
+			if !errors.Is(got, tt.err) {
+				t.Errorf("mapSendError(%v) = %v, want it wrapped", tt.err, got)
+			}
+			for _, mark := range []error{domain.ErrRecipientUnavailable, domain.ErrMessageNotSent} {
+				if errors.Is(got, mark) != (mark == tt.want) {
+					t.Errorf("mapSendError(%v) = %v, want marked with %v", tt.err, got, tt.want)
+				}
+			}
+		})
+	}
+
+	if err := mapSendError(nil); err != nil {
+		t.Errorf("mapSendError(nil) = %v", err)
+	}
+}
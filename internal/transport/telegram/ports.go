@@ -9,6 +9,8 @@ import (
 type OnboardingUsecase interface {
 	Start(ctx context.Context, userID int64, username string) error
 	RemoveMe(ctx context.Context, userID int64) error
+	Language(ctx context.Context, userID int64) (string, error)
+	SetLanguage(ctx context.Context, userID int64, username, code string) error
 }
 
 type CatalogUsecase interface {
@@ -21,6 +23,8 @@ type SubscriptionUsecase interface {
 	Subscribe(ctx context.Context, userID int64, username, dictionaryID string) error
 	Unsubscribe(ctx context.Context, userID int64, dictionaryID string) error
 	EnsureSubscribed(ctx context.Context, userID int64, dictionaryID string) error
+	CardDirections(ctx context.Context, userID int64, dictionaryID string) (domain.CardDirections, error)
+	SetCardDirections(ctx context.Context, userID int64, dictionaryID string, directions domain.CardDirections) error
 }
 
 type LearningUsecase interface {
@@ -28,6 +32,9 @@ type LearningUsecase interface {
 	LearnByDictionaryID(ctx context.Context, userID int64, dictionaryID string) (*domain.LearningWord, error)
 	AddCurrentWord(ctx context.Context, userID int64) (*domain.LearningWord, error)
 	BlockCurrentWord(ctx context.Context, userID int64) (*domain.LearningWord, error)
+	StartBatch(ctx context.Context, userID int64) (*domain.LearnBatch, error)
+	StartBatchQuiz(ctx context.Context, userID int64, batchID string) (*domain.LearnBatchCard, error)
+	AnswerBatchQuiz(ctx context.Context, userID int64, token string, option int) (*domain.QuizAnswerResult, *domain.LearnBatchCard, error)
 	ActiveDictionaryID(ctx context.Context, userID int64) (string, error)
 	Back(ctx context.Context, userID int64) error
 }
@@ -36,10 +43,52 @@ type ReviewUsecase interface {
 	PrepareByDictionaryNumber(ctx context.Context, userID int64, number int) (string, error)
 	PrepareByDictionaryID(ctx context.Context, userID int64, dictionaryID string) error
 	ActiveDictionaryID(ctx context.Context, userID int64) (string, error)
-	StartDueRound(ctx context.Context, userID int64) (*domain.ReviewWord, string, error)
-	StartForceRound(ctx context.Context, userID int64, dictionaryID string) (*domain.ReviewWord, error)
-	RateCurrent(ctx context.Context, userID int64, grade int) (*domain.ReviewWord, string, error)
+	PrepareAll(ctx context.Context, userID int64) error
+	StartDueRound(ctx context.Context, userID int64, mode domain.ReviewMode) (*domain.ReviewCard, string, error)
+	StartForceRound(ctx context.Context, userID int64, dictionaryID string, mode domain.ReviewMode) (*domain.ReviewCard, error)
+	RateCurrent(ctx context.Context, userID int64, grade int) (*domain.ReviewCard, string, error)
+	RateCard(ctx context.Context, userID int64, token string, grade int) (*domain.ReviewCard, string, error)
+	AnswerCurrent(ctx context.Context, userID int64, answer string) (*domain.TypedAnswerResult, *domain.ReviewCard, string, error)
+	Undo(ctx context.Context, userID int64) (*domain.ReviewCard, string, error)
+	UndoCard(ctx context.Context, userID int64, token string) (*domain.ReviewCard, string, error)
+	AnswerQuiz(ctx context.Context, userID int64, token string, option int) (*domain.QuizAnswerResult, *domain.ReviewCard, string, error)
 	Stop(ctx context.Context, userID int64) error
+	StopCard(ctx context.Context, userID int64, token string) error
+	ReviewKeyboard(ctx context.Context, userID int64) (domain.ReviewKeyboard, error)
+	SetReviewKeyboard(ctx context.Context, userID int64, keyboard domain.ReviewKeyboard) error
+}
+
+type WordsUsecase interface {
+	Find(ctx context.Context, userID int64, spelling string) ([]*domain.UserWord, error)
+	ListSetAside(ctx context.Context, userID int64) ([]*domain.UserWord, error)
+	Get(ctx context.Context, userID int64, dictWordID string) (*domain.UserWord, error)
+	Apply(ctx context.Context, userID int64, dictWordID string, action domain.WordAction, arg int) (*domain.UserWord, error)
+}
+
+type ReminderUsecase interface {
+	Settings(ctx context.Context, userID int64) (*domain.ReminderSettings, error)
+	SetEnabled(ctx context.Context, userID int64, enabled bool) error
+	SetTime(ctx context.Context, userID int64, raw string) error
+	SetTimezone(ctx context.Context, userID int64, raw string) (string, error)
+	SetQuietHours(ctx context.Context, userID int64, raw string) error
+}
+
+type SettingsUsecase interface {
+	Settings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+	Adjust(ctx context.Context, userID int64, field domain.SettingField, delta int) (*domain.UserSettings, error)
+}
+
+type StatsUsecase interface {
+	Stats(ctx context.Context, userID int64, number int) (*domain.UserStats, error)
+}
+
+type DigestUsecase interface {
+	Preview(ctx context.Context, userID int64) (*domain.WeeklyDigest, bool, error)
+	SetEnabled(ctx context.Context, userID int64, enabled bool) error
+}
+
+type StreakUsecase interface {
+	Streak(ctx context.Context, userID int64) (*domain.StreakStats, error)
 }
 
 // TODO: move ActiveDictionaryID from 2 usecases above to this one.
@@ -48,3 +97,13 @@ type ReviewUsecase interface {
 //	SetActiveDictionaryID(ctx context.Context, userID int64, dictionaryID string) error
 //	ClearActiveDictionaryID(ctx context.Context, userID int64) error
 //}
+
+// UserLocker serializes the updates of a user across the bot instances.
+type UserLocker interface {
+	LockUser(ctx context.Context, userID int64) (unlock func(), err error)
+}
+
+// ProcessedUpdates remembers the updates taken for handling.
+type ProcessedUpdates interface {
+	MarkUpdateProcessed(ctx context.Context, updateID int) (bool, error)
+}
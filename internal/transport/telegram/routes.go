@@ -5,14 +5,20 @@ import (
 
 	tele "gopkg.in/telebot.v4"
 
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/i18n"
 	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/ui"
 )
 
 type Handlers interface {
+	// Middlewares
+	Locale(next tele.HandlerFunc) tele.HandlerFunc
+
 	// Onboarding
 	Start(c tele.Context) error
 	Help(c tele.Context) error
 	RemoveMe(c tele.Context) error
+	Language(c tele.Context) error
+	SetLanguage(c tele.Context) error
 
 	// Catalog
 	Dict(c tele.Context) error
@@ -24,33 +30,89 @@ type Handlers interface {
 	Unsubscribe(c tele.Context) error
 	ConfirmUnsubscribe(c tele.Context) error
 	RejectUnsubscribe(c tele.Context) error
+	CardDirections(c tele.Context) error
+	SetCardDirections(c tele.Context) error
 
 	// Learning
 	LearnByDictNum(c tele.Context) error
 	LearnByDictID(c tele.Context) error
 	LearningAction(c tele.Context) error
+	LearnBatch(c tele.Context) error
+	LearnBatchQuiz(c tele.Context) error
+	LearnBatchAnswer(c tele.Context) error
 
 	// Review
 	ReviewByDictNum(c tele.Context) error
 	ReviewByDictID(c tele.Context) error
+	ReviewAll(c tele.Context) error
 	ReviewAction(c tele.Context) error
 	ReviewForce(c tele.Context) error
 	ReviewForceByCallback(c tele.Context) error
+	ReviewAnswer(c tele.Context) error
+	ReviewQuizAnswer(c tele.Context) error
+	ReviewQuizAnswerInline(c tele.Context) error
+	ReviewRateInline(c tele.Context) error
+	ReviewUndoInline(c tele.Context) error
+	ReviewStopInline(c tele.Context) error
+	ReviewKeyboard(c tele.Context) error
+	SetReviewKeyboard(c tele.Context) error
+
+	// Words
+	Word(c tele.Context) error
+	WordOpen(c tele.Context) error
+	WordAction(c tele.Context) error
+
+	// Reminders
+	Remind(c tele.Context) error
+	SetReminders(c tele.Context) error
+	Timezone(c tele.Context) error
+	QuietHours(c tele.Context) error
+	ReminderReview(c tele.Context) error
+
+	// Settings
+	Settings(c tele.Context) error
+	SettingsAdjust(c tele.Context) error
+	SettingsPick(c tele.Context) error
+
+	// Streaks
+	Streak(c tele.Context) error
+
+	// Stats
+	Stats(c tele.Context) error
+
+	// Digest
+	Digest(c tele.Context) error
+	SetDigest(c tele.Context) error
+}
+
+// guards are the middlewares that keep the updates of a user from being
+// handled at once and an update from being handled twice; they wrap the rest,
+// and report passes the result of the whole chain to Process. An update is
+// checked for being processed under the lock of its user.
+func (t *Server) guards() []tele.MiddlewareFunc {
+	return []tele.MiddlewareFunc{t.report, t.serializeUser, t.skipProcessed}
 }
 
 func (t *Server) InitRoutes(_ context.Context, h Handlers) {
+	// Middlewares must be registered before handlers; the first one wraps
+	// the rest
+	t.bot.Use(t.guards()...)
+	t.bot.Use(h.Locale)
+
 	// Onboarding
 	t.bot.Handle("/start", h.Start)
 	t.bot.Handle("/help", h.Help)
-	t.bot.Handle(ui.MainMenuHelpText, h.Help)
+	t.handleText(ui.MainMenuHelpText, h.Help)
 	t.bot.Handle("/removeMe", h.RemoveMe)
+	t.bot.Handle("/language", h.Language)
+	t.bot.Handle(&tele.InlineButton{Unique: "set_language"}, h.SetLanguage)
 
 	// Catalog
 	t.bot.Handle("/dict", h.Dict)
-	t.bot.Handle(ui.MainMenuDictText, h.Dict)
+	t.handleText(ui.MainMenuDictText, h.Dict)
 	t.bot.Handle(&tele.InlineButton{Unique: "to_dicts"}, h.Dict)
 	t.bot.Handle("/mydict", h.MyDict)
-	t.bot.Handle(ui.MainMenuMyDictText, h.MyDict)
+	t.handleText(ui.MainMenuMyDictText, h.MyDict)
 	t.bot.Handle(&tele.InlineButton{Unique: "dict_details"}, h.DictDetails)
 
 	// Subscription
@@ -58,25 +120,80 @@ func (t *Server) InitRoutes(_ context.Context, h Handlers) {
 	t.bot.Handle(&tele.InlineButton{Unique: "dict_unsubscribe"}, h.Unsubscribe)
 	t.bot.Handle(&tele.InlineButton{Unique: "dict_confirm_unsubscribe"}, h.ConfirmUnsubscribe)
 	t.bot.Handle(&tele.InlineButton{Unique: "dict_reject_unsubscribe"}, h.RejectUnsubscribe)
+	t.bot.Handle(&tele.InlineButton{Unique: "dict_dirs"}, h.CardDirections)
+	t.bot.Handle(&tele.InlineButton{Unique: "dict_dirs_set"}, h.SetCardDirections)
 
 	// Learning
 	t.bot.Handle("/learn", h.LearnByDictNum)
 	t.bot.Handle(&tele.InlineButton{Unique: "dict_learn"}, h.LearnByDictID)
-	t.bot.Handle(ui.LearnAddText, h.LearningAction)
-	t.bot.Handle(ui.LearnBlockText, h.LearningAction)
-	t.bot.Handle(ui.LearnReviewText, h.LearningAction)
-	t.bot.Handle(ui.ToMainMenuText, h.LearningAction)
+	t.handleText(ui.LearnAddText, h.LearningAction)
+	t.handleText(ui.LearnBlockText, h.LearningAction)
+	t.handleText(ui.LearnReviewText, h.LearningAction)
+	t.handleText(ui.LearnBatchText, h.LearnBatch)
+	t.bot.Handle(&tele.InlineButton{Unique: "learn_batch_quiz"}, h.LearnBatchQuiz)
+	t.bot.Handle(&tele.InlineButton{Unique: "learn_quiz"}, h.LearnBatchAnswer)
+	t.handleText(ui.ToMainMenuText, h.LearningAction)
 
 	// Review
 	t.bot.Handle("/review", h.ReviewByDictNum)
 	t.bot.Handle(&tele.InlineButton{Unique: "dict_review"}, h.ReviewByDictID)
-	t.bot.Handle(ui.ReviewRestartText, h.ReviewForce)
+	t.handleText(ui.MainMenuReviewAllText, h.ReviewAll)
+	t.handleText(ui.ReviewRestartText, h.ReviewForce)
 	t.bot.Handle(&tele.InlineButton{Unique: "review_force"}, h.ReviewForceByCallback)
-	t.bot.Handle(ui.ReviewStartText, h.ReviewAction)
-	t.bot.Handle(ui.ReviewStopText, h.ReviewAction)
-	t.bot.Handle(ui.ToMainMenuText, h.ReviewAction)
-	t.bot.Handle(ui.ReviewRate1Text, h.ReviewAction)
-	t.bot.Handle(ui.ReviewRate2Text, h.ReviewAction)
-	t.bot.Handle(ui.ReviewRate3Text, h.ReviewAction)
-	t.bot.Handle(ui.ReviewRate4Text, h.ReviewAction)
+	t.handleText(ui.ReviewStartText, h.ReviewAction)
+	t.handleText(ui.ReviewStartTypedText, h.ReviewAction)
+	t.handleText(ui.ReviewStartQuizText, h.ReviewAction)
+	t.handleText(ui.ReviewStartClozeText, h.ReviewAction)
+	t.handleText(ui.ReviewStartListeningText, h.ReviewAction)
+	t.handleText(ui.ReviewStopText, h.ReviewAction)
+	t.handleText(ui.ToMainMenuText, h.ReviewAction)
+	t.handleText(ui.ReviewRate1Text, h.ReviewAction)
+	t.handleText(ui.ReviewRate2Text, h.ReviewAction)
+	t.handleText(ui.ReviewRate3Text, h.ReviewAction)
+	t.handleText(ui.ReviewRate4Text, h.ReviewAction)
+	t.handleText(ui.ReviewUndoText, h.ReviewAction)
+	t.handleText(ui.ReviewDontKnowText, h.ReviewAnswer)
+	t.bot.Handle(tele.OnText, h.ReviewAnswer)
+	t.bot.Handle(&tele.InlineButton{Unique: "quiz_answer"}, h.ReviewQuizAnswer)
+	t.bot.Handle(&tele.InlineButton{Unique: "rv_quiz"}, h.ReviewQuizAnswerInline)
+	t.bot.Handle(&tele.InlineButton{Unique: "rv_rate"}, h.ReviewRateInline)
+	t.bot.Handle(&tele.InlineButton{Unique: "rv_undo"}, h.ReviewUndoInline)
+	t.bot.Handle(&tele.InlineButton{Unique: "rv_stop"}, h.ReviewStopInline)
+	t.bot.Handle("/reviewkb", h.ReviewKeyboard)
+	t.bot.Handle(&tele.InlineButton{Unique: "set_review_kb"}, h.SetReviewKeyboard)
+
+	// Words
+	t.bot.Handle("/word", h.Word)
+	t.bot.Handle(&tele.InlineButton{Unique: "word_open"}, h.WordOpen)
+	t.bot.Handle(&tele.InlineButton{Unique: "word_act"}, h.WordAction)
+
+	// Reminders
+	t.bot.Handle("/remind", h.Remind)
+	t.bot.Handle(&tele.InlineButton{Unique: "remind_set"}, h.SetReminders)
+	t.bot.Handle("/timezone", h.Timezone)
+	t.bot.Handle("/quiet", h.QuietHours)
+	t.bot.Handle(&tele.InlineButton{Unique: "remind_review"}, h.ReminderReview)
+
+	// Settings
+	t.bot.Handle("/settings", h.Settings)
+	t.bot.Handle(&tele.InlineButton{Unique: "settings"}, h.SettingsAdjust)
+	t.bot.Handle(&tele.InlineButton{Unique: "settings_pick"}, h.SettingsPick)
+
+	// Streaks
+	t.bot.Handle("/streak", h.Streak)
+
+	// Stats
+	t.bot.Handle("/stats", h.Stats)
+
+	// Digest
+	t.bot.Handle("/digest", h.Digest)
+	t.bot.Handle(&tele.InlineButton{Unique: "digest_set"}, h.SetDigest)
+}
+
+// handleText registers h for every translation of the button key, so routing
+// does not depend on the user's language.
+func (t *Server) handleText(key string, h tele.HandlerFunc) {
+	for _, text := range i18n.Variants(key) {
+		t.bot.Handle(text, h)
+	}
 }
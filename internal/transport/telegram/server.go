@@ -6,19 +6,54 @@ import (
 )
 
 type Server struct {
-	bot    *tele.Bot
-	logger *zerolog.Logger
+	bot     *tele.Bot
+	locker  UserLocker
+	updates ProcessedUpdates
+	logger  *zerolog.Logger
 }
 
-func NewServer(bot *tele.Bot, parentLogger *zerolog.Logger) *Server {
+func NewServer(
+	bot *tele.Bot,
+	locker UserLocker,
+	updates ProcessedUpdates,
+	parentLogger *zerolog.Logger,
+) *Server {
 	if parentLogger == nil {
 		panic("logger cannot be nil")
 	}
+	if locker == nil {
+		panic("UserLocker cannot be nil")
+	}
+	if updates == nil {
+		panic("ProcessedUpdates cannot be nil")
+	}
 	logger := parentLogger.With().Str("component", "telegram_server").Logger()
 
-	return &Server{bot, &logger}
+	return &Server{bot, locker, updates, &logger}
 }
 
 func (t *Server) Start() {
 	t.bot.Start()
 }
+
+// updateResultKey is where Process expects the result of the update in the
+// update context, see report.
+const updateResultKey = "update_result"
+
+type updateResult struct {
+	err error
+}
+
+// Process handles the update in the calling goroutine and returns its error,
+// so that the webhook doesn't acknowledge an update that failed. The bot must
+// be synchronous (tele.Settings.Synchronous), or the handler runs later. An
+// update without a handler is ignored.
+func (t *Server) Process(update tele.Update) error {
+	c := t.bot.NewContext(update)
+	result := &updateResult{}
+	c.Set(updateResultKey, result)
+
+	t.bot.ProcessContext(c)
+
+	return result.err
+}
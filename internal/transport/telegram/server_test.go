@@ -0,0 +1,360 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	tele "gopkg.in/telebot.v4"
+)
+
+const (
+	// instanceEnv makes the test binary run as a bot instance; its value is
+	// the URL of the fake Telegram server.
+	instanceEnv = "TEST_BOT_INSTANCE_URL"
+
+	testInstances      = 3
+	testUsers          = 3
+	testUpdatesPerUser = 8
+	testHandlingTime   = 20 * time.Millisecond
+	// testFailEvery makes the first handling of every such update fail after
+	// its effect, so that the copy delivered again must not apply it again.
+	testFailEvery       = 5
+	testInstanceTimeout = 30 * time.Second
+)
+
+// TestServerInstances runs several bot processes against a fake Telegram
+// server that delivers every update to two of them, as a retry after a
+// timeout would. The fake server also stands in for the database: it holds
+// the user locks and the processed updates the instances share. Every update
+// must take effect once, also when its handling fails after the effect or the
+// lock of its user fails, and the updates of a user one at a time.
+func TestServerInstances(t *testing.T) {
+	if tgURL := os.Getenv(instanceEnv); tgURL != "" {
+		runTestInstance(t, tgURL)
+		return
+	}
+
+	tg := newFakeTelegram(t)
+	srv := httptest.NewServer(tg)
+	t.Cleanup(srv.Close)
+
+	for i := 0; i < testInstances; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestServerInstances$")
+		cmd.Env = append(os.Environ(), instanceEnv+"="+srv.URL)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("start instance: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		})
+	}
+
+	select {
+	case <-tg.done:
+	case <-time.After(testInstanceTimeout):
+		t.Fatal("updates not handled in time")
+	}
+
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	for _, problem := range tg.problems {
+		t.Error(problem)
+	}
+	for id := 1; id <= testUsers*testUpdatesPerUser; id++ {
+		if tg.begun[id] != 1 || tg.effects[id] != 1 {
+			t.Errorf("update %d: handled %d times, took effect %d times, want 1", id, tg.begun[id], tg.effects[id])
+		}
+	}
+	if len(tg.lockFailed) != testUsers {
+		t.Errorf("lock failed for %d users, want %d", len(tg.lockFailed), testUsers)
+	}
+}
+
+// runTestInstance runs a bot with the guards of the server and a handler
+// that reports the start of handling, its effect and the end to the fake
+// server.
+func runTestInstance(t *testing.T, tgURL string) {
+	bot, err := tele.NewBot(tele.Settings{
+		URL:     tgURL,
+		Token:   "test",
+		Offline: true,
+		Poller:  &tele.LongPoller{},
+		OnError: func(err error, _ tele.Context) { t.Log(err) },
+	})
+	if err != nil {
+		t.Fatalf("new bot: %v", err)
+	}
+
+	logger := zerolog.Nop()
+	coord := &testCoordinator{url: tgURL}
+	server := NewServer(bot, coord, coord, &logger)
+
+	bot.Use(server.guards()...)
+	bot.Handle(tele.OnText, func(c tele.Context) error {
+		id := strconv.Itoa(c.Update().ID)
+		if err := c.Send("begin " + id); err != nil {
+			return err
+		}
+		time.Sleep(testHandlingTime)
+		if _, err := coord.call(context.Background(), "effect", id); err != nil {
+			return err
+		}
+		return c.Send("end " + id)
+	})
+
+	// The instance runs until the test kills it.
+	server.Start()
+}
+
+// testCoordinator is the UserLocker and ProcessedUpdates of an instance,
+// backed by the fake server.
+type testCoordinator struct {
+	url string
+}
+
+func (c *testCoordinator) LockUser(ctx context.Context, userID int64) (func(), error) {
+	user := strconv.FormatInt(userID, 10)
+	if _, err := c.call(ctx, "lock", user); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_, _ = c.call(context.Background(), "unlock", user)
+	}, nil
+}
+
+func (c *testCoordinator) MarkUpdateProcessed(ctx context.Context, updateID int) (bool, error) {
+	body, err := c.call(ctx, "mark", strconv.Itoa(updateID))
+	if err != nil {
+		return false, err
+	}
+
+	return body == "first", nil
+}
+
+func (c *testCoordinator) call(ctx context.Context, method, arg string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.url+"/coord/"+method+"?arg="+url.QueryEscape(arg), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: status %d", method, resp.StatusCode)
+	}
+
+	return string(body), nil
+}
+
+// fakeTelegram serves getUpdates and sendMessage of the Bot API to the
+// instances and the coordination calls of testCoordinator.
+type fakeTelegram struct {
+	mu     sync.Mutex
+	queue  []tele.Update
+	locks  map[string]chan struct{}
+	marked map[int]bool
+	// busy is the update being handled for every user.
+	busy       map[int64]int
+	begun      map[int]int
+	effects    map[int]int
+	failed     map[int]bool
+	lockFailed map[string]bool
+	problems   []string
+	finish     sync.Once
+	done       chan struct{}
+}
+
+func newFakeTelegram(t *testing.T) *fakeTelegram {
+	tg := &fakeTelegram{
+		locks:      make(map[string]chan struct{}),
+		marked:     make(map[int]bool),
+		busy:       make(map[int64]int),
+		begun:      make(map[int]int),
+		effects:    make(map[int]int),
+		failed:     make(map[int]bool),
+		lockFailed: make(map[string]bool),
+		done:       make(chan struct{}),
+	}
+
+	id := 0
+	for u := 1; u <= testUsers; u++ {
+		for range testUpdatesPerUser {
+			id++
+			user := &tele.User{ID: int64(u)}
+			update := tele.Update{ID: id, Message: &tele.Message{
+				ID:     id,
+				Sender: user,
+				Chat:   &tele.Chat{ID: int64(u), Type: tele.ChatPrivate},
+				Text:   "hello",
+			}}
+			tg.queue = append(tg.queue, update, update)
+		}
+	}
+	rand.Shuffle(len(tg.queue), func(i, j int) { tg.queue[i], tg.queue[j] = tg.queue[j], tg.queue[i] })
+	t.Logf("%d updates delivered twice to %d instances", id, testInstances)
+
+	return tg
+}
+
+func (tg *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	if strings.HasPrefix(r.URL.Path, "/coord/") {
+		tg.coordinate(w, r, method, r.URL.Query().Get("arg"))
+		return
+	}
+
+	switch method {
+	case "getUpdates":
+		tg.getUpdates(w)
+	case "sendMessage":
+		tg.sendMessage(w, r)
+	default:
+		http.Error(w, "unexpected method "+method, http.StatusNotFound)
+	}
+}
+
+// getUpdates hands out the next few updates of the queue to whichever
+// instance asks, regardless of the offset, like a load balancer would.
+func (tg *fakeTelegram) getUpdates(w http.ResponseWriter) {
+	tg.mu.Lock()
+	n := min(len(tg.queue), 1+rand.IntN(3))
+	updates := append([]tele.Update(nil), tg.queue[:n]...)
+	tg.queue = tg.queue[n:]
+	tg.mu.Unlock()
+
+	if len(updates) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	writeResult(w, updates)
+}
+
+func (tg *fakeTelegram) sendMessage(w http.ResponseWriter, r *http.Request) {
+	var params map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chatID, _ := strconv.ParseInt(fmt.Sprint(params["chat_id"]), 10, 64)
+	event, rawID, _ := strings.Cut(fmt.Sprint(params["text"]), " ")
+	id, _ := strconv.Atoi(rawID)
+
+	tg.mu.Lock()
+	switch event {
+	case "begin":
+		if other := tg.busy[chatID]; other != 0 {
+			tg.problems = append(tg.problems,
+				fmt.Sprintf("user %d: update %d handled while update %d is", chatID, id, other))
+		}
+		tg.busy[chatID] = id
+		tg.begun[id]++
+	case "end":
+		tg.busy[chatID] = 0
+		if len(tg.effects) == testUsers*testUpdatesPerUser && len(tg.queue) == 0 {
+			// The duplicates still in flight get a moment to be dropped.
+			tg.finish.Do(func() {
+				time.AfterFunc(200*time.Millisecond, func() { close(tg.done) })
+			})
+		}
+		if id%testFailEvery == 0 && !tg.failed[id] {
+			tg.failed[id] = true
+			tg.mu.Unlock()
+
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"ok": false, "error_code": http.StatusBadRequest, "description": "Bad Request: test failure",
+			})
+			return
+		}
+	}
+	tg.mu.Unlock()
+
+	writeResult(w, tele.Message{ID: id, Chat: &tele.Chat{ID: chatID, Type: tele.ChatPrivate}})
+}
+
+// coordinate serves the locks and the processed updates shared by the
+// instances, as the database does in production, and counts the effects of
+// the updates. The first lock of every user fails.
+func (tg *fakeTelegram) coordinate(w http.ResponseWriter, r *http.Request, method, arg string) {
+	switch method {
+	case "lock":
+		tg.mu.Lock()
+		if !tg.lockFailed[arg] {
+			tg.lockFailed[arg] = true
+			tg.mu.Unlock()
+
+			http.Error(w, "test failure", http.StatusInternalServerError)
+			return
+		}
+		lock, ok := tg.locks[arg]
+		if !ok {
+			lock = make(chan struct{}, 1)
+			tg.locks[arg] = lock
+		}
+		tg.mu.Unlock()
+
+		select {
+		case lock <- struct{}{}:
+		case <-r.Context().Done():
+			return
+		}
+	case "unlock":
+		tg.mu.Lock()
+		lock := tg.locks[arg]
+		tg.mu.Unlock()
+
+		<-lock
+	case "mark":
+		id, _ := strconv.Atoi(arg)
+
+		tg.mu.Lock()
+		first := !tg.marked[id]
+		tg.marked[id] = true
+		tg.mu.Unlock()
+
+		if first {
+			_, _ = w.Write([]byte("first"))
+		}
+	case "effect":
+		id, _ := strconv.Atoi(arg)
+
+		tg.mu.Lock()
+		tg.effects[id]++
+		tg.mu.Unlock()
+	default:
+		http.Error(w, "unexpected call "+method, http.StatusNotFound)
+	}
+}
+
+func writeResult(w http.ResponseWriter, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
@@ -3,83 +3,185 @@ package ui
 import (
 	"fmt"
 	"html"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/i18n"
 )
 
-func FormatDictionaryCard(dict domain.Dictionary) string {
+// Formatter keys of the i18n catalog.
+const (
+	untitledText            = "format.untitled"
+	descriptionLineText     = "format.description"
+	authorLineText          = "format.author"
+	modeLineText            = "format.mode"
+	modeWithHintLineText    = "format.mode_with_hint"
+	dictEmptyText           = "format.dict_empty"
+	dictSampleHeaderText    = "format.dict_sample_header"
+	examplesPlaceholderText = "format.examples_placeholder"
+	dictModeTextPrefix      = "dict_mode."
+	dictModeHintTextPrefix  = "dict_mode_hint."
+	typedCorrectText        = "review.typed_correct"
+	typedCorrectSlowText    = "review.typed_correct_slow"
+	typedVariantText        = "review.typed_variant"
+	typedTypoText           = "review.typed_typo"
+	typedWrongText          = "review.typed_wrong"
+	typedNoAnswerText       = "review.typed_no_answer"
+	quizPromptForwardText   = "review.quiz_prompt_forward"
+	quizPromptReverseText   = "review.quiz_prompt_reverse"
+	quizCorrectText         = "review.quiz_correct"
+	quizCorrectSlowText     = "review.quiz_correct_slow"
+	quizWrongText           = "review.quiz_wrong"
+	clozePromptText         = "review.cloze_prompt"
+	listeningPromptText     = "review.listening_prompt"
+	clozeWrongFormText      = "review.cloze_wrong_form"
+	examplesHeaderText      = "format.examples_header"
+	summaryHeaderText       = "review.summary_header"
+	summaryReviewedText     = "review.summary_reviewed"
+	summaryGradesText       = "review.summary_grades"
+	summaryAccuracyText     = "review.summary_accuracy"
+	summaryDurationText     = "review.summary_duration"
+	summaryHardestText      = "review.summary_hardest"
+	summaryDueTomorrowText  = "review.summary_due_tomorrow"
+	durationMinSecText      = "format.duration_min_sec"
+	durationSecText         = "format.duration_sec"
+	learnBatchHeaderText    = "learning.batch_header"
+	learnBatchLeftText      = "learning.batch_left"
+	wordDictionaryText      = "word.dictionary"
+	wordStatusText          = "word.status"
+	wordStatusTextPrefix    = "word.status_"
+	wordStatusNewText       = "word.status_new"
+	wordCardDueText         = "word.card_due"
+	wordCardUnscheduledText = "word.card_unscheduled"
+	wordCardParamsText      = "word.card_params"
+	wordCardGradesText      = "word.card_grades"
+	wordCardIntervalsText   = "word.card_intervals"
+	reminderSettingsText    = "reminder.settings"
+	reminderOnText          = "reminder.on"
+	reminderOffText         = "reminder.off"
+	reminderQuietOffText    = "reminder.quiet_off"
+	settingsText            = "settings.text"
+	reviewModeTextPrefix    = "settings.mode_"
+	schedulerTextPrefix     = "settings.scheduler_"
+	goalKindTextPrefix      = "settings.goal_"
+	streakText              = "streak.text"
+	statsHeaderAllText      = "stats.header_all"
+	statsHeaderDictText     = "stats.header_dictionary"
+	statsText               = "stats.text"
+	digestHeaderText        = "digest.header"
+	digestText              = "digest.text"
+	digestHardestText       = "digest.hardest"
+	digestHardestWordText   = "digest.hardest_word"
+	digestEmptyText         = "digest.empty"
+	digestOnText            = "digest.on"
+	digestOffText           = "digest.off"
+	notificationTextPrefix  = "notification."
+)
+
+const (
+	// wordDateLayout is the layout of review dates on word cards.
+	wordDateLayout = "02.01.2006"
+	// wordGradeDateLayout is the layout of the dates of the grades.
+	wordGradeDateLayout = "02.01"
+	// wordGradesShown is how many latest grades are listed on a word card.
+	wordGradesShown = 5
+	// digestDateLayout is the layout of the days a digest covers.
+	digestDateLayout = "02.01"
+)
+
+// sparkBlocks are the bars of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// gradeButtonKeys are the button labels of the grades, indexed by grade.
+var gradeButtonKeys = [domain.MaxGrade + 1]string{
+	ReviewRate1Text,
+	ReviewRate2Text,
+	ReviewRate3Text,
+	ReviewRate4Text,
+}
+
+// gradeText is the button label of the grade, or the grade itself if there is
+// no button for it.
+func gradeText(loc *i18n.Localizer, grade int) string {
+	if grade < 0 || grade >= len(gradeButtonKeys) {
+		return strconv.Itoa(grade)
+	}
+
+	return loc.T(gradeButtonKeys[grade])
+}
+
+func FormatDictionaryCard(loc *i18n.Localizer, dict domain.Dictionary) string {
 	var b strings.Builder
 	title := strings.TrimSpace(dict.Title)
 	if title == "" {
-		title = "Без названия"
+		title = loc.T(untitledText)
 	}
 	b.WriteString(fmt.Sprintf("📘 <u>%s</u>\n", html.EscapeString(title)))
 
 	if strings.TrimSpace(dict.Description) != "" {
-		b.WriteString(fmt.Sprintf("Описание: %s\n", html.EscapeString(dict.Description)))
+		b.WriteString(loc.Tf(descriptionLineText, html.EscapeString(dict.Description)))
 	}
 
 	if strings.TrimSpace(dict.Author) != "" {
-		b.WriteString(fmt.Sprintf("Автор: %s\n", html.EscapeString(dict.Author)))
+		b.WriteString(loc.Tf(authorLineText, html.EscapeString(dict.Author)))
 	}
 
-	b.WriteString(fmt.Sprintf("Тип: %s", html.EscapeString(dict.Mode.HumanReadable())))
+	b.WriteString(loc.Tf(modeLineText, html.EscapeString(formatDictionaryMode(loc, dict.Mode))))
 
 	return b.String()
 }
 
-func FormatSubscribedDictionaryCard(number int, dict domain.Dictionary) string {
+func FormatSubscribedDictionaryCard(loc *i18n.Localizer, number int, dict domain.Dictionary) string {
 	var b strings.Builder
 	title := strings.TrimSpace(dict.Title)
 	if title == "" {
-		title = "Без названия"
+		title = loc.T(untitledText)
 	}
 	b.WriteString(fmt.Sprintf("%d. 📘 <u>%s</u>\n", number, html.EscapeString(title)))
 
 	if strings.TrimSpace(dict.Author) != "" {
-		b.WriteString(fmt.Sprintf("Автор: %s\n", html.EscapeString(dict.Author)))
+		b.WriteString(loc.Tf(authorLineText, html.EscapeString(dict.Author)))
 	}
 
-	b.WriteString(fmt.Sprintf("Тип: %s", html.EscapeString(dict.Mode.HumanReadable())))
+	b.WriteString(loc.Tf(modeLineText, html.EscapeString(formatDictionaryMode(loc, dict.Mode))))
 
 	return b.String()
 }
 
-func FormatDictionaryDetails(dict domain.Dictionary, words []domain.DictionaryWordPreview) string {
+func FormatDictionaryDetails(loc *i18n.Localizer, dict domain.Dictionary, words []domain.DictionaryWordPreview) string {
 	var b strings.Builder
 	title := strings.TrimSpace(dict.Title)
 	if title == "" {
-		title = "Без названия"
+		title = loc.T(untitledText)
 	}
 	b.WriteString(fmt.Sprintf("📘 <u>%s</u>\n\n", html.EscapeString(title)))
 
 	if strings.TrimSpace(dict.Description) != "" {
-		b.WriteString(fmt.Sprintf("Описание: %s\n", html.EscapeString(dict.Description)))
+		b.WriteString(loc.Tf(descriptionLineText, html.EscapeString(dict.Description)))
 	}
 
 	if strings.TrimSpace(dict.Author) != "" {
-		b.WriteString(fmt.Sprintf("Автор: %s\n", html.EscapeString(dict.Author)))
+		b.WriteString(loc.Tf(authorLineText, html.EscapeString(dict.Author)))
 	}
 
 	dictModeHint := ""
 	switch dict.Mode {
-	case domain.RandomPoolMode:
-		dictModeHint = "ты сам выбираешь, когда приступать к изучению новых слов"
-	case domain.OnScheduleMode:
-		dictModeHint = "новые слова приходят тебе по расписанию, заданному автором"
+	case domain.RandomPoolMode, domain.OnScheduleMode:
+		dictModeHint = loc.T(dictModeHintTextPrefix + dict.Mode.String())
 	}
 
-	b.WriteString(fmt.Sprintf("Тип: %s — %s\n\n",
-		html.EscapeString(dict.Mode.HumanReadable()), html.EscapeString(dictModeHint)))
+	b.WriteString(loc.Tf(modeWithHintLineText,
+		html.EscapeString(formatDictionaryMode(loc, dict.Mode)), html.EscapeString(dictModeHint)))
 
 	if len(words) == 0 {
-		b.WriteString("В этом словаре пока нет слов 💤")
+		b.WriteString(loc.T(dictEmptyText))
 
 		return b.String()
 	}
 
-	b.WriteString("Несколько слов отсюда:\n")
+	b.WriteString(loc.T(dictSampleHeaderText))
 	for _, w := range words {
 		b.WriteString(
 			fmt.Sprintf("• %s — <tg-spoiler>%s</tg-spoiler>\n",
@@ -90,24 +192,469 @@ func FormatDictionaryDetails(dict domain.Dictionary, words []domain.DictionaryWo
 	return strings.TrimSpace(b.String())
 }
 
-func FormatLearningWordCard(word domain.LearningWord) string {
+func FormatLearningWordCard(loc *i18n.Localizer, word domain.LearningWord) string {
 	var b strings.Builder
 	b.WriteString(fmt.Sprintf("🇬🇧 <b>%s</b> — %s\n\n",
 		html.EscapeString(word.Spelling), html.EscapeString(word.Transcription)))
 
 	b.WriteString(fmt.Sprintf("🇷🇺 <tg-spoiler>%s</tg-spoiler>\n\n", html.EscapeString(word.RUTranslation)))
 
-	b.WriteString("Примеры использования в речи:\n" +
-		"*тут может быть куча предложений, чтобы лучше понять контекст употребления, но пока их нет*")
+	if len(word.Examples) == 0 {
+		b.WriteString(loc.T(examplesPlaceholderText))
 
-	return b.String()
+		return b.String()
+	}
+
+	b.WriteString(loc.T(examplesHeaderText))
+	for _, e := range word.Examples {
+		b.WriteString(fmt.Sprintf("• <i>%s</i>\n", html.EscapeString(e)))
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// FormatLearnBatch lists the words of a batch with one example each.
+func FormatLearnBatch(loc *i18n.Localizer, batch *domain.LearnBatch) string {
+	var b strings.Builder
+	b.WriteString(loc.Tf(learnBatchHeaderText, len(batch.Words)))
+
+	for _, w := range batch.Words {
+		b.WriteString(fmt.Sprintf("🇬🇧 <b>%s</b> — %s\n",
+			html.EscapeString(w.Spelling), html.EscapeString(w.Transcription)))
+		b.WriteString(fmt.Sprintf("🇷🇺 %s\n", html.EscapeString(w.RUTranslation)))
+		if len(w.Examples) > 0 {
+			b.WriteString(fmt.Sprintf("• <i>%s</i>\n", html.EscapeString(w.Examples[0])))
+		}
+		b.WriteString("\n")
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+func FormatLearnBatchCard(loc *i18n.Localizer, card *domain.LearnBatchCard) string {
+	return FormatQuizReviewWordCard(loc, card.Word) + "\n\n" + loc.Tf(learnBatchLeftText, card.Left)
 }
 
 func FormatReviewWordCard(word *domain.ReviewWord) string {
 	var b strings.Builder
+	if word.Direction == domain.CardDirectionReverse {
+		b.WriteString(fmt.Sprintf("🇷🇺 <b>%s</b>\n\n", html.EscapeString(word.RUTranslation)))
+		b.WriteString(fmt.Sprintf("🇬🇧 <tg-spoiler>%s — %s</tg-spoiler>",
+			html.EscapeString(word.Spelling), html.EscapeString(word.Transcription)))
+
+		return b.String()
+	}
+
 	b.WriteString(fmt.Sprintf("🇬🇧 <b>%s</b> — %s\n\n",
 		html.EscapeString(word.Spelling), html.EscapeString(word.Transcription)))
 	b.WriteString(fmt.Sprintf("🇷🇺 <tg-spoiler>%s</tg-spoiler>", html.EscapeString(word.RUTranslation)))
 
 	return b.String()
 }
+
+func FormatReviewCard(loc *i18n.Localizer, card *domain.ReviewCard) string {
+	switch card.Mode {
+	case domain.ReviewModeTyped:
+		return FormatTypedReviewWordCard(loc, card.Word)
+	case domain.ReviewModeQuiz:
+		return FormatQuizReviewWordCard(loc, card.Word)
+	case domain.ReviewModeCloze:
+		return FormatClozeReviewWordCard(loc, card)
+	default:
+		return FormatReviewWordCard(card.Word)
+	}
+}
+
+func FormatQuizReviewWordCard(loc *i18n.Localizer, word *domain.ReviewWord) string {
+	var b strings.Builder
+	if word.Direction == domain.CardDirectionReverse {
+		b.WriteString(fmt.Sprintf("🇷🇺 <b>%s</b>\n\n", html.EscapeString(word.RUTranslation)))
+		b.WriteString(loc.T(quizPromptReverseText))
+
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("🇬🇧 <b>%s</b> — %s\n\n",
+		html.EscapeString(word.Spelling), html.EscapeString(word.Transcription)))
+	b.WriteString(loc.T(quizPromptForwardText))
+
+	return b.String()
+}
+
+// FormatClozeReviewWordCard shows the example with a blank and the translation
+// under a spoiler as a hint.
+func FormatClozeReviewWordCard(loc *i18n.Localizer, card *domain.ReviewCard) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🧩 <i>%s</i>\n\n", html.EscapeString(card.Cloze.Text)))
+	b.WriteString(fmt.Sprintf("🇷🇺 <tg-spoiler>%s</tg-spoiler>\n\n", html.EscapeString(card.Word.RUTranslation)))
+	b.WriteString(loc.T(clozePromptText))
+
+	return b.String()
+}
+
+func FormatListeningReviewCaption(loc *i18n.Localizer) string {
+	return loc.T(listeningPromptText)
+}
+
+// FormatQuizAnswerFeedback replaces the quiz card once an option is chosen.
+func FormatQuizAnswerFeedback(loc *i18n.Localizer, res *domain.QuizAnswerResult) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🇬🇧 <b>%s</b> — %s\n",
+		html.EscapeString(res.Word.Spelling), html.EscapeString(res.Word.Transcription)))
+	b.WriteString(fmt.Sprintf("🇷🇺 %s\n\n", html.EscapeString(res.Word.RUTranslation)))
+
+	switch {
+	case res.Correct && res.Slow:
+		b.WriteString(loc.T(quizCorrectSlowText))
+	case res.Correct:
+		b.WriteString(loc.T(quizCorrectText))
+	default:
+		b.WriteString(loc.Tf(quizWrongText, html.EscapeString(res.Quiz.Options[res.Chosen])))
+	}
+
+	return b.String()
+}
+
+// FormatTypedReviewWordCard shows the translation only: the spelling is what
+// the user has to type.
+func FormatTypedReviewWordCard(loc *i18n.Localizer, word *domain.ReviewWord) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🇷🇺 <b>%s</b>\n\n", html.EscapeString(word.RUTranslation)))
+	b.WriteString(loc.T(ReviewTypedPromptMsg))
+
+	return b.String()
+}
+
+func FormatTypedAnswerFeedback(loc *i18n.Localizer, res *domain.TypedAnswerResult) string {
+	var b strings.Builder
+	switch {
+	case strings.TrimSpace(res.Answer) == "":
+		b.WriteString(loc.T(typedNoAnswerText))
+	case res.WrongForm:
+		b.WriteString(loc.Tf(clozeWrongFormText, html.EscapeString(res.Expected)))
+	case res.Correct && res.Variant != "":
+		b.WriteString(loc.Tf(typedVariantText, html.EscapeString(res.Variant)))
+	case res.Correct && res.Slow:
+		b.WriteString(loc.T(typedCorrectSlowText))
+	case res.Correct:
+		b.WriteString(loc.T(typedCorrectText))
+	case res.Grade > domain.MinGrade:
+		b.WriteString(loc.Tf(typedTypoText, formatAnswerDiff(res.Diff)))
+	default:
+		b.WriteString(loc.Tf(typedWrongText, formatAnswerDiff(res.Diff)))
+	}
+
+	b.WriteString(fmt.Sprintf("🇬🇧 <b>%s</b> — %s\n",
+		html.EscapeString(res.Word.Spelling), html.EscapeString(res.Word.Transcription)))
+	b.WriteString(fmt.Sprintf("🇷🇺 %s", html.EscapeString(res.Word.RUTranslation)))
+
+	return b.String()
+}
+
+// formatAnswerDiff renders the typed answer with wrong and extra letters
+// struck through and missing letters in bold.
+func formatAnswerDiff(ops []domain.DiffOp) string {
+	var b strings.Builder
+	for _, op := range ops {
+		switch op.Kind {
+		case domain.DiffEqual:
+			b.WriteString(html.EscapeString(string(op.Actual)))
+		case domain.DiffSubstitute:
+			b.WriteString(fmt.Sprintf("<s>%s</s><b>%s</b>",
+				html.EscapeString(string(op.Actual)), html.EscapeString(string(op.Expected))))
+		case domain.DiffMissing:
+			b.WriteString(fmt.Sprintf("<b>%s</b>", html.EscapeString(string(op.Expected))))
+		case domain.DiffExtra:
+			b.WriteString(fmt.Sprintf("<s>%s</s>", html.EscapeString(string(op.Actual))))
+		}
+	}
+
+	return b.String()
+}
+
+func FormatReviewSummary(loc *i18n.Localizer, summary *domain.ReviewSummary) string {
+	var b strings.Builder
+	b.WriteString(loc.T(summaryHeaderText))
+	b.WriteString(loc.Tf(summaryReviewedText, summary.Reviewed))
+
+	grades := make([]string, 0, len(summary.Grades))
+	for grade, count := range summary.Grades {
+		grades = append(grades, fmt.Sprintf("%s — %d", html.EscapeString(loc.T(gradeButtonKeys[grade])), count))
+	}
+	b.WriteString(loc.Tf(summaryGradesText, strings.Join(grades, ", ")))
+
+	b.WriteString(loc.Tf(summaryAccuracyText, int(summary.Accuracy*100+0.5)))
+	b.WriteString(loc.Tf(summaryDurationText, formatDuration(loc, summary.Duration)))
+
+	if len(summary.Hardest) > 0 {
+		hardest := make([]string, 0, len(summary.Hardest))
+		for _, w := range summary.Hardest {
+			hardest = append(hardest, html.EscapeString(w.Spelling))
+		}
+		b.WriteString(loc.Tf(summaryHardestText, strings.Join(hardest, ", ")))
+	}
+
+	b.WriteString(loc.Tf(summaryDueTomorrowText, summary.DueTomorrow))
+
+	return strings.TrimSpace(b.String())
+}
+
+// FormatUserWord shows the word with the user's progress on every card
+// direction of it: the SM-2 state, the latest grades and how the interval
+// changed with them. Dates are shown in tz, the user's time zone.
+func FormatUserWord(loc *i18n.Localizer, word *domain.UserWord, tz *time.Location) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🇬🇧 <b>%s</b> — %s\n",
+		html.EscapeString(word.Spelling), html.EscapeString(word.Transcription)))
+	b.WriteString(fmt.Sprintf("🇷🇺 %s\n\n", html.EscapeString(word.RUTranslation)))
+	b.WriteString(loc.Tf(wordDictionaryText, html.EscapeString(word.DictionaryTitle)))
+
+	status := loc.T(wordStatusNewText)
+	if word.Status != "" {
+		status = loc.T(wordStatusTextPrefix + string(word.Status))
+	}
+	b.WriteString(loc.Tf(wordStatusText, status))
+
+	if word.Status == domain.UserWordStatusBlocked {
+		return strings.TrimSpace(b.String())
+	}
+
+	for _, card := range word.Cards {
+		direction := loc.T(CardDirectionsForwardText)
+		if card.Direction == domain.CardDirectionReverse {
+			direction = loc.T(CardDirectionsReverseText)
+		}
+
+		if card.NextReviewAt == nil {
+			b.WriteString(loc.Tf(wordCardUnscheduledText, direction))
+		} else {
+			b.WriteString(loc.Tf(wordCardDueText, direction, card.NextReviewAt.In(tz).Format(wordDateLayout), card.IntervalDays))
+		}
+		b.WriteString(loc.Tf(wordCardParamsText, card.EF, card.Repetition))
+
+		if len(card.History) == 0 {
+			continue
+		}
+
+		shown := card.History[max(0, len(card.History)-wordGradesShown):]
+		grades := make([]string, 0, len(shown))
+		for _, entry := range shown {
+			grades = append(grades, fmt.Sprintf("%s %s",
+				entry.ReviewedAt.In(tz).Format(wordGradeDateLayout), html.EscapeString(gradeText(loc, entry.Grade))))
+		}
+		b.WriteString(loc.Tf(wordCardGradesText, strings.Join(grades, ", ")))
+
+		intervals := make([]int, 0, len(card.History))
+		for _, entry := range card.History {
+			intervals = append(intervals, entry.IntervalDays)
+		}
+		b.WriteString(loc.Tf(wordCardIntervalsText, sparkline(intervals), intervals[0], intervals[len(intervals)-1]))
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+func FormatReminderSettings(loc *i18n.Localizer, settings *domain.ReminderSettings) string {
+	status := loc.T(reminderOffText)
+	if settings.Enabled {
+		status = loc.T(reminderOnText)
+	}
+
+	quiet := loc.T(reminderQuietOffText)
+	if settings.QuietStart != nil && settings.QuietEnd != nil {
+		quiet = domain.FormatClock(*settings.QuietStart) + "–" + domain.FormatClock(*settings.QuietEnd)
+	}
+
+	timezone := formatTimezone(settings, time.Now())
+
+	return loc.Tf(reminderSettingsText, status, domain.FormatClock(settings.Time), timezone, quiet)
+}
+
+// formatTimezone shows the current UTC offset of the time zone; the fixed
+// offset zones, whose Etc/GMT names have the sign inverted, are shown only
+// as the offset.
+func formatTimezone(settings *domain.ReminderSettings, now time.Time) string {
+	_, offset := now.In(settings.Location()).Zone()
+
+	utc := fmt.Sprintf("UTC%+d", offset/3600)
+	if minutes := offset % 3600 / 60; minutes != 0 {
+		utc += fmt.Sprintf(":%02d", max(minutes, -minutes))
+	}
+
+	if settings.Timezone == "UTC" || strings.HasPrefix(settings.Timezone, "Etc/") {
+		return utc
+	}
+
+	return settings.Timezone + " (" + utc + ")"
+}
+
+// cardDirectionsKeys are the labels of the card directions.
+var cardDirectionsKeys = map[domain.CardDirections]string{
+	domain.CardDirectionsForward: CardDirectionsForwardText,
+	domain.CardDirectionsReverse: CardDirectionsReverseText,
+	domain.CardDirectionsBoth:    CardDirectionsBothText,
+}
+
+// FormatSettings lists the settings of the user. An unset language is shown
+// as the one of loc.
+func FormatSettings(loc *i18n.Localizer, settings *domain.UserSettings) string {
+	language := loc.T(LanguageNameText)
+	if settings.Language != "" {
+		language = i18n.For(settings.Language).T(LanguageNameText)
+	}
+
+	reminders := loc.T(reminderOffText)
+	if settings.Reminder.Enabled {
+		reminders = loc.T(reminderOnText) + ", " + domain.FormatClock(settings.Reminder.Time)
+	}
+
+	return loc.Tf(settingsText,
+		language,
+		formatTimezone(&settings.Reminder, time.Now()),
+		reminders,
+		settings.NewWordsPerDay,
+		settings.ReviewsPerDay,
+		loc.T(reviewModeTextPrefix+string(settings.ReviewMode)),
+		loc.T(cardDirectionsKeys[settings.CardDirections]),
+		loc.T(schedulerTextPrefix+string(settings.Scheduler)),
+		loc.T(goalKindTextPrefix+string(settings.DailyGoal.Kind)),
+		settings.DailyGoal.Target,
+		onOff(loc, settings.StreakFreeze),
+		onOff(loc, settings.Digest),
+		onOff(loc, settings.Notifications),
+	)
+}
+
+// FormatStreak is the caption of the activity calendar.
+func FormatStreak(loc *i18n.Localizer, stats *domain.StreakStats) string {
+	done := ""
+	if stats.Goal.Met(stats.Today) {
+		done = " ✅"
+	}
+
+	return loc.Tf(streakText,
+		stats.Current,
+		stats.Longest,
+		loc.T(goalKindTextPrefix+string(stats.Goal.Kind)),
+		stats.Goal.Progress(stats.Today),
+		stats.Goal.Target,
+		done,
+		stats.Freezes,
+		domain.MaxStreakFreezes,
+		domain.StreakFreezeEvery,
+	)
+}
+
+// FormatStats is the caption of the stats charts.
+func FormatStats(loc *i18n.Localizer, stats *domain.UserStats) string {
+	header := loc.T(statsHeaderAllText)
+	if stats.DictionaryTitle != "" {
+		header = loc.Tf(statsHeaderDictText, html.EscapeString(stats.DictionaryTitle))
+	}
+
+	return header + "\n\n" + loc.Tf(statsText,
+		stats.WordsByStatus[domain.UserWordStatusLearning],
+		stats.WordsByStatus[domain.UserWordStatusSuspended],
+		stats.WordsByStatus[domain.UserWordStatusBlocked],
+		domain.RetentionShortDays,
+		stats.RetentionShort.Rate()*100,
+		stats.RetentionShort.Reviews,
+		domain.StatsDays,
+		stats.RetentionLong.Rate()*100,
+		stats.RetentionLong.Reviews,
+		domain.StatsDays,
+		domain.StatsDays,
+	)
+}
+
+// FormatDigest tells the progress of the digest in HTML: the counts, the
+// hardest words if any, or a nudge to start if the digest is empty.
+func FormatDigest(loc *i18n.Localizer, digest *domain.WeeklyDigest) string {
+	var b strings.Builder
+	b.WriteString(loc.Tf(digestHeaderText, digest.From.Format(digestDateLayout), digest.To.Format(digestDateLayout)))
+	b.WriteString("\n\n")
+
+	if digest.Empty() {
+		b.WriteString(loc.T(digestEmptyText))
+		return b.String()
+	}
+
+	b.WriteString(loc.Tf(digestText,
+		digest.WordsLearned,
+		digest.Retention.Reviews,
+		digest.Retention.Rate()*100,
+		digest.Streak,
+		domain.DigestDays,
+		digest.DueAhead,
+	))
+
+	if len(digest.Hardest) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(loc.T(digestHardestText))
+		for _, w := range digest.Hardest {
+			b.WriteString("\n")
+			b.WriteString(loc.Tf(digestHardestWordText, html.EscapeString(w.Spelling), html.EscapeString(w.RUTranslation), w.Lapses))
+		}
+	}
+
+	return b.String()
+}
+
+// FormatDigestPreview is the digest followed by whether it's sent weekly.
+func FormatDigestPreview(loc *i18n.Localizer, digest *domain.WeeklyDigest, enabled bool) string {
+	status := loc.T(digestOffText)
+	if enabled {
+		status = loc.T(digestOnText)
+	}
+
+	return FormatDigest(loc, digest) + "\n\n" + status
+}
+
+// FormatNotification tells in HTML how many words were added to or unlocked
+// in the dictionary.
+func FormatNotification(loc *i18n.Localizer, notification *domain.Notification) string {
+	return loc.Tf(notificationTextPrefix+string(notification.Kind),
+		html.EscapeString(notification.DictionaryTitle),
+		notification.Words,
+	)
+}
+
+func onOff(loc *i18n.Localizer, on bool) string {
+	if on {
+		return loc.T(reminderOnText)
+	}
+
+	return loc.T(reminderOffText)
+}
+
+// sparkline draws the values as bars scaled to the largest one.
+func sparkline(values []int) string {
+	top := 0
+	for _, v := range values {
+		top = max(top, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if top > 0 {
+			i = v * (len(sparkBlocks) - 1) / top
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+
+	return b.String()
+}
+
+func formatDuration(loc *i18n.Localizer, d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	if seconds < 60 {
+		return loc.Tf(durationSecText, seconds)
+	}
+
+	return loc.Tf(durationMinSecText, seconds/60, seconds%60)
+}
+
+func formatDictionaryMode(loc *i18n.Localizer, mode domain.DictionaryMode) string {
+	return loc.T(dictModeTextPrefix + mode.String())
+}
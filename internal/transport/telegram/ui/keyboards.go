@@ -1,62 +1,122 @@
 package ui
 
-import tele "gopkg.in/telebot.v4"
+import (
+	"strconv"
 
-// Only reply btns contain emoji
+	tele "gopkg.in/telebot.v4"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/transport/telegram/i18n"
+)
+
+// Button keys of the i18n catalog. Only reply btns contain emoji.
 const (
-	MainMenuDictText   = "📚 Словари (публичные)"
-	MainMenuMyDictText = "📖 Мои словари"
-	MainMenuHelpText   = "❔ Помощь"
-
-	AddDictText     = "Добавить словарь"
-	DictDetailsText = "Подробнее"
-	ToDictsText     = "К словарям"
-	RemoveDictText  = "Отписаться"
-
-	ConfirnUnsubText = "Да"
-	RejectUnsubText  = "Нет"
-
-	StartLearnText  = "Учить"
-	StartReviewText = "Повторить"
-
-	LearnAddText       = "✍️ Добавить в словарь"
-	LearnBlockText     = "🙅‍♂️ Не добавлять — знаю это слово"
-	LearnReviewText    = "🧠 Перейти к повторению слов"
-	LearnReviewNowText = "Перейти к повторению сейчас"
-
-	ReviewStartText   = "🚀 Старт"
-	ReviewRestartText = "🔁 Повторить еще раз"
-	ReviewStopText    = "️🏁 Закончить подход"
-	ReviewRate1Text   = "Не помню"
-	ReviewRate2Text   = "Трудно"
-	ReviewRate3Text   = "Легко"
-	ReviewRate4Text   = "Помню!"
-	ReviewForceStart  = "Все равно хочу попрактиковаться"
-
-	ToMainMenuText = "🏠 В главное меню"
+	MainMenuDictText      = "btn.main_dict"
+	MainMenuMyDictText    = "btn.main_my_dict"
+	MainMenuReviewAllText = "btn.main_review_all"
+	MainMenuHelpText      = "btn.main_help"
+
+	AddDictText     = "btn.add_dict"
+	DictDetailsText = "btn.dict_details"
+	ToDictsText     = "btn.to_dicts"
+	RemoveDictText  = "btn.remove_dict"
+
+	ConfirnUnsubText = "btn.confirm_unsub"
+	RejectUnsubText  = "btn.reject_unsub"
+
+	CardDirectionsText        = "btn.card_directions"
+	CardDirectionsForwardText = "btn.directions_forward"
+	CardDirectionsReverseText = "btn.directions_reverse"
+	CardDirectionsBothText    = "btn.directions_both"
+
+	StartLearnText  = "btn.start_learn"
+	StartReviewText = "btn.start_review"
+
+	LearnAddText       = "btn.learn_add"
+	LearnBlockText     = "btn.learn_block"
+	LearnReviewText    = "btn.learn_review"
+	LearnReviewNowText = "btn.learn_review_now"
+	LearnBatchText     = "btn.learn_batch"
+	LearnBatchQuizText = "btn.learn_batch_quiz"
+
+	ReviewStartText          = "btn.review_start"
+	ReviewStartTypedText     = "btn.review_start_typed"
+	ReviewStartQuizText      = "btn.review_start_quiz"
+	ReviewStartClozeText     = "btn.review_start_cloze"
+	ReviewStartListeningText = "btn.review_start_listening"
+	ReviewRestartText        = "btn.review_restart"
+	ReviewStopText           = "btn.review_stop"
+	ReviewRate1Text          = "btn.review_rate1"
+	ReviewRate2Text          = "btn.review_rate2"
+	ReviewRate3Text          = "btn.review_rate3"
+	ReviewRate4Text          = "btn.review_rate4"
+	ReviewDontKnowText       = "btn.review_dont_know"
+	ReviewUndoText           = "btn.review_undo"
+	ReviewForceStart         = "btn.review_force"
+
+	ReviewKeyboardInlineText = "btn.review_kb_inline"
+	ReviewKeyboardReplyText  = "btn.review_kb_reply"
+
+	WordTomorrowText = "btn.word_tomorrow"
+	WordResetText    = "btn.word_reset"
+	WordResumeText   = "btn.word_resume"
+	WordUnblockText  = "btn.word_unblock"
+	WordSuspendText  = "btn.word_suspend"
+	WordKnownText    = "btn.word_known"
+
+	ReminderReviewText = "btn.reminder_review"
+	ReminderOnText     = "btn.reminder_on"
+	ReminderOffText    = "btn.reminder_off"
+
+	DigestOnText  = "btn.digest_on"
+	DigestOffText = "btn.digest_off"
+
+	SettingsTimezoneText       = "btn.settings_tz"
+	SettingsReminderTimeText   = "btn.settings_rtime"
+	SettingsRemindersOnText    = "btn.settings_remind_on"
+	SettingsRemindersOffText   = "btn.settings_remind_off"
+	SettingsNewWordsText       = "btn.settings_new"
+	SettingsReviewsText        = "btn.settings_reviews"
+	SettingsReviewModeText     = "btn.settings_mode"
+	SettingsCardDirectionsText = "btn.settings_dirs"
+	SettingsSchedulerText      = "btn.settings_sched"
+	SettingsLanguageText       = "btn.settings_language"
+	SettingsReviewKeyboardText = "btn.settings_review_kb"
+	SettingsGoalKindText       = "btn.settings_goal"
+	SettingsGoalTargetText     = "btn.settings_goal_target"
+	SettingsFreezeOnText       = "btn.settings_freeze_on"
+	SettingsFreezeOffText      = "btn.settings_freeze_off"
+	SettingsDigestOnText       = "btn.settings_digest_on"
+	SettingsDigestOffText      = "btn.settings_digest_off"
+	SettingsNotifyOnText       = "btn.settings_notify_on"
+	SettingsNotifyOffText      = "btn.settings_notify_off"
+
+	ToMainMenuText = "btn.to_main_menu"
 )
 
-func BuildMainMenuReplyKb() *tele.ReplyMarkup {
+func BuildMainMenuReplyKb(loc *i18n.Localizer) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{ResizeKeyboard: true}
 
-	btnDict := markup.Text(MainMenuDictText)
-	btnList := markup.Text(MainMenuMyDictText)
-	btnHelp := markup.Text(MainMenuHelpText)
+	btnDict := markup.Text(loc.T(MainMenuDictText))
+	btnList := markup.Text(loc.T(MainMenuMyDictText))
+	btnReviewAll := markup.Text(loc.T(MainMenuReviewAllText))
+	btnHelp := markup.Text(loc.T(MainMenuHelpText))
 
 	markup.Reply(
 		markup.Row(btnDict),
 		markup.Row(btnList),
+		markup.Row(btnReviewAll),
 		markup.Row(btnHelp),
 	)
 
 	return markup
 }
 
-func BuildPublicDictionaryInlineKb(dictionaryID string) *tele.ReplyMarkup {
+func BuildPublicDictionaryInlineKb(loc *i18n.Localizer, dictionaryID string) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{}
 
-	btnSubscribe := markup.Data(AddDictText, "dict_subscribe", dictionaryID)
-	btnDetails := markup.Data(DictDetailsText, "dict_details", dictionaryID)
+	btnSubscribe := markup.Data(loc.T(AddDictText), "dict_subscribe", dictionaryID)
+	btnDetails := markup.Data(loc.T(DictDetailsText), "dict_details", dictionaryID)
 
 	markup.Inline(
 		markup.Row(btnSubscribe, btnDetails),
@@ -65,26 +125,28 @@ func BuildPublicDictionaryInlineKb(dictionaryID string) *tele.ReplyMarkup {
 	return markup
 }
 
-func BuildUserDictionaryInlineKb(dictionaryID string) *tele.ReplyMarkup {
+func BuildUserDictionaryInlineKb(loc *i18n.Localizer, dictionaryID string) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{}
 
-	btnLearn := markup.Data(StartLearnText, "dict_learn", dictionaryID)
-	btnReview := markup.Data(StartReviewText, "dict_review", dictionaryID)
-	btnUnsubscribe := markup.Data(RemoveDictText, "dict_unsubscribe", dictionaryID)
+	btnLearn := markup.Data(loc.T(StartLearnText), "dict_learn", dictionaryID)
+	btnReview := markup.Data(loc.T(StartReviewText), "dict_review", dictionaryID)
+	btnUnsubscribe := markup.Data(loc.T(RemoveDictText), "dict_unsubscribe", dictionaryID)
+	btnDirections := markup.Data(loc.T(CardDirectionsText), "dict_dirs", dictionaryID)
 
 	markup.Inline(
 		markup.Row(btnLearn),
 		markup.Row(btnReview, btnUnsubscribe),
+		markup.Row(btnDirections),
 	)
 
 	return markup
 }
 
-func BuildDictionaryDetailsInlineKb(dictionaryID string) *tele.ReplyMarkup {
+func BuildDictionaryDetailsInlineKb(loc *i18n.Localizer, dictionaryID string) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{}
 
-	btnSubscribe := markup.Data(AddDictText, "dict_subscribe", dictionaryID)
-	btnDetails := markup.Data(ToDictsText, "to_dicts")
+	btnSubscribe := markup.Data(loc.T(AddDictText), "dict_subscribe", dictionaryID)
+	btnDetails := markup.Data(loc.T(ToDictsText), "to_dicts")
 
 	markup.Inline(
 		markup.Row(btnSubscribe, btnDetails),
@@ -93,11 +155,11 @@ func BuildDictionaryDetailsInlineKb(dictionaryID string) *tele.ReplyMarkup {
 	return markup
 }
 
-func BuildUnsubscribeConfirmInlineKb(dictionaryID string) *tele.ReplyMarkup {
+func BuildUnsubscribeConfirmInlineKb(loc *i18n.Localizer, dictionaryID string) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{}
 
-	btnConfirm := markup.Data(ConfirnUnsubText, "dict_confirm_unsubscribe", dictionaryID)
-	btnReject := markup.Data(RejectUnsubText, "dict_reject_unsubscribe", dictionaryID)
+	btnConfirm := markup.Data(loc.T(ConfirnUnsubText), "dict_confirm_unsubscribe", dictionaryID)
+	btnReject := markup.Data(loc.T(RejectUnsubText), "dict_reject_unsubscribe", dictionaryID)
 
 	markup.Inline(
 		markup.Row(btnConfirm, btnReject),
@@ -106,16 +168,48 @@ func BuildUnsubscribeConfirmInlineKb(dictionaryID string) *tele.ReplyMarkup {
 	return markup
 }
 
-func BuildLearningReplyKb() *tele.ReplyMarkup {
+func BuildCardDirectionsInlineKb(
+	loc *i18n.Localizer,
+	dictionaryID string,
+	current domain.CardDirections,
+) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	options := []struct {
+		directions domain.CardDirections
+		text       string
+	}{
+		{domain.CardDirectionsForward, CardDirectionsForwardText},
+		{domain.CardDirectionsReverse, CardDirectionsReverseText},
+		{domain.CardDirectionsBoth, CardDirectionsBothText},
+	}
+
+	btns := make([]tele.Btn, 0, len(options))
+	for _, o := range options {
+		text := loc.T(o.text)
+		if o.directions == current {
+			text = "✅ " + text
+		}
+		btns = append(btns, markup.Data(text, "dict_dirs_set", dictionaryID, string(o.directions)))
+	}
+
+	markup.Inline(markup.Row(btns...))
+
+	return markup
+}
+
+func BuildLearningReplyKb(loc *i18n.Localizer) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{ResizeKeyboard: true}
 
-	btnAdd := markup.Text(LearnAddText)
-	btnBlock := markup.Text(LearnBlockText)
-	btnReview := markup.Text(LearnReviewText)
-	btnBack := markup.Text(ToMainMenuText)
+	btnAdd := markup.Text(loc.T(LearnAddText))
+	btnBlock := markup.Text(loc.T(LearnBlockText))
+	btnBatch := markup.Text(loc.T(LearnBatchText))
+	btnReview := markup.Text(loc.T(LearnReviewText))
+	btnBack := markup.Text(loc.T(ToMainMenuText))
 
 	markup.Reply(
 		markup.Row(btnAdd, btnBlock),
+		markup.Row(btnBatch),
 		markup.Row(btnReview),
 		markup.Row(btnBack),
 	)
@@ -123,20 +217,43 @@ func BuildLearningReplyKb() *tele.ReplyMarkup {
 	return markup
 }
 
-func BuildLearningCompletedInlineKb(dictionaryID string) *tele.ReplyMarkup {
+func BuildLearningCompletedInlineKb(loc *i18n.Localizer, dictionaryID string) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{}
 
-	btnReview := markup.Data(LearnReviewNowText, "dict_review", dictionaryID)
+	btnReview := markup.Data(loc.T(LearnReviewNowText), "dict_review", dictionaryID)
 	markup.Inline(markup.Row(btnReview))
 
 	return markup
 }
 
-func BuildLearningCompletedReplyKb() *tele.ReplyMarkup {
+func BuildLearnBatchInlineKb(loc *i18n.Localizer, batchID string) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	btnQuiz := markup.Data(loc.T(LearnBatchQuizText), "learn_batch_quiz", batchID)
+	markup.Inline(markup.Row(btnQuiz))
+
+	return markup
+}
+
+// BuildLearnBatchQuizInlineKb is BuildReviewQuizInlineKb of a batch card.
+func BuildLearnBatchQuizInlineKb(card *domain.LearnBatchCard) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	rows := make([]tele.Row, 0, len(card.Quiz.Options))
+	for i, option := range card.Quiz.Options {
+		btn := markup.Data(option, "learn_quiz", card.Token, strconv.Itoa(i))
+		rows = append(rows, markup.Row(btn))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+func BuildLearningCompletedReplyKb(loc *i18n.Localizer) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{ResizeKeyboard: true}
 
-	btnReview := markup.Text(LearnReviewText)
-	btnBack := markup.Text(ToMainMenuText)
+	btnReview := markup.Text(loc.T(LearnReviewText))
+	btnBack := markup.Text(loc.T(ToMainMenuText))
 
 	markup.Reply(
 		markup.Row(btnReview),
@@ -146,56 +263,360 @@ func BuildLearningCompletedReplyKb() *tele.ReplyMarkup {
 	return markup
 }
 
-func BuildReviewIntroReplyKb() *tele.ReplyMarkup {
+func BuildReviewIntroReplyKb(loc *i18n.Localizer) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{ResizeKeyboard: true}
 
-	btnStart := markup.Text(ReviewStartText)
-	btnMain := markup.Text(ToMainMenuText)
+	btnStart := markup.Text(loc.T(ReviewStartText))
+	btnStartTyped := markup.Text(loc.T(ReviewStartTypedText))
+	btnStartQuiz := markup.Text(loc.T(ReviewStartQuizText))
+	btnStartCloze := markup.Text(loc.T(ReviewStartClozeText))
+	btnStartListening := markup.Text(loc.T(ReviewStartListeningText))
+	btnMain := markup.Text(loc.T(ToMainMenuText))
 
 	markup.Reply(
 		markup.Row(btnStart),
+		markup.Row(btnStartTyped, btnStartQuiz),
+		markup.Row(btnStartCloze, btnStartListening),
 		markup.Row(btnMain),
 	)
 
 	return markup
 }
 
-func BuildReviewRateReplyKb() *tele.ReplyMarkup {
+func BuildReviewRateReplyKb(loc *i18n.Localizer) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{ResizeKeyboard: true}
 
-	btn1 := markup.Text(ReviewRate1Text)
-	btn2 := markup.Text(ReviewRate2Text)
-	btn3 := markup.Text(ReviewRate3Text)
-	btn4 := markup.Text(ReviewRate4Text)
-	btnStop := markup.Text(ReviewStopText)
+	btn1 := markup.Text(loc.T(ReviewRate1Text))
+	btn2 := markup.Text(loc.T(ReviewRate2Text))
+	btn3 := markup.Text(loc.T(ReviewRate3Text))
+	btn4 := markup.Text(loc.T(ReviewRate4Text))
+	btnUndo := markup.Text(loc.T(ReviewUndoText))
+	btnStop := markup.Text(loc.T(ReviewStopText))
 
 	markup.Reply(
 		markup.Row(btn1, btn2, btn3, btn4),
-		markup.Row(btnStop),
+		markup.Row(btnUndo, btnStop),
+	)
+
+	return markup
+}
+
+func BuildReviewTypedReplyKb(loc *i18n.Localizer) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{ResizeKeyboard: true}
+
+	btnDontKnow := markup.Text(loc.T(ReviewDontKnowText))
+	btnUndo := markup.Text(loc.T(ReviewUndoText))
+	btnStop := markup.Text(loc.T(ReviewStopText))
+
+	markup.Reply(
+		markup.Row(btnDontKnow),
+		markup.Row(btnUndo, btnStop),
 	)
 
 	return markup
 }
 
-func BuildReviewForceInlineKb(dictionaryID string) *tele.ReplyMarkup {
+func BuildReviewQuizReplyKb(loc *i18n.Localizer) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{ResizeKeyboard: true}
+
+	btnUndo := markup.Text(loc.T(ReviewUndoText))
+	btnStop := markup.Text(loc.T(ReviewStopText))
+	markup.Reply(markup.Row(btnUndo, btnStop))
+
+	return markup
+}
+
+// BuildReviewQuizInlineKb puts every option on its own row. The card token in
+// the data lets the handler reject buttons of already answered cards. An
+// inline card also gets the undo and stop buttons, which otherwise are on the
+// reply keyboard.
+func BuildReviewQuizInlineKb(loc *i18n.Localizer, card *domain.ReviewCard) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	unique := "quiz_answer"
+	if card.Inline() {
+		unique = "rv_quiz"
+	}
+
+	rows := make([]tele.Row, 0, len(card.Quiz.Options)+1)
+	for i, option := range card.Quiz.Options {
+		btn := markup.Data(option, unique, card.Token, strconv.Itoa(i))
+		rows = append(rows, markup.Row(btn))
+	}
+	if card.Inline() {
+		btnUndo := markup.Data(loc.T(ReviewUndoText), "rv_undo", card.Token)
+		btnStop := markup.Data(loc.T(ReviewStopText), "rv_stop", card.Token)
+		rows = append(rows, markup.Row(btnUndo, btnStop))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// BuildReviewRateInlineKb is BuildReviewRateReplyKb of an inline card.
+func BuildReviewRateInlineKb(loc *i18n.Localizer, card *domain.ReviewCard) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	rates := []string{ReviewRate1Text, ReviewRate2Text, ReviewRate3Text, ReviewRate4Text}
+	btns := make([]tele.Btn, 0, len(rates))
+	for grade, text := range rates {
+		btns = append(btns, markup.Data(loc.T(text), "rv_rate", card.Token, strconv.Itoa(grade)))
+	}
+	btnUndo := markup.Data(loc.T(ReviewUndoText), "rv_undo", card.Token)
+	btnStop := markup.Data(loc.T(ReviewStopText), "rv_stop", card.Token)
+
+	markup.Inline(
+		markup.Row(btns...),
+		markup.Row(btnUndo, btnStop),
+	)
+
+	return markup
+}
+
+// BuildReviewFinishInlineKb is shown under the summary of a round reviewed in
+// one message. token is of the last card, so the round can still be undone.
+func BuildReviewFinishInlineKb(loc *i18n.Localizer, token, dictionaryID string) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{}
 
-	btnForce := markup.Data(ReviewForceStart, "review_force", dictionaryID)
+	rows := []tele.Row{markup.Row(markup.Data(loc.T(ReviewUndoText), "rv_undo", token))}
+	// A cross-dictionary round has no dictionary to force-review.
+	if dictionaryID != "" {
+		rows = append(rows, markup.Row(markup.Data(loc.T(ReviewForceStart), "review_force", dictionaryID)))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+func BuildReviewForceInlineKb(loc *i18n.Localizer, dictionaryID string) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	btnForce := markup.Data(loc.T(ReviewForceStart), "review_force", dictionaryID)
 	markup.Inline(markup.Row(btnForce))
 
 	return markup
 }
 
-func BuildReviewFinishReplyKb() *tele.ReplyMarkup {
+func BuildReviewFinishReplyKb(loc *i18n.Localizer) *tele.ReplyMarkup {
 	markup := &tele.ReplyMarkup{ResizeKeyboard: true}
 
-	btnRestart := markup.Text(ReviewRestartText)
-	btnMain := markup.Text(ToMainMenuText)
+	btnRestart := markup.Text(loc.T(ReviewRestartText))
+	btnUndo := markup.Text(loc.T(ReviewUndoText))
+	btnMain := markup.Text(loc.T(ToMainMenuText))
 
 	markup.Reply(
 		markup.Row(btnRestart),
-		markup.Row(btnMain),
+		markup.Row(btnUndo, btnMain),
 	)
 
 	return markup
 }
+
+func BuildReviewKeyboardInlineKb(loc *i18n.Localizer, current domain.ReviewKeyboard) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	options := []struct {
+		keyboard domain.ReviewKeyboard
+		text     string
+	}{
+		{domain.ReviewKeyboardInline, ReviewKeyboardInlineText},
+		{domain.ReviewKeyboardReply, ReviewKeyboardReplyText},
+	}
+
+	rows := make([]tele.Row, 0, len(options))
+	for _, o := range options {
+		text := loc.T(o.text)
+		if o.keyboard == current {
+			text = "✅ " + text
+		}
+		rows = append(rows, markup.Row(markup.Data(text, "set_review_kb", string(o.keyboard))))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// BuildSetAsideWordsInlineKb lists the words, a button a row, to open their
+// cards.
+func BuildSetAsideWordsInlineKb(loc *i18n.Localizer, words []*domain.UserWord) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	rows := make([]tele.Row, 0, len(words))
+	for _, w := range words {
+		text := w.Spelling + " — " + loc.T(wordStatusTextPrefix+string(w.Status))
+		rows = append(rows, markup.Row(markup.Data(text, "word_open", w.ID)))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// BuildUserWordInlineKb offers the actions that apply to the word; the ones
+// taking a number of days or months get a row of presets.
+func BuildUserWordInlineKb(loc *i18n.Localizer, word *domain.UserWord) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	btn := func(text string, action domain.WordAction, arg int) tele.Btn {
+		return markup.Data(text, "word_act", word.ID, string(action), strconv.Itoa(arg))
+	}
+
+	simple := map[domain.WordAction]string{
+		domain.WordActionTomorrow: WordTomorrowText,
+		domain.WordActionReset:    WordResetText,
+		domain.WordActionResume:   WordResumeText,
+		domain.WordActionUnblock:  WordUnblockText,
+	}
+
+	var main []tele.Btn
+	var rows []tele.Row
+	for _, action := range word.Status.Actions() {
+		switch action {
+		case domain.WordActionSuspend:
+			presets := make([]tele.Btn, 0, len(domain.WordSuspendDays))
+			for _, days := range domain.WordSuspendDays {
+				presets = append(presets, btn(loc.Tf(WordSuspendText, days), action, days))
+			}
+			rows = append(rows, markup.Row(presets...))
+		case domain.WordActionKnown:
+			presets := make([]tele.Btn, 0, len(domain.WordKnownMonths))
+			for _, months := range domain.WordKnownMonths {
+				presets = append(presets, btn(loc.Tf(WordKnownText, months), action, months))
+			}
+			rows = append(rows, markup.Row(presets...))
+		default:
+			main = append(main, btn(loc.T(simple[action]), action, 0))
+		}
+	}
+	if len(main) > 0 {
+		rows = append([]tele.Row{markup.Row(main...)}, rows...)
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+func BuildReminderInlineKb(loc *i18n.Localizer) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	btnReview := markup.Data(loc.T(ReminderReviewText), "remind_review")
+	markup.Inline(markup.Row(btnReview))
+
+	return markup
+}
+
+// BuildReminderSettingsInlineKb offers to turn the reminders off or on,
+// whichever they are not.
+func BuildReminderSettingsInlineKb(loc *i18n.Localizer, enabled bool) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	btn := markup.Data(loc.T(ReminderOnText), "remind_set", "on")
+	if enabled {
+		btn = markup.Data(loc.T(ReminderOffText), "remind_set", "off")
+	}
+	markup.Inline(markup.Row(btn))
+
+	return markup
+}
+
+// BuildDigestInlineKb offers to turn the weekly digest off or on, whichever it
+// is not.
+func BuildDigestInlineKb(loc *i18n.Localizer, enabled bool) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	btn := markup.Data(loc.T(DigestOnText), "digest_set", "on")
+	if enabled {
+		btn = markup.Data(loc.T(DigestOffText), "digest_set", "off")
+	}
+	markup.Inline(markup.Row(btn))
+
+	return markup
+}
+
+// BuildNotificationInlineKb starts learning the dictionary.
+func BuildNotificationInlineKb(loc *i18n.Localizer, dictionaryID string) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	btnLearn := markup.Data(loc.T(StartLearnText), "dict_learn", dictionaryID)
+	markup.Inline(markup.Row(btnLearn))
+
+	return markup
+}
+
+// Pickers opened from the settings menu.
+const (
+	SettingsPickLanguage       = "lang"
+	SettingsPickReviewKeyboard = "kb"
+)
+
+// BuildSettingsInlineKb steps the numeric settings back and forth, switches
+// the choices to the next option and opens the pickers of the language and
+// of how review cards are shown.
+func BuildSettingsInlineKb(loc *i18n.Localizer, settings *domain.UserSettings) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	btn := func(text string, field domain.SettingField, delta int) tele.Btn {
+		return markup.Data(text, "settings", string(field), strconv.Itoa(delta))
+	}
+	steps := func(key string, field domain.SettingField, step int) tele.Row {
+		return markup.Row(
+			btn(loc.Tf(key, -step), field, -1),
+			btn(loc.Tf(key, step), field, 1),
+		)
+	}
+
+	remindersText := SettingsRemindersOnText
+	if settings.Reminder.Enabled {
+		remindersText = SettingsRemindersOffText
+	}
+	freezeText := SettingsFreezeOnText
+	if settings.StreakFreeze {
+		freezeText = SettingsFreezeOffText
+	}
+	digestText := SettingsDigestOnText
+	if settings.Digest {
+		digestText = SettingsDigestOffText
+	}
+	notifyText := SettingsNotifyOnText
+	if settings.Notifications {
+		notifyText = SettingsNotifyOffText
+	}
+
+	markup.Inline(
+		markup.Row(
+			markup.Data(loc.T(SettingsLanguageText), "settings_pick", SettingsPickLanguage),
+			markup.Data(loc.T(SettingsReviewKeyboardText), "settings_pick", SettingsPickReviewKeyboard),
+		),
+		steps(SettingsTimezoneText, domain.SettingTimezone, 1),
+		steps(SettingsReminderTimeText, domain.SettingReminderTime, 1),
+		markup.Row(btn(loc.T(remindersText), domain.SettingReminders, 1)),
+		steps(SettingsNewWordsText, domain.SettingNewWordsPerDay, domain.NewWordsPerDayStep),
+		steps(SettingsReviewsText, domain.SettingReviewsPerDay, domain.ReviewsPerDayStep),
+		markup.Row(btn(loc.T(SettingsReviewModeText), domain.SettingReviewMode, 1)),
+		markup.Row(btn(loc.T(SettingsCardDirectionsText), domain.SettingCardDirections, 1)),
+		markup.Row(btn(loc.T(SettingsSchedulerText), domain.SettingScheduler, 1)),
+		steps(SettingsGoalTargetText, domain.SettingGoalTarget, domain.DailyGoalStep),
+		markup.Row(
+			btn(loc.T(SettingsGoalKindText), domain.SettingGoalKind, 1),
+			btn(loc.T(freezeText), domain.SettingStreakFreeze, 1),
+		),
+		markup.Row(
+			btn(loc.T(digestText), domain.SettingDigest, 1),
+			btn(loc.T(notifyText), domain.SettingNotifications, 1),
+		),
+	)
+
+	return markup
+}
+
+func BuildLanguageInlineKb() *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+
+	rows := make([]tele.Row, 0, len(i18n.Supported()))
+	for _, lang := range i18n.Supported() {
+		btn := markup.Data(i18n.For(lang).T(LanguageNameText), "set_language", lang)
+		rows = append(rows, markup.Row(btn))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
@@ -1,69 +1,115 @@
 package ui
 
-// Onboarding
-const WelcomeMsg = `Привет, меня зовут Ингли, я твой помощник в изучении английских слов! 👾
-
-Я работаю по интервальным повторениям — просто и эффективно. Подписывайся на словари, учи слова, а я как твой бортовой компьютер буду вычислять усвоение материала: сложные слова буду показывать чаще, и про лёгкие тоже не забудем 😉
-` + HelpMsg
-
-const HelpMsg = `
-Нажимай на кнопки внизу экрана, либо пиши следующие команды в чат:
-
-- /start - еще раз посмотреть приветственное сообщение 🙂
-- /help - посмотреть список команд 📖
-- /dict - список опубликованных словарей, на которые можно подписаться 📚
-- /mydict - список словарей, на которые ты подписан. Из них можно учить слова 📚
-- /learn <номер словаря> - приступить к изучению: я буду показывать тебе новые слова и их перевод. Старайся запомнить!  🧠
-- /review <номер словаря> - приступить к повторению: оценивай, насколько хорошо помнишь слова, и я буду подбрасывать их снова (чем хуже помнишь — тем чаще будут выпадать) 🎲
-`
+// Message keys of the i18n catalog. Texts live in i18n/locales/*.json.
 
-const RemoveMsg = `Все данные удалены 🫥`
+// Onboarding
+const (
+	WelcomeMsg = "onboarding.welcome"
+	HelpMsg    = "onboarding.help"
+	RemoveMsg  = "onboarding.remove"
+)
 
 // Catalog
 const (
-	PublicDictionariesEmptyMsg  = `Пока нет опубликованных словарей 💤`
-	UserDictionariesEmptyMsg    = `У тебя нет добавленных словарей 💤`
-	PublicDictionariesHeaderMsg = `Доступные словари:`
-	UserDictionariesHeaderMsg   = `Твои словари:`
+	PublicDictionariesEmptyMsg  = "catalog.public_empty"
+	UserDictionariesEmptyMsg    = "catalog.user_empty"
+	PublicDictionariesHeaderMsg = "catalog.public_header"
+	UserDictionariesHeaderMsg   = "catalog.user_header"
 )
 
 // Subscription
 const (
-	DictionarySubscribedMsg           = `Добавил в "Мои словари" ✅`
-	DictionaryAlreadySubscribedMsg    = `Ты уже добавлял этот словарь 👌`
-	DictionaryUnsubscribeConfirmMsg   = `❕Весь прогресс по словам будет стёрт. Ты уверен, что хочешь отписаться?`
-	DictionaryUnsubscribedMsg         = `Подписка отменена ✅`
-	DictionaryUnsubscribeCanceledMsg  = `Ты по-прежнему подписан на этот словарь 👌`
-	DictionarySubscriptionNotFoundMsg = `Ты не подписан на этот словарь`
-	DictionaryNotFoundMsg             = `Словарь не найден 🧐`
+	DictionarySubscribedMsg           = "subscription.subscribed"
+	DictionaryAlreadySubscribedMsg    = "subscription.already_subscribed"
+	DictionaryUnsubscribeConfirmMsg   = "subscription.unsubscribe_confirm"
+	DictionaryUnsubscribedMsg         = "subscription.unsubscribed"
+	DictionaryUnsubscribeCanceledMsg  = "subscription.unsubscribe_canceled"
+	DictionarySubscriptionNotFoundMsg = "subscription.not_found"
+	DictionaryNotFoundMsg             = "dictionary.not_found"
+	CardDirectionsChooseMsg           = "subscription.card_directions_choose"
+	CardDirectionsChangedMsg          = "subscription.card_directions_changed"
 )
 
 // Learning
 const (
-	LearnUsageMsg      = `Использование: /learn <номер словаря из списка>`
-	LearnNotStartedMsg = `Сначала открой обучение через /learn <номер словаря> или кнопку «Учить» у себя в словарях`
+	LearnUsageMsg      = "learning.usage"
+	LearnNotStartedMsg = "learning.not_started"
+	LearnCompletedMsg  = "learning.completed"
+	LearnDailyLimitMsg = "learning.daily_limit"
 
-	LearnCompletedMsg = `
-Ты прошел словарь! 🎉🥳🎉
-А ты уверен, что помнишь все слова из него?
-`
+	LearnBatchQuizStartedMsg = "learning.batch_quiz_started"
+	LearnBatchCompletedMsg   = "learning.batch_completed"
 )
 
 // Review
 const (
-	ReviewUsageMsg          = `Использование: /review <номер словаря из списка>`
-	ReviewIntroMsg          = `🕹️ Я начну показывать слова из этого словаря, которые мы уже рассматривали — отмечай, насколько хорошо их ты помнишь, нажимая на кнопки внизу экрана`
-	ReviewNoDueMsg          = `У тебя отличный прогресс. Я уверен, тебе пока не нужно повторять слова`
-	ReviewEmptyWordsListMsg = `Сначала изучи слова, а после заходи, и будем вместе их повторять ☕️`
-	ReviewCompletedMsg      = `Ты повторил все изученные слова из этого словаря 🥳`
+	ReviewUsageMsg          = "review.usage"
+	ReviewIntroMsg          = "review.intro"
+	ReviewIntroAllMsg       = "review.intro_all"
+	ReviewNoDueMsg          = "review.no_due"
+	ReviewDailyLimitMsg     = "review.daily_limit"
+	ReviewEmptyWordsListMsg = "review.empty_words"
+	ReviewCompletedMsg      = "review.completed"
+	ReviewCompletedAllMsg   = "review.completed_all"
+	ReviewNothingToUndoMsg  = "review.nothing_to_undo"
+	ReviewTypedPromptMsg    = "review.typed_prompt"
+	ReviewQuizIntroMsg      = "review.quiz_intro"
+	ReviewCardStaleMsg      = "review.card_stale"
+	ReviewCardChangedMsg    = "review.card_changed"
+	ReviewInlineIntroMsg    = "review.inline_intro"
+
+	ReviewKeyboardChooseMsg  = "review_kb.choose"
+	ReviewKeyboardChangedMsg = "review_kb.changed"
+)
+
+// Words
+const (
+	WordUsageMsg         = "word.usage"
+	WordSetAsideMsg      = "word.set_aside"
+	WordNotFoundMsg      = "word.not_found"
+	WordAppliedMsg       = "word.applied"
+	WordNotApplicableMsg = "word.not_applicable"
+)
+
+// Reminders
+const (
+	ReminderMsg                = "reminder.text"
+	ReminderInvalidTimeMsg     = "reminder.invalid_time"
+	ReminderInvalidTimezoneMsg = "reminder.invalid_timezone"
+	ReminderInvalidQuietMsg    = "reminder.invalid_quiet"
+	ReminderChangedMsg         = "reminder.changed"
+)
+
+// Language
+const (
+	LanguageUsageMsg   = "language.usage"
+	LanguageChooseMsg  = "language.choose"
+	LanguageChangedMsg = "language.changed"
+	LanguageNameText   = "language.name"
+)
+
+// Settings
+const (
+	SettingsChangedMsg = "settings.changed"
+)
+
+// Stats
+const (
+	StatsUsageMsg = "stats.usage"
+)
+
+// Digest
+const (
+	DigestUsageMsg   = "digest.usage"
+	DigestChangedMsg = "digest.changed"
 )
 
 // Other messages
 const (
-	ToMainMenuMsg = "⏮️ Возврат в меню"
+	ToMainMenuMsg = "common.to_main_menu"
 
-	InternalErrorMsg           = `Бип-буп, я сломался 😵‍💫`
-	InvalidDictionaryNumberMsg = `Не нашел словарь с таким номером. Посмотри /mydict и попробуй снова`
-	NotSubscribedMsg           = `Сначала добавь этот словарь к себе, потом приступай к изучению`
-	ActiveDictMissingMsg       = `Стой 🫨, кажется я не вижу словарь, в котором ты находишься`
+	InternalErrorMsg           = "common.internal_error"
+	InvalidDictionaryNumberMsg = "common.invalid_dict_number"
+	NotSubscribedMsg           = "common.not_subscribed"
+	ActiveDictMissingMsg       = "common.active_dict_missing"
 )
@@ -0,0 +1,52 @@
+// Package broadcast goes through the recipients of a background job, such as
+// the reminders or the digests, page by page.
+package broadcast
+
+import (
+	"context"
+	"fmt"
+)
+
+// PageSize is how many recipients are loaded at once.
+const PageSize = 500
+
+// ListFunc loads up to limit recipients with keys after the key, in the order
+// of the keys.
+type ListFunc[T any] func(ctx context.Context, after int64, limit int) ([]T, error)
+
+// Send hands every recipient listed to send and returns how many send
+// reported sent. The pace of the messages is up to the sender. Send reports
+// its failures itself, so that one recipient doesn't hold up the rest; Send
+// only stops when listing fails or ctx is done.
+func Send[T any](
+	ctx context.Context,
+	list ListFunc[T],
+	key func(T) int64,
+	send func(context.Context, T) bool,
+) (int, error) {
+	const op = "Send"
+
+	sent := 0
+	var after int64
+	for {
+		recipients, err := list(ctx, after, PageSize)
+		if err != nil {
+			return sent, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, recipient := range recipients {
+			if err = ctx.Err(); err != nil {
+				return sent, fmt.Errorf("%s: %w", op, err)
+			}
+
+			if send(ctx, recipient) {
+				sent++
+			}
+		}
+
+		if len(recipients) < PageSize {
+			return sent, nil
+		}
+		after = key(recipients[len(recipients)-1])
+	}
+}
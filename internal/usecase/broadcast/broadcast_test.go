@@ -0,0 +1,74 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		recipients int
+		pages      int
+	}{
+		{"none", 0, 1},
+		{"part of a page", 3, 1},
+		{"full page", PageSize, 2},
+		{"several pages", 2*PageSize + 1, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := 0
+			list := func(_ context.Context, after int64, limit int) ([]int64, error) {
+				pages++
+				var page []int64
+				for key := after + 1; key <= int64(tt.recipients) && len(page) < limit; key++ {
+					page = append(page, key)
+				}
+				return page, nil
+			}
+
+			seen := 0
+			// Only the even recipients are sent to.
+			send := func(_ context.Context, key int64) bool {
+				seen++
+				if key != int64(seen) {
+					t.Errorf("recipient %d handed out as the %d-th", key, seen)
+				}
+				return key%2 == 0
+			}
+
+			sent, err := Send(context.Background(), list, func(key int64) int64 { return key }, send)
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if seen != tt.recipients || sent != tt.recipients/2 || pages != tt.pages {
+				t.Errorf("Send = %d sent of %d seen in %d pages, want %d of %d in %d",
+					sent, seen, pages, tt.recipients/2, tt.recipients, tt.pages)
+			}
+		})
+	}
+}
+
+func TestSendStops(t *testing.T) {
+	listErr := errors.New("list failed")
+	failing := func(context.Context, int64, int) ([]int64, error) { return nil, listErr }
+	key := func(key int64) int64 { return key }
+	send := func(context.Context, int64) bool { return true }
+
+	if _, err := Send(context.Background(), failing, key, send); !errors.Is(err, listErr) {
+		t.Errorf("Send with failing list: err = %v, want %v", err, listErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	one := func(context.Context, int64, int) ([]int64, error) { return []int64{1, 2}, nil }
+	sent, err := Send(ctx, one, key, func(context.Context, int64) bool {
+		cancel()
+		return true
+	})
+	if !errors.Is(err, context.Canceled) || sent != 1 {
+		t.Errorf("Send canceled after the first = %d, %v; want 1, canceled", sent, err)
+	}
+}
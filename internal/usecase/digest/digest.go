@@ -0,0 +1,224 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/broadcast"
+)
+
+// TickInterval is how often due digests are looked for.
+const TickInterval = 5 * time.Minute
+
+// releaseTimeout bounds releasing the claim of a digest not sent.
+const releaseTimeout = 5 * time.Second
+
+type Usecase struct {
+	digestRepo   DigestRepo
+	settingsRepo SettingsRepo
+	streaks      Streaks
+	notifier     Notifier
+	logger       *zerolog.Logger
+}
+
+func NewUsecase(
+	digestRepo DigestRepo,
+	settingsRepo SettingsRepo,
+	streaks Streaks,
+	notifier Notifier,
+	parentLogger *zerolog.Logger,
+) *Usecase {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "digest_usecase").Logger()
+
+	return &Usecase{
+		digestRepo:   digestRepo,
+		settingsRepo: settingsRepo,
+		streaks:      streaks,
+		notifier:     notifier,
+		logger:       &logger,
+	}
+}
+
+// Tick sends the digests due at the moment; the scheduler runs it every
+// TickInterval.
+func (u *Usecase) Tick(ctx context.Context, now time.Time) error {
+	sent, err := u.SendDue(ctx, now)
+	if sent > 0 {
+		u.logger.Info().Int("sent", sent).Msg("digests sent")
+	}
+
+	return err
+}
+
+// SendDue sends the digests of the past week to the users for whom it's
+// domain.DigestWeekday and past the reminder time, and returns how many were
+// sent. A digest is claimed before it's sent, so it's never sent twice, even
+// if the bot stops in the middle. A digest that surely wasn't sent is
+// released and sent on a later tick; one that failed otherwise, and so might
+// have been sent, is not retried. Users who can't be messaged anymore get
+// their digest turned off.
+func (u *Usecase) SendDue(ctx context.Context, now time.Time) (int, error) {
+	const op = "SendDue"
+
+	sent, err := broadcast.Send(
+		ctx,
+		func(ctx context.Context, after int64, limit int) ([]domain.DigestCandidate, error) {
+			return u.digestRepo.ListDigestCandidates(ctx, now, after, limit)
+		},
+		func(candidate domain.DigestCandidate) int64 { return candidate.UserID },
+		func(ctx context.Context, candidate domain.DigestCandidate) bool {
+			return candidate.Settings.Allows(now) && u.deliver(ctx, candidate, now)
+		},
+	)
+	if err != nil {
+		return sent, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sent, nil
+}
+
+// deliver claims and sends the digest of one user. Failures are logged
+// rather than returned so that one user doesn't hold up the rest. An empty
+// digest is claimed but not sent.
+func (u *Usecase) deliver(ctx context.Context, candidate domain.DigestCandidate, now time.Time) bool {
+	logger := u.logger.With().
+		Int64("user_id", candidate.UserID).
+		Time("week", candidate.Week).
+		Logger()
+
+	loc := candidate.Settings.Location()
+	from := domain.DateStart(candidate.Week, loc)
+	to := domain.DateStart(candidate.Week.AddDate(0, 0, domain.DigestDays), loc)
+
+	digest, err := u.build(ctx, candidate.UserID, candidate.Week, from, to, now)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to build digest")
+
+		return false
+	}
+
+	claimed, err := u.digestRepo.ClaimDigest(ctx, candidate.UserID, candidate.Week)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to claim digest")
+
+		return false
+	}
+	if !claimed || digest.Empty() {
+		return false
+	}
+
+	err = u.notifier.SendDigest(ctx, candidate, digest)
+	if errors.Is(err, domain.ErrRecipientUnavailable) {
+		logger.Info().Err(err).Msg("user is unavailable, turning digest off")
+
+		if err = u.digestRepo.SetDigestEnabled(ctx, candidate.UserID, false); err != nil {
+			logger.Error().Err(err).Msg("failed to turn digest off")
+		}
+
+		return false
+	}
+	if errors.Is(err, domain.ErrMessageNotSent) {
+		logger.Warn().Err(err).Msg("digest not sent, releasing it for a later tick")
+
+		// A context past its deadline mustn't keep the claim.
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+		defer cancel()
+
+		if err = u.digestRepo.ReleaseDigest(releaseCtx, candidate.UserID, candidate.Week); err != nil {
+			logger.Error().Err(err).Msg("failed to release digest")
+		}
+
+		return false
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to send digest")
+
+		return false
+	}
+
+	if err = u.digestRepo.MarkDigestSent(ctx, candidate.UserID, candidate.Week, now); err != nil {
+		logger.Error().Err(err).Msg("failed to mark digest sent")
+	}
+
+	return true
+}
+
+// Preview returns the digest of the last domain.DigestDays days, today
+// included, and whether the weekly digest is on.
+func (u *Usecase) Preview(ctx context.Context, userID int64) (*domain.WeeklyDigest, bool, error) {
+	const op = "Preview"
+
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	loc := settings.Reminder.Location()
+	first := domain.LocalDate(now, loc).AddDate(0, 0, 1-domain.DigestDays)
+
+	digest, err := u.build(ctx, userID, first, domain.DateStart(first, loc), now, now)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return digest, settings.Digest, nil
+}
+
+func (u *Usecase) SetEnabled(ctx context.Context, userID int64, enabled bool) error {
+	const op = "SetEnabled"
+
+	if err := u.digestRepo.SetDigestEnabled(ctx, userID, enabled); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// build aggregates the progress in [from, to), which starts on the local
+// date first, and the words due in domain.DigestDays days after now.
+func (u *Usecase) build(
+	ctx context.Context,
+	userID int64,
+	first, from, to, now time.Time,
+) (*domain.WeeklyDigest, error) {
+	const op = "build"
+
+	digest := &domain.WeeklyDigest{
+		From: first,
+		To:   first.AddDate(0, 0, domain.DigestDays-1),
+	}
+
+	var err error
+	digest.WordsLearned, digest.Retention, err = u.digestRepo.CountProgress(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	digest.Hardest, err = u.digestRepo.ListHardestWords(ctx, userID, from, to, domain.DigestHardestWords)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	digest.DueAhead, err = u.digestRepo.CountDueUntil(ctx, userID, now.AddDate(0, 0, domain.DigestDays))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	streak, err := u.streaks.Streak(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	digest.Streak = streak.Current
+
+	return digest, nil
+}
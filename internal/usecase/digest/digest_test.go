@@ -0,0 +1,226 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+const testUserID = 1
+
+// fakeDigestRepo keeps the claims of the digests of one week by user.
+type fakeDigestRepo struct {
+	candidates []domain.DigestCandidate
+	reviews    int
+	claimed    map[int64]bool
+	sent       map[int64]bool
+	released   int
+	disabled   map[int64]bool
+}
+
+func newFakeDigestRepo(userIDs ...int64) *fakeDigestRepo {
+	repo := &fakeDigestRepo{
+		reviews:  3,
+		claimed:  make(map[int64]bool),
+		sent:     make(map[int64]bool),
+		disabled: make(map[int64]bool),
+	}
+	for _, userID := range userIDs {
+		repo.candidates = append(repo.candidates, domain.DigestCandidate{
+			UserID:   userID,
+			Settings: domain.ReminderSettings{Enabled: true, Timezone: "UTC"},
+			Week:     time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC),
+		})
+	}
+
+	return repo
+}
+
+func (r *fakeDigestRepo) ListDigestCandidates(
+	_ context.Context,
+	_ time.Time,
+	after int64,
+	limit int,
+) ([]domain.DigestCandidate, error) {
+	var page []domain.DigestCandidate
+	for _, c := range r.candidates {
+		if c.UserID > after && !r.sent[c.UserID] && !r.disabled[c.UserID] && len(page) < limit {
+			page = append(page, c)
+		}
+	}
+
+	return page, nil
+}
+
+func (r *fakeDigestRepo) ClaimDigest(_ context.Context, userID int64, _ time.Time) (bool, error) {
+	if r.claimed[userID] {
+		return false, nil
+	}
+	r.claimed[userID] = true
+
+	return true, nil
+}
+
+func (r *fakeDigestRepo) ReleaseDigest(ctx context.Context, userID int64, _ time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !r.sent[userID] {
+		delete(r.claimed, userID)
+	}
+	r.released++
+
+	return nil
+}
+
+func (r *fakeDigestRepo) MarkDigestSent(_ context.Context, userID int64, _, _ time.Time) error {
+	r.sent[userID] = true
+	return nil
+}
+
+func (r *fakeDigestRepo) SetDigestEnabled(_ context.Context, userID int64, enabled bool) error {
+	r.disabled[userID] = !enabled
+	return nil
+}
+
+func (r *fakeDigestRepo) CountProgress(context.Context, int64, time.Time, time.Time) (int, domain.Retention, error) {
+	return 0, domain.Retention{Reviews: r.reviews, Recalled: r.reviews}, nil
+}
+
+func (r *fakeDigestRepo) ListHardestWords(context.Context, int64, time.Time, time.Time, int) ([]domain.HardWord, error) {
+	return nil, nil
+}
+
+func (r *fakeDigestRepo) CountDueUntil(context.Context, int64, time.Time) (int, error) {
+	return 0, nil
+}
+
+type fakeStreaks struct{}
+
+func (fakeStreaks) Streak(context.Context, int64) (*domain.StreakStats, error) {
+	return &domain.StreakStats{Current: 2}, nil
+}
+
+// fakeNotifier returns err from every send and counts the sends.
+type fakeNotifier struct {
+	err   error
+	calls int
+}
+
+func (n *fakeNotifier) SendDigest(context.Context, domain.DigestCandidate, *domain.WeeklyDigest) error {
+	n.calls++
+	return n.err
+}
+
+func newTestUsecase(repo *fakeDigestRepo, notifier *fakeNotifier) *Usecase {
+	logger := zerolog.Nop()
+	return NewUsecase(repo, nil, fakeStreaks{}, notifier, &logger)
+}
+
+func TestSendDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		sendErr      error
+		reviews      int
+		wantSent     int
+		wantCalls    int
+		wantClaimed  bool
+		wantReleased int
+		wantDisabled bool
+	}{
+		{"sent", nil, 3, 1, 1, true, 0, false},
+		{"empty digest", nil, 0, 0, 0, true, 0, false},
+		{"surely not sent", fmt.Errorf("pacer: %w", domain.ErrMessageNotSent), 3, 0, 1, false, 1, false},
+		{"maybe sent", errors.New("connection reset"), 3, 0, 1, true, 0, false},
+		{"user unavailable", domain.ErrRecipientUnavailable, 3, 0, 1, true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDigestRepo(testUserID)
+			repo.reviews = tt.reviews
+			notifier := &fakeNotifier{err: tt.sendErr}
+
+			sent, err := newTestUsecase(repo, notifier).SendDue(context.Background(), now)
+			if err != nil {
+				t.Fatalf("SendDue: %v", err)
+			}
+			if sent != tt.wantSent {
+				t.Errorf("sent = %d, want %d", sent, tt.wantSent)
+			}
+			if notifier.calls != tt.wantCalls {
+				t.Errorf("send calls = %d, want %d", notifier.calls, tt.wantCalls)
+			}
+			if repo.claimed[testUserID] != tt.wantClaimed {
+				t.Errorf("claimed = %v, want %v", repo.claimed[testUserID], tt.wantClaimed)
+			}
+			if repo.released != tt.wantReleased {
+				t.Errorf("released = %d, want %d", repo.released, tt.wantReleased)
+			}
+			if repo.disabled[testUserID] != tt.wantDisabled {
+				t.Errorf("disabled = %v, want %v", repo.disabled[testUserID], tt.wantDisabled)
+			}
+		})
+	}
+}
+
+func TestSendDueRetriesReleased(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	repo := newFakeDigestRepo(testUserID)
+	notifier := &fakeNotifier{err: domain.ErrMessageNotSent}
+	uc := newTestUsecase(repo, notifier)
+
+	if sent, err := uc.SendDue(context.Background(), now); err != nil || sent != 0 {
+		t.Fatalf("SendDue = %d, %v; want 0, nil", sent, err)
+	}
+
+	notifier.err = nil
+	if sent, err := uc.SendDue(context.Background(), now); err != nil || sent != 1 {
+		t.Fatalf("SendDue after the failure = %d, %v; want 1, nil", sent, err)
+	}
+
+	if sent, err := uc.SendDue(context.Background(), now); err != nil || sent != 0 {
+		t.Errorf("SendDue after the digest is sent = %d, %v; want 0, nil", sent, err)
+	}
+	if notifier.calls != 2 {
+		t.Errorf("send calls = %d, want 2", notifier.calls)
+	}
+}
+
+// cancelingNotifier cancels the context of the send, as a shutdown in the
+// middle of it does, and fails the send.
+type cancelingNotifier struct {
+	cancel context.CancelFunc
+}
+
+func (n *cancelingNotifier) SendDigest(context.Context, domain.DigestCandidate, *domain.WeeklyDigest) error {
+	n.cancel()
+	return domain.ErrMessageNotSent
+}
+
+func TestDeliverReleasesAfterCancel(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	repo := newFakeDigestRepo(testUserID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := zerolog.Nop()
+	uc := NewUsecase(repo, nil, fakeStreaks{}, &cancelingNotifier{cancel: cancel}, &logger)
+
+	if uc.deliver(ctx, repo.candidates[0], now) {
+		t.Fatal("deliver = true, want false")
+	}
+	if repo.released != 1 || repo.claimed[testUserID] {
+		t.Errorf("released = %d, claimed = %v; want the claim released although the context is canceled",
+			repo.released, repo.claimed[testUserID])
+	}
+}
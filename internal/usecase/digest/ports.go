@@ -0,0 +1,36 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type DigestRepo interface {
+	ListDigestCandidates(ctx context.Context, now time.Time, afterUserID int64, limit int) ([]domain.DigestCandidate, error)
+	ClaimDigest(ctx context.Context, userID int64, week time.Time) (bool, error)
+	ReleaseDigest(ctx context.Context, userID int64, week time.Time) error
+	MarkDigestSent(ctx context.Context, userID int64, week, at time.Time) error
+	SetDigestEnabled(ctx context.Context, userID int64, enabled bool) error
+	CountProgress(ctx context.Context, userID int64, from, to time.Time) (int, domain.Retention, error)
+	ListHardestWords(ctx context.Context, userID int64, from, to time.Time, limit int) ([]domain.HardWord, error)
+	CountDueUntil(ctx context.Context, userID int64, until time.Time) (int, error)
+}
+
+type SettingsRepo interface {
+	GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+}
+
+// Streaks tells the current streak of a user.
+type Streaks interface {
+	Streak(ctx context.Context, userID int64) (*domain.StreakStats, error)
+}
+
+// Notifier delivers digests to users. It returns
+// domain.ErrRecipientUnavailable if the user can't be messaged anymore, e.g.
+// has blocked the bot, and domain.ErrMessageNotSent if the digest surely
+// wasn't sent and may be sent again.
+type Notifier interface {
+	SendDigest(ctx context.Context, candidate domain.DigestCandidate, digest *domain.WeeklyDigest) error
+}
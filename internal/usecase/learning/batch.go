@@ -0,0 +1,276 @@
+package learning
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// batchRetryGap is how many cards later a word answered wrong comes back, so
+// the answer isn't still in short-term memory.
+const batchRetryGap = 3
+
+// learnBatch is kept in the SessionStore between updates, so its fields are
+// exported to be encoded.
+type learnBatch struct {
+	ID           string
+	DictionaryID string
+	Words        []domain.LearningWord
+
+	// Seq and Token identify the shown card; Token is empty until the quiz is
+	// started.
+	Seq     int
+	Token   string
+	Queue   []*batchWord
+	Current *batchWord
+	Quiz    *domain.Quiz
+	ShownAt time.Time
+	Learned []domain.LearnedWord
+	// Candidates are the words of the dictionary quiz distractors are picked
+	// from, loaded once by ensureCandidates.
+	Candidates []domain.DictionaryWordPreview
+}
+
+type batchWord struct {
+	Word     *domain.ReviewWord
+	Mistakes int
+}
+
+// StartBatch picks up to domain.LearnBatchSize new words of the active
+// dictionary, but no more than the daily limit leaves, to be shown at once.
+// They are not tracked until the batch quiz is passed, see AnswerBatchQuiz.
+func (u *Usecase) StartBatch(ctx context.Context, userID int64) (*domain.LearnBatch, error) {
+	const op = "StartBatch"
+
+	dictionaryID, err := u.userRepo.GetActiveDictionaryID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if dictionaryID == "" {
+		return nil, domain.ErrLearningNotStarted
+	}
+
+	left, err := u.newWordsLeft(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if left <= 0 {
+		return nil, u.finishBatch(ctx, userID, domain.ErrDailyNewWordsLimit)
+	}
+
+	words, err := u.dictRepo.PickUntrackedWords(ctx, userID, dictionaryID, min(domain.LearnBatchSize, left))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(words) == 0 {
+		return nil, u.finishBatch(ctx, userID, domain.ErrNoWordsForLearning)
+	}
+
+	batch := &learnBatch{
+		ID:           strconv.FormatUint(uint64(rand.Uint32()), 36),
+		DictionaryID: dictionaryID,
+		Words:        words,
+	}
+	if err = u.setBatch(ctx, userID, batch); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.logger.Debug().
+		Int64("user_id", userID).
+		Str("dictionary_id", dictionaryID).
+		Int("words", len(words)).
+		Msgf("%s succeeded", op)
+
+	return &domain.LearnBatch{ID: batch.ID, Words: words}, nil
+}
+
+// StartBatchQuiz quizzes the batch shown by StartBatch in random order.
+// ErrStaleLearnCard is returned when batchID is not the current batch or its
+// quiz is already started.
+func (u *Usecase) StartBatchQuiz(ctx context.Context, userID int64, batchID string) (*domain.LearnBatchCard, error) {
+	const op = "StartBatchQuiz"
+
+	batch, ok, err := u.getBatch(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok || batch.ID != batchID || batch.Token != "" {
+		return nil, domain.ErrStaleLearnCard
+	}
+
+	batch.Queue = make([]*batchWord, 0, len(batch.Words))
+	for _, w := range batch.Words {
+		batch.Queue = append(batch.Queue, &batchWord{Word: &domain.ReviewWord{
+			ID:            w.ID,
+			DictionaryID:  w.DictionaryID,
+			Spelling:      w.Spelling,
+			Transcription: w.Transcription,
+			Audio:         w.Audio,
+			RUTranslation: w.RUTranslation,
+			Examples:      w.Examples,
+			Direction:     domain.CardDirectionForward,
+		}})
+	}
+	rand.Shuffle(len(batch.Queue), func(i, j int) {
+		batch.Queue[i], batch.Queue[j] = batch.Queue[j], batch.Queue[i]
+	})
+
+	if err = u.ensureCandidates(ctx, batch); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	card := nextBatchCard(batch)
+	if err = u.setBatch(ctx, userID, batch); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return card, nil
+}
+
+// AnswerBatchQuiz grades the choice of an option of the current batch card.
+// A word answered wrong comes back later in the quiz. Once every word has
+// been recalled, the words start being learned with short-term first
+// reviews (see domain.LearnedReviewAt) and ErrLearnBatchFinished is returned
+// along with the result.
+func (u *Usecase) AnswerBatchQuiz(
+	ctx context.Context,
+	userID int64,
+	token string,
+	option int,
+) (*domain.QuizAnswerResult, *domain.LearnBatchCard, error) {
+	const op = "AnswerBatchQuiz"
+
+	batch, ok, err := u.getBatch(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok || batch.Current == nil || batch.Token != token ||
+		option < 0 || option >= len(batch.Quiz.Options) {
+		return nil, nil, domain.ErrStaleLearnCard
+	}
+
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	current := batch.Current
+	result := domain.GradeQuizAnswer(batch.Quiz, option, now.Sub(batch.ShownAt), settings.Scheduler)
+	result.Word = current.Word
+
+	if result.Correct {
+		batch.Learned = append(batch.Learned, domain.LearnedWord{
+			DictWordID: current.Word.ID,
+			Mistakes:   current.Mistakes,
+		})
+	} else {
+		current.Mistakes++
+		at := min(len(batch.Queue), batchRetryGap)
+		batch.Queue = append(batch.Queue[:at], append([]*batchWord{current}, batch.Queue[at:]...)...)
+	}
+
+	if err = u.ensureCandidates(ctx, batch); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if card := nextBatchCard(batch); card != nil {
+		if err = u.setBatch(ctx, userID, batch); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return result, card, nil
+	}
+
+	// The batch is cleared with the words learned, so that a failure can't
+	// leave a learned batch to be answered again.
+	err = u.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := u.wordStateRepo.AddLearnedWords(ctx, userID, batch.Learned, now); err != nil {
+			return err
+		}
+		return u.clearBatch(ctx, userID)
+	})
+	if err != nil {
+		return result, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.logger.Debug().
+		Int64("user_id", userID).
+		Str("dictionary_id", batch.DictionaryID).
+		Int("words", len(batch.Learned)).
+		Msgf("%s: batch learned", op)
+
+	return result, nil, domain.ErrLearnBatchFinished
+}
+
+// nextBatchCard moves the batch to the next card; nil is returned when every
+// word has been recalled. The candidates must be loaded, see
+// ensureCandidates.
+func nextBatchCard(batch *learnBatch) *domain.LearnBatchCard {
+	batch.Seq++
+	batch.Token = batch.ID + "." + strconv.Itoa(batch.Seq)
+	batch.Quiz = nil
+	if len(batch.Queue) == 0 {
+		batch.Current = nil
+		return nil
+	}
+
+	batch.Current = batch.Queue[0]
+	batch.Queue = batch.Queue[1:]
+	batch.Quiz = domain.BuildQuiz(batch.Current.Word, batch.Candidates)
+	batch.ShownAt = time.Now()
+
+	return &domain.LearnBatchCard{
+		Word:  batch.Current.Word,
+		Quiz:  batch.Quiz,
+		Token: batch.Token,
+		Left:  len(batch.Queue) + 1,
+	}
+}
+
+// ensureCandidates loads a sample of the batch dictionary to pick quiz
+// distractors from, unless the batch has one; see domain.QuizCandidatesLimit.
+func (u *Usecase) ensureCandidates(ctx context.Context, batch *learnBatch) error {
+	if batch.Candidates != nil {
+		return nil
+	}
+
+	candidates, err := u.dictRepo.SampleWordPreviews(ctx, batch.DictionaryID, domain.QuizCandidatesLimit)
+	if err != nil {
+		return err
+	}
+	batch.Candidates = candidates
+
+	return nil
+}
+
+func (u *Usecase) setBatch(ctx context.Context, userID int64, batch *learnBatch) error {
+	return u.sessions.SaveSession(ctx, userID, domain.SessionLearnBatch, batch)
+}
+
+// getBatch loads the batch of the user; false is returned if there is none
+// or it has expired.
+func (u *Usecase) getBatch(ctx context.Context, userID int64) (*learnBatch, bool, error) {
+	batch := &learnBatch{}
+	ok, err := u.sessions.LoadSession(ctx, userID, domain.SessionLearnBatch, batch)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return batch, true, nil
+}
+
+func (u *Usecase) clearBatch(ctx context.Context, userID int64) error {
+	return u.sessions.DeleteSession(ctx, userID, domain.SessionLearnBatch)
+}
+
+// finishBatch clears the batch when no new one can be started and returns
+// the reason, or the error of clearing.
+func (u *Usecase) finishBatch(ctx context.Context, userID int64, reason error) error {
+	if err := u.clearBatch(ctx, userID); err != nil {
+		return err
+	}
+
+	return reason
+}
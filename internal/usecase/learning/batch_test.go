@@ -0,0 +1,46 @@
+package learning
+
+import (
+	"testing"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+func TestNextBatchCard(t *testing.T) {
+	batch := &learnBatch{
+		ID: "b",
+		Queue: []*batchWord{
+			{Word: &domain.ReviewWord{ID: "1", Spelling: "cat", RUTranslation: "кот"}},
+			{Word: &domain.ReviewWord{ID: "2", Spelling: "dog", RUTranslation: "собака"}},
+		},
+		Candidates: []domain.DictionaryWordPreview{},
+	}
+
+	tokens := make(map[string]struct{})
+	for left := 2; left > 0; left-- {
+		card := nextBatchCard(batch)
+		if card == nil {
+			t.Fatalf("nextBatchCard = nil with %d words left", left)
+		}
+		if card.Left != left {
+			t.Errorf("card left = %d, want %d", card.Left, left)
+		}
+		if card.Quiz == nil || card.Quiz != batch.Quiz || card.Word != batch.Current.Word {
+			t.Error("card isn't the current one of the batch")
+		}
+		if _, ok := tokens[card.Token]; ok {
+			t.Errorf("token %q is issued twice", card.Token)
+		}
+		tokens[card.Token] = struct{}{}
+	}
+
+	if card := nextBatchCard(batch); card != nil {
+		t.Errorf("nextBatchCard of an empty queue = %+v, want nil", card)
+	}
+	if batch.Current != nil || batch.Quiz != nil {
+		t.Error("batch keeps the last card after the queue is over")
+	}
+	if _, ok := tokens[batch.Token]; ok {
+		t.Error("token of the last card is still valid after the queue is over")
+	}
+}
@@ -3,7 +3,7 @@ package learning
 import (
 	"context"
 	"fmt"
-	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -15,15 +15,17 @@ type Usecase struct {
 	dictRepo      DictionaryRepo
 	subsRepo      SubscriptionsRepo
 	wordStateRepo WordStateRepo
+	settingsRepo  SettingsRepo
+	sessions      SessionStore
+	txManager     TxManager
 	logger        *zerolog.Logger
-
-	pendingMu   sync.RWMutex
-	pendingWord map[int64]pendingWord
 }
 
+// pendingWord is the word shown to be added or not, kept in the SessionStore
+// between updates.
 type pendingWord struct {
-	dictionaryID string
-	wordID       string
+	DictionaryID string
+	WordID       string
 }
 
 func NewUsecase(
@@ -31,6 +33,9 @@ func NewUsecase(
 	dictRepo DictionaryRepo,
 	subsRepo SubscriptionsRepo,
 	wordStateRepo WordStateRepo,
+	settingsRepo SettingsRepo,
+	sessions SessionStore,
+	txManager TxManager,
 	parentLogger *zerolog.Logger,
 ) *Usecase {
 	if parentLogger == nil {
@@ -44,8 +49,10 @@ func NewUsecase(
 		dictRepo:      dictRepo,
 		subsRepo:      subsRepo,
 		wordStateRepo: wordStateRepo,
+		settingsRepo:  settingsRepo,
+		sessions:      sessions,
+		txManager:     txManager,
 		logger:        &logger,
-		pendingWord:   make(map[int64]pendingWord),
 	}
 }
 
@@ -128,25 +135,49 @@ func (u *Usecase) startLearning(
 ) (*domain.LearningWord, error) {
 	const op = "startLearning"
 
-	word, err := u.dictRepo.PickRandomUntrackedWord(ctx, userID, dictionaryID)
+	// The word is picked, shown and the dictionary marked as started
+	// together; when there is no word to show, the pending one is cleared and
+	// reason is returned.
+	var (
+		word   *domain.LearningWord
+		reason error
+	)
+	err := u.txManager.Do(ctx, func(ctx context.Context) error {
+		left, err := u.newWordsLeft(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if left <= 0 {
+			reason = domain.ErrDailyNewWordsLimit
+			return u.clearPending(ctx, userID)
+		}
+
+		word, err = u.dictRepo.PickUntrackedWord(ctx, userID, dictionaryID)
+		if err != nil {
+			return err
+		}
+		if word == nil {
+			reason = domain.ErrNoWordsForLearning
+			return u.clearPending(ctx, userID)
+		}
+
+		if err = u.setPending(ctx, userID, pendingWord{
+			DictionaryID: dictionaryID,
+			WordID:       word.ID,
+		}); err != nil {
+			return err
+		}
+
+		if err = u.subsRepo.MarkLearningStarted(ctx, userID, dictionaryID); err != nil {
+			return err
+		}
+		return u.userRepo.SetActiveDictionaryID(ctx, userID, dictionaryID)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	if word == nil {
-		u.clearPending(userID)
-		return nil, domain.ErrNoWordsForLearning
-	}
-
-	u.setPending(userID, pendingWord{
-		dictionaryID: dictionaryID,
-		wordID:       word.ID,
-	})
-
-	if err = u.subsRepo.MarkLearningStarted(ctx, userID, dictionaryID); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
-	if err = u.userRepo.SetActiveDictionaryID(ctx, userID, dictionaryID); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+	if reason != nil {
+		return nil, reason
 	}
 
 	return word, nil
@@ -178,7 +209,12 @@ func (u *Usecase) ActiveDictionaryID(ctx context.Context, userID int64) (string,
 func (u *Usecase) Back(ctx context.Context, userID int64) error {
 	const op = "Back"
 
-	u.clearPending(userID)
+	if err := u.clearPending(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := u.clearBatch(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 	if err := u.userRepo.ClearActiveDictionaryID(ctx, userID); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -196,34 +232,60 @@ func (u *Usecase) applyDecision(
 	status domain.UserWordStatus,
 	op string,
 ) (*domain.LearningWord, error) {
-	current, ok := u.getPending(userID)
+	current, ok, err := u.getPending(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 	if !ok {
 		return nil, domain.ErrLearningNotStarted
 	}
 
-	// TODO (high priority): set default ef, last_reviewed_at, etc - check how
-	// it was implemented in 1st version of bot
-	if err := u.wordStateRepo.UpsertStatus(ctx, userID, current.wordID, status); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
-
-	nextWord, err := u.dictRepo.PickRandomUntrackedWord(ctx, userID, current.dictionaryID)
+	// The decision is saved along with moving to the next word, so that a
+	// failure doesn't leave the decided word shown.
+	var (
+		nextWord *domain.LearningWord
+		reason   error
+	)
+	err = u.txManager.Do(ctx, func(ctx context.Context) error {
+		// TODO (high priority): set default ef, last_reviewed_at, etc - check how
+		// it was implemented in 1st version of bot
+		if err := u.wordStateRepo.UpsertStatus(ctx, userID, current.WordID, status); err != nil {
+			return err
+		}
+
+		left, err := u.newWordsLeft(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if left <= 0 {
+			reason = domain.ErrDailyNewWordsLimit
+			return u.clearPending(ctx, userID)
+		}
+
+		nextWord, err = u.dictRepo.PickUntrackedWord(ctx, userID, current.DictionaryID)
+		if err != nil {
+			return err
+		}
+		if nextWord == nil {
+			reason = domain.ErrNoWordsForLearning
+			return u.clearPending(ctx, userID)
+		}
+
+		return u.setPending(ctx, userID, pendingWord{
+			DictionaryID: current.DictionaryID,
+			WordID:       nextWord.ID,
+		})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	if nextWord == nil {
-		u.clearPending(userID)
-		return nil, domain.ErrNoWordsForLearning
+	if reason != nil {
+		return nil, reason
 	}
 
-	u.setPending(userID, pendingWord{
-		dictionaryID: current.dictionaryID,
-		wordID:       nextWord.ID,
-	})
-
 	u.logger.Debug().
 		Int64("user_id", userID).
-		Str("dictionary_id", current.dictionaryID).
+		Str("dictionary_id", current.DictionaryID).
 		Str("word_id", nextWord.ID).
 		Str("decision", string(status)).
 		Msgf("%s succeeded", op)
@@ -231,22 +293,35 @@ func (u *Usecase) applyDecision(
 	return nextWord, nil
 }
 
-func (u *Usecase) setPending(userID int64, p pendingWord) {
-	u.pendingMu.Lock()
-	defer u.pendingMu.Unlock()
-	u.pendingWord[userID] = p
+// newWordsLeft returns how many more words the user may add today under
+// their daily limit of new words.
+func (u *Usecase) newWordsLeft(ctx context.Context, userID int64) (int, error) {
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	added, err := u.wordStateRepo.CountWordsAddedSince(ctx, userID, settings.DayStart(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+
+	return settings.NewWordsPerDay - added, nil
+}
+
+func (u *Usecase) setPending(ctx context.Context, userID int64, p pendingWord) error {
+	return u.sessions.SaveSession(ctx, userID, domain.SessionLearning, p)
 }
 
-func (u *Usecase) getPending(userID int64) (pendingWord, bool) {
-	u.pendingMu.RLock()
-	defer u.pendingMu.RUnlock()
-	p, ok := u.pendingWord[userID]
+// getPending loads the pending word of the user; false is returned if there
+// is none or it has expired.
+func (u *Usecase) getPending(ctx context.Context, userID int64) (pendingWord, bool, error) {
+	var p pendingWord
+	ok, err := u.sessions.LoadSession(ctx, userID, domain.SessionLearning, &p)
 
-	return p, ok
+	return p, ok, err
 }
 
-func (u *Usecase) clearPending(userID int64) {
-	u.pendingMu.Lock()
-	defer u.pendingMu.Unlock()
-	delete(u.pendingWord, userID)
+func (u *Usecase) clearPending(ctx context.Context, userID int64) error {
+	return u.sessions.DeleteSession(ctx, userID, domain.SessionLearning)
 }
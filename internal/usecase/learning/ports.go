@@ -2,6 +2,7 @@ package learning
 
 import (
 	"context"
+	"time"
 
 	"github.com/krezefal/eng-tg-bot/internal/domain"
 )
@@ -14,7 +15,9 @@ type UserRepo interface {
 
 type DictionaryRepo interface {
 	ExistsByID(ctx context.Context, dictionaryID string) (bool, error)
-	PickRandomUntrackedWord(ctx context.Context, userID int64, dictionaryID string) (*domain.LearningWord, error)
+	PickUntrackedWord(ctx context.Context, userID int64, dictionaryID string) (*domain.LearningWord, error)
+	PickUntrackedWords(ctx context.Context, userID int64, dictionaryID string, limit int) ([]domain.LearningWord, error)
+	SampleWordPreviews(ctx context.Context, dictionaryID string, limit int) ([]domain.DictionaryWordPreview, error)
 }
 
 type SubscriptionsRepo interface {
@@ -25,4 +28,24 @@ type SubscriptionsRepo interface {
 
 type WordStateRepo interface {
 	UpsertStatus(ctx context.Context, userID int64, dictWordID string, status domain.UserWordStatus) error
+	AddLearnedWords(ctx context.Context, userID int64, words []domain.LearnedWord, learnedAt time.Time) error
+	CountWordsAddedSince(ctx context.Context, userID int64, since time.Time) (int, error)
+}
+
+type SettingsRepo interface {
+	GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+}
+
+// SessionStore keeps the sessions of users between updates. LoadSession
+// reports false for a missing or expired session.
+type SessionStore interface {
+	LoadSession(ctx context.Context, userID int64, kind domain.SessionKind, dst any) (bool, error)
+	SaveSession(ctx context.Context, userID int64, kind domain.SessionKind, session any) error
+	DeleteSession(ctx context.Context, userID int64, kind domain.SessionKind) error
+}
+
+// TxManager runs fn atomically: the repo calls made with the ctx passed to fn
+// are committed together or not at all.
+type TxManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/broadcast"
+)
+
+// TickInterval is how often unlocked batches and due notifications are
+// looked for.
+const TickInterval = time.Minute
+
+// unlockLookback is how far back unlocked batches are looked for, so that the
+// ones unlocked while the bot was down are told about too.
+const unlockLookback = 24 * time.Hour
+
+type Usecase struct {
+	notificationRepo NotificationRepo
+	notifier         Notifier
+	logger           *zerolog.Logger
+}
+
+func NewUsecase(notificationRepo NotificationRepo, notifier Notifier, parentLogger *zerolog.Logger) *Usecase {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "notification_usecase").Logger()
+
+	return &Usecase{
+		notificationRepo: notificationRepo,
+		notifier:         notifier,
+		logger:           &logger,
+	}
+}
+
+// Tick enqueues the notifications about the batches unlocked lately and
+// sends the due ones; the scheduler runs it every TickInterval.
+func (u *Usecase) Tick(ctx context.Context, now time.Time) error {
+	const op = "Tick"
+
+	enqueued, err := u.notificationRepo.EnqueueUnlockedBatches(ctx, now.Add(-unlockLookback), now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if enqueued > 0 {
+		u.logger.Info().Int("enqueued", enqueued).Msg("unlocked batches enqueued")
+	}
+
+	if _, err = u.notificationRepo.DropDisabledNotifications(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	sent, err := u.SendDue(ctx, now)
+	if sent > 0 {
+		u.logger.Info().Int("sent", sent).Msg("notifications sent")
+	}
+
+	return err
+}
+
+// SendDue sends the notifications due at the moment, except to users in
+// their quiet hours, and returns how many were sent. A failed notification
+// is retried later with a growing delay, up to
+// domain.NotificationMaxAttempts times. Users who can't be messaged anymore
+// get their notifications turned off.
+func (u *Usecase) SendDue(ctx context.Context, now time.Time) (int, error) {
+	const op = "SendDue"
+
+	sent, err := broadcast.Send(
+		ctx,
+		func(ctx context.Context, after int64, limit int) ([]domain.Notification, error) {
+			return u.notificationRepo.ListDueNotifications(ctx, now, after, limit)
+		},
+		func(notification domain.Notification) int64 { return notification.ID },
+		func(ctx context.Context, notification domain.Notification) bool {
+			return !notification.Settings.Quiet(now) && u.send(ctx, notification, now)
+		},
+	)
+	if err != nil {
+		return sent, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sent, nil
+}
+
+// send sends one notification. Failures are logged rather than returned so
+// that one user doesn't hold up the rest. A notification sent but not marked
+// so is sent again, so a user may rarely get one twice.
+func (u *Usecase) send(ctx context.Context, notification domain.Notification, now time.Time) bool {
+	logger := u.logger.With().
+		Int64("notification_id", notification.ID).
+		Int64("user_id", notification.UserID).
+		Logger()
+
+	err := u.notifier.SendNotification(ctx, notification)
+	if errors.Is(err, domain.ErrRecipientUnavailable) {
+		logger.Info().Err(err).Msg("user is unavailable, turning notifications off")
+
+		if err = u.notificationRepo.SetNotificationsEnabled(ctx, notification.UserID, false); err != nil {
+			logger.Error().Err(err).Msg("failed to turn notifications off")
+		}
+
+		return false
+	}
+	if err != nil {
+		logger.Error().Err(err).Int("attempts", notification.Attempts+1).Msg("failed to send notification")
+
+		next := now.Add(domain.NotificationRetryDelay(notification.Attempts + 1))
+		if err = u.notificationRepo.MarkNotificationFailed(ctx, notification.ID, next, err.Error()); err != nil {
+			logger.Error().Err(err).Msg("failed to mark notification failed")
+		}
+
+		return false
+	}
+
+	if err = u.notificationRepo.MarkNotificationSent(ctx, notification.ID, now); err != nil {
+		logger.Error().Err(err).Msg("failed to mark notification sent")
+	}
+
+	return true
+}
@@ -0,0 +1,135 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// fakeNotificationRepo lists the notifications not sent yet and records how
+// they end up.
+type fakeNotificationRepo struct {
+	notifications []domain.Notification
+	sent          map[int64]time.Time
+	failed        map[int64]time.Time
+	disabled      map[int64]bool
+}
+
+func newFakeNotificationRepo(notifications ...domain.Notification) *fakeNotificationRepo {
+	return &fakeNotificationRepo{
+		notifications: notifications,
+		sent:          make(map[int64]time.Time),
+		failed:        make(map[int64]time.Time),
+		disabled:      make(map[int64]bool),
+	}
+}
+
+func (r *fakeNotificationRepo) EnqueueUnlockedBatches(context.Context, time.Time, time.Time) (int, error) {
+	return 0, nil
+}
+
+func (r *fakeNotificationRepo) ListDueNotifications(
+	_ context.Context,
+	_ time.Time,
+	afterID int64,
+	limit int,
+) ([]domain.Notification, error) {
+	var page []domain.Notification
+	for _, n := range r.notifications {
+		if _, ok := r.sent[n.ID]; !ok && n.ID > afterID && len(page) < limit {
+			page = append(page, n)
+		}
+	}
+
+	return page, nil
+}
+
+func (r *fakeNotificationRepo) MarkNotificationSent(_ context.Context, id int64, at time.Time) error {
+	r.sent[id] = at
+	return nil
+}
+
+func (r *fakeNotificationRepo) MarkNotificationFailed(_ context.Context, id int64, nextAttemptAt time.Time, _ string) error {
+	r.failed[id] = nextAttemptAt
+	return nil
+}
+
+func (r *fakeNotificationRepo) DropDisabledNotifications(context.Context) (int, error) {
+	return 0, nil
+}
+
+func (r *fakeNotificationRepo) SetNotificationsEnabled(_ context.Context, userID int64, enabled bool) error {
+	r.disabled[userID] = !enabled
+	return nil
+}
+
+// fakeNotifier returns the error set for the user from a send.
+type fakeNotifier struct {
+	errs  map[int64]error
+	calls int
+}
+
+func (n *fakeNotifier) SendNotification(_ context.Context, notification domain.Notification) error {
+	n.calls++
+	return n.errs[notification.UserID]
+}
+
+func TestSendDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	quietStart, quietEnd := 11*60, 13*60
+
+	const (
+		okUser = iota + 1
+		failingUser
+		goneUser
+		quietUser
+	)
+	settings := domain.ReminderSettings{Timezone: "UTC"}
+	quiet := domain.ReminderSettings{Timezone: "UTC", QuietStart: &quietStart, QuietEnd: &quietEnd}
+	repo := newFakeNotificationRepo(
+		domain.Notification{ID: 1, UserID: okUser, Settings: settings},
+		domain.Notification{ID: 2, UserID: failingUser, Settings: settings, Attempts: 2},
+		domain.Notification{ID: 3, UserID: goneUser, Settings: settings},
+		domain.Notification{ID: 4, UserID: quietUser, Settings: quiet},
+	)
+	notifier := &fakeNotifier{errs: map[int64]error{
+		failingUser: errors.New("telegram: Internal Server Error (500)"),
+		goneUser:    domain.ErrRecipientUnavailable,
+	}}
+	logger := zerolog.Nop()
+
+	sent, err := NewUsecase(repo, notifier, &logger).SendDue(context.Background(), now)
+	if err != nil {
+		t.Fatalf("SendDue: %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("sent = %d, want 1", sent)
+	}
+	if at, ok := repo.sent[1]; !ok || !at.Equal(now) {
+		t.Errorf("notification 1 sent at %v, %v; want %v", at, ok, now)
+	}
+
+	wantNext := now.Add(domain.NotificationRetryDelay(3))
+	if next, ok := repo.failed[2]; !ok || !next.Equal(wantNext) {
+		t.Errorf("notification 2 retried at %v, %v; want %v", next, ok, wantNext)
+	}
+
+	if !repo.disabled[goneUser] {
+		t.Error("notifications of an unavailable user are left on")
+	}
+	if _, ok := repo.failed[3]; ok {
+		t.Error("notification to an unavailable user is retried")
+	}
+
+	if notifier.calls != 3 {
+		t.Errorf("send calls = %d, want 3, none in the quiet hours", notifier.calls)
+	}
+	if _, ok := repo.sent[4]; ok {
+		t.Error("notification is sent in the quiet hours")
+	}
+}
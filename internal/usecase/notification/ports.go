@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type NotificationRepo interface {
+	EnqueueUnlockedBatches(ctx context.Context, since, now time.Time) (int, error)
+	ListDueNotifications(ctx context.Context, now time.Time, afterID int64, limit int) ([]domain.Notification, error)
+	MarkNotificationSent(ctx context.Context, id int64, at time.Time) error
+	MarkNotificationFailed(ctx context.Context, id int64, nextAttemptAt time.Time, reason string) error
+	DropDisabledNotifications(ctx context.Context) (int, error)
+	SetNotificationsEnabled(ctx context.Context, userID int64, enabled bool) error
+}
+
+// Notifier delivers notifications to users. It returns
+// domain.ErrRecipientUnavailable if the user can't be messaged anymore, e.g.
+// has blocked the bot.
+type Notifier interface {
+	SendNotification(ctx context.Context, notification domain.Notification) error
+}
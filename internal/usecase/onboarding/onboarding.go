@@ -57,3 +57,36 @@ func (u *OnboardingUsecase) RemoveMe(ctx context.Context, userID int64) error {
 
 	return nil
 }
+
+// Language returns the UI language chosen by the user. Empty string means the
+// user has not chosen one and the client language should be used.
+func (u *OnboardingUsecase) Language(ctx context.Context, userID int64) (string, error) {
+	const op = "Language"
+
+	code, err := u.userRepo.GetLanguageCode(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+func (u *OnboardingUsecase) SetLanguage(ctx context.Context, userID int64, username, code string) error {
+	const op = "SetLanguage"
+
+	// idempotence creation: /language may come before /start
+	if err := u.userRepo.CreateUser(ctx, userID, username); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.userRepo.SetLanguageCode(ctx, userID, code); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.logger.Debug().
+		Int64("user_id", userID).
+		Str("language_code", code).
+		Msgf("%s succeeded", op)
+
+	return nil
+}
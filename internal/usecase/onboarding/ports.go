@@ -5,4 +5,6 @@ import "context"
 type UserRepo interface {
 	CreateUser(ctx context.Context, id int64, username string) error
 	DeleteUser(ctx context.Context, id int64) error
+	GetLanguageCode(ctx context.Context, userID int64) (string, error)
+	SetLanguageCode(ctx context.Context, userID int64, code string) error
 }
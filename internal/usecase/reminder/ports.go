@@ -0,0 +1,25 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type UserRepo interface {
+	GetReminderSettings(ctx context.Context, userID int64) (*domain.ReminderSettings, error)
+	SetRemindersEnabled(ctx context.Context, userID int64, enabled bool) error
+	SetReminderTime(ctx context.Context, userID int64, minute int) error
+	SetTimezone(ctx context.Context, userID int64, timezone string) error
+	SetQuietHours(ctx context.Context, userID int64, start, end *int) error
+	ListReminderCandidates(ctx context.Context, now time.Time, afterUserID int64, limit int) ([]domain.ReminderCandidate, error)
+	MarkReminded(ctx context.Context, userID int64, at time.Time) error
+}
+
+// Notifier delivers reminders to users. It returns
+// domain.ErrRecipientUnavailable if the user can't be messaged anymore, e.g.
+// has blocked the bot.
+type Notifier interface {
+	SendReminder(ctx context.Context, candidate domain.ReminderCandidate) error
+}
@@ -0,0 +1,178 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+	"github.com/krezefal/eng-tg-bot/internal/usecase/broadcast"
+)
+
+// TickInterval is how often due reminders are looked for.
+const TickInterval = time.Minute
+
+// quietHoursOff is the argument that turns the quiet hours off.
+const quietHoursOff = "off"
+
+type Usecase struct {
+	userRepo UserRepo
+	notifier Notifier
+	logger   *zerolog.Logger
+}
+
+func NewUsecase(userRepo UserRepo, notifier Notifier, parentLogger *zerolog.Logger) *Usecase {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "reminder_usecase").Logger()
+
+	return &Usecase{
+		userRepo: userRepo,
+		notifier: notifier,
+		logger:   &logger,
+	}
+}
+
+// Tick sends the reminders due at the moment; the scheduler runs it every
+// TickInterval.
+func (u *Usecase) Tick(ctx context.Context, now time.Time) error {
+	sent, err := u.SendDue(ctx, now)
+	if sent > 0 {
+		u.logger.Info().Int("sent", sent).Msg("reminders sent")
+	}
+
+	return err
+}
+
+// SendDue reminds every user whose reminder is due at the moment and returns
+// how many reminders were sent. Users who can't be messaged anymore get their
+// reminders turned off.
+func (u *Usecase) SendDue(ctx context.Context, now time.Time) (int, error) {
+	const op = "SendDue"
+
+	sent, err := broadcast.Send(
+		ctx,
+		func(ctx context.Context, after int64, limit int) ([]domain.ReminderCandidate, error) {
+			return u.userRepo.ListReminderCandidates(ctx, now, after, limit)
+		},
+		func(candidate domain.ReminderCandidate) int64 { return candidate.UserID },
+		func(ctx context.Context, candidate domain.ReminderCandidate) bool {
+			return candidate.ShouldRemind(now) && u.remind(ctx, candidate, now)
+		},
+	)
+	if err != nil {
+		return sent, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sent, nil
+}
+
+// remind sends the reminder to one user. Failures are logged rather than
+// returned so that one user doesn't hold up the rest; an unsent reminder is
+// retried on the next tick.
+func (u *Usecase) remind(ctx context.Context, candidate domain.ReminderCandidate, now time.Time) bool {
+	logger := u.logger.With().Int64("user_id", candidate.UserID).Logger()
+
+	err := u.notifier.SendReminder(ctx, candidate)
+	if errors.Is(err, domain.ErrRecipientUnavailable) {
+		logger.Info().Err(err).Msg("user is unavailable, turning reminders off")
+
+		if err = u.userRepo.SetRemindersEnabled(ctx, candidate.UserID, false); err != nil {
+			logger.Error().Err(err).Msg("failed to turn reminders off")
+		}
+
+		return false
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to send reminder")
+
+		return false
+	}
+
+	if err = u.userRepo.MarkReminded(ctx, candidate.UserID, now); err != nil {
+		logger.Error().Err(err).Msg("failed to mark user reminded")
+	}
+
+	return true
+}
+
+func (u *Usecase) Settings(ctx context.Context, userID int64) (*domain.ReminderSettings, error) {
+	const op = "Settings"
+
+	settings, err := u.userRepo.GetReminderSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return settings, nil
+}
+
+func (u *Usecase) SetEnabled(ctx context.Context, userID int64, enabled bool) error {
+	const op = "SetEnabled"
+
+	if err := u.userRepo.SetRemindersEnabled(ctx, userID, enabled); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetTime sets the local time of the daily reminder, given like "19:30".
+func (u *Usecase) SetTime(ctx context.Context, userID int64, raw string) error {
+	const op = "SetTime"
+
+	minute, ok := domain.ParseClock(raw)
+	if !ok {
+		return domain.ErrInvalidReminderTime
+	}
+
+	if err := u.userRepo.SetReminderTime(ctx, userID, minute); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetTimezone sets the time zone, given as an IANA name or a UTC offset, and
+// returns the IANA name stored.
+func (u *Usecase) SetTimezone(ctx context.Context, userID int64, raw string) (string, error) {
+	const op = "SetTimezone"
+
+	timezone, ok := domain.ParseTimezone(raw)
+	if !ok {
+		return "", domain.ErrInvalidTimezone
+	}
+
+	if err := u.userRepo.SetTimezone(ctx, userID, timezone); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return timezone, nil
+}
+
+// SetQuietHours sets the quiet hours, given like "23:00-08:00", or turns them
+// off with "off".
+func (u *Usecase) SetQuietHours(ctx context.Context, userID int64, raw string) error {
+	const op = "SetQuietHours"
+
+	var start, end *int
+	if !strings.EqualFold(strings.TrimSpace(raw), quietHoursOff) {
+		from, to, ok := domain.ParseQuietHours(raw)
+		if !ok {
+			return domain.ErrInvalidQuietHours
+		}
+		start, end = &from, &to
+	}
+
+	if err := u.userRepo.SetQuietHours(ctx, userID, start, end); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
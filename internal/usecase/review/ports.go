@@ -11,10 +11,13 @@ type UserRepo interface {
 	SetActiveDictionaryID(ctx context.Context, userID int64, dictionaryID string) error
 	GetActiveDictionaryID(ctx context.Context, userID int64) (string, error)
 	ClearActiveDictionaryID(ctx context.Context, userID int64) error
+	GetReviewKeyboard(ctx context.Context, userID int64) (domain.ReviewKeyboard, error)
+	SetReviewKeyboard(ctx context.Context, userID int64, keyboard domain.ReviewKeyboard) error
 }
 
 type DictionaryRepo interface {
 	ExistsByID(ctx context.Context, dictionaryID string) (bool, error)
+	SampleWordPreviews(ctx context.Context, dictionaryID string, limit int) ([]domain.DictionaryWordPreview, error)
 }
 
 type SubscriptionsRepo interface {
@@ -24,7 +27,37 @@ type SubscriptionsRepo interface {
 
 type WordsStateRepo interface {
 	HasReviewWords(ctx context.Context, userID int64, dictionaryID string) (bool, error)
-	ListDueReviewWords(ctx context.Context, userID int64, dictionaryID string, now time.Time) ([]*domain.ReviewWord, error)
+	HasAnyReviewWords(ctx context.Context, userID int64) (bool, error)
+	ListDueReviewWords(ctx context.Context, userID int64, dictionaryID string, now, dayStart time.Time) ([]*domain.ReviewWord, error)
+	ListAllDueReviewWords(ctx context.Context, userID int64, now, dayStart time.Time) ([]*domain.ReviewWord, error)
+	CountDueReviewWords(ctx context.Context, userID int64, dictionaryID string, until time.Time) (int, error)
 	ListAllReviewWordsByNearest(ctx context.Context, userID int64, dictionaryID string, now time.Time) ([]*domain.ReviewWord, error)
 	ApplyReviewResult(ctx context.Context, in *domain.ApplyReviewResultInput) error
+	GetStateSnapshot(
+		ctx context.Context,
+		userID int64,
+		dictWordID string,
+		direction domain.CardDirection,
+	) (*domain.WordStateSnapshot, error)
+	RestoreState(ctx context.Context, snapshot *domain.WordStateSnapshot) error
+	ResumeSuspended(ctx context.Context, userID int64, now time.Time) error
+	CountReviewsSince(ctx context.Context, userID int64, since time.Time) (int, error)
+}
+
+type SettingsRepo interface {
+	GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+}
+
+// SessionStore keeps the sessions of users between updates. LoadSession
+// reports false for a missing or expired session.
+type SessionStore interface {
+	LoadSession(ctx context.Context, userID int64, kind domain.SessionKind, dst any) (bool, error)
+	SaveSession(ctx context.Context, userID int64, kind domain.SessionKind, session any) error
+	DeleteSession(ctx context.Context, userID int64, kind domain.SessionKind) error
+}
+
+// TxManager runs fn atomically: the repo calls made with the ctx passed to fn
+// are committed together or not at all.
+type TxManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -2,8 +2,11 @@ package review
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
+	"math/rand/v2"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -16,23 +19,107 @@ type Usecase struct {
 	dictionaryRepo DictionaryRepo
 	subsRepo       SubscriptionsRepo
 	wordStateRepo  WordsStateRepo
+	settingsRepo   SettingsRepo
+	sessions       SessionStore
+	txManager      TxManager
 	logger         *zerolog.Logger
-
-	sessionMu sync.RWMutex
-	sessions  map[int64]*reviewSession
 }
 
+// reviewSession is the state of a round, kept in the SessionStore between
+// updates; the exported fields are stored.
 type reviewSession struct {
-	dictionaryID string
-	queue        []*domain.ReviewWord
-	current      *domain.ReviewWord
+	// ID and Seq make up the token of the shown card; see ownsToken.
+	ID       string
+	Seq      int
+	Token    string
+	Keyboard domain.ReviewKeyboard
+	// Settings are the user's settings as of the start of the round.
+	Settings *domain.UserSettings
+	// DictionaryID is empty for a cross-dictionary session: words keep their
+	// own dictionary in ReviewWord.DictionaryID.
+	DictionaryID    string
+	AllDictionaries bool
+	Mode            domain.ReviewMode
+	Queue           []*domain.ReviewWord
+	Current         *domain.ReviewWord
+	// CurrentState is the state of Current as of when it was shown. The grade
+	// is computed from it and stored only if the state has the same Version
+	// still, so a card graded meanwhile elsewhere isn't graded twice.
+	CurrentState *domain.WordStateSnapshot
+	// ShownAt is when Current was shown, used to grade typed answers and quiz
+	// choices by response time.
+	ShownAt time.Time
+	Quiz    *domain.Quiz
+	Cloze   *domain.Cloze
+	// QuizCandidates are the words distractors are picked from in
+	// ReviewModeQuiz, by dictionary ID; see quizCandidates.
+	QuizCandidates map[string][]domain.DictionaryWordPreview
+	// Undo holds the graded cards with their state before the grade, the
+	// latest last.
+	Undo []undoEntry
+	// StartedAt and Grades are used for the summary of the round.
+	StartedAt time.Time
+	Grades    []domain.ReviewGradeRecord
+}
+
+type undoEntry struct {
+	Word     *domain.ReviewWord
+	Grade    int
+	Snapshot *domain.WordStateSnapshot
+}
+
+// ownsToken reports whether the token was issued in this session, for
+// buttons that act on the session rather than on a card.
+func (s *reviewSession) ownsToken(token string) bool {
+	return strings.HasPrefix(token, s.ID+".")
+}
+
+// recordGrade keeps the grade for the summary and for undo.
+func (s *reviewSession) recordGrade(entry undoEntry) {
+	s.Grades = append(s.Grades, domain.ReviewGradeRecord{Word: entry.Word, Grade: entry.Grade})
+	s.Undo = append(s.Undo, entry)
+	if len(s.Undo) > maxUndoDepth {
+		s.Undo = s.Undo[len(s.Undo)-maxUndoDepth:]
+	}
+}
+
+// popUndo takes the last grade back, also from the grades of the summary.
+func (s *reviewSession) popUndo() (undoEntry, bool) {
+	if len(s.Undo) == 0 {
+		return undoEntry{}, false
+	}
+
+	entry := s.Undo[len(s.Undo)-1]
+	s.Undo = s.Undo[:len(s.Undo)-1]
+	if len(s.Grades) > 0 {
+		s.Grades = s.Grades[:len(s.Grades)-1]
+	}
+
+	return entry, true
+}
+
+// requeue puts the word at the head of the queue, followed by the card that
+// was shown instead of it.
+func (s *reviewSession) requeue(word *domain.ReviewWord) {
+	head := []*domain.ReviewWord{word}
+	if s.Current != nil {
+		head = append(head, s.Current)
+	}
+	s.Queue = append(head, s.Queue...)
+	s.Current = nil
 }
 
+// maxUndoDepth is how many grades can be undone in a row.
+const maxUndoDepth = 20
+
 func NewUsecase(
 	userRepo UserRepo,
 	dictionaryRepo DictionaryRepo,
 	subsRepo SubscriptionsRepo,
 	wordStateRepo WordsStateRepo,
+	settingsRepo SettingsRepo,
+	sessions SessionStore,
+	txManager TxManager,
 	parentLogger *zerolog.Logger,
 ) *Usecase {
 	if parentLogger == nil {
@@ -46,8 +133,10 @@ func NewUsecase(
 		dictionaryRepo: dictionaryRepo,
 		subsRepo:       subsRepo,
 		wordStateRepo:  wordStateRepo,
+		settingsRepo:   settingsRepo,
+		sessions:       sessions,
+		txManager:      txManager,
 		logger:         &logger,
-		sessions:       make(map[int64]*reviewSession),
 	}
 }
 
@@ -75,7 +164,9 @@ func (u *Usecase) PrepareByDictionaryNumber(
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	u.clearSession(userID)
+	if err = u.clearSession(ctx, userID); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
 
 	return dictionaryID, nil
 }
@@ -95,33 +186,61 @@ func (u *Usecase) PrepareByDictionaryID(
 	return nil
 }
 
-func (u *Usecase) StartForceRound(ctx context.Context, userID int64, dictionaryID string) (*domain.ReviewWord, error) {
+func (u *Usecase) StartForceRound(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	mode domain.ReviewMode,
+) (*domain.ReviewCard, error) {
 	const op = "StartForceRound"
 
-	if err := u.prepareByDictionaryIDInner(ctx, userID, dictionaryID); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
+	// The round is prepared and its words are listed in one transaction.
+	var words []*domain.ReviewWord
+	err := u.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := u.prepareByDictionaryIDInner(ctx, userID, dictionaryID); err != nil {
+			return err
+		}
 
-	now := time.Now()
-	words, err := u.wordStateRepo.ListAllReviewWordsByNearest(ctx, userID, dictionaryID, now)
+		var err error
+		words, err = u.wordStateRepo.ListAllReviewWordsByNearest(ctx, userID, dictionaryID, time.Now())
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	// TODO: add transaction for prepareByDictionaryIDInner &
-	// ListAllReviewWordsByNearest and get rid of if below:
-	// prepareByDictionaryIDInner will check it with HasReviewWords.
+	// The transaction is READ COMMITTED: the words HasReviewWords has seen
+	// may be gone by now.
 	if len(words) == 0 {
 		return nil, domain.ErrEmptyReviewWordsList
 	}
+	words = burySiblings(words)
+
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if mode == domain.ReviewModePreferred {
+		mode = settings.ReviewMode
+	}
 
-	u.setSession(userID, dictionaryID, words)
+	candidates, err := u.quizCandidates(ctx, words, mode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	nextW, err := u.nextWord(userID)
+	card, err := u.startSession(ctx, userID, &reviewSession{
+		DictionaryID:   dictionaryID,
+		Mode:           mode,
+		Keyboard:       settings.ReviewKeyboard,
+		Settings:       settings,
+		Queue:          words,
+		QuizCandidates: candidates,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return nextW, nil
+	return card, nil
 }
 
 func (u *Usecase) prepareByDictionaryIDInner(
@@ -147,6 +266,10 @@ func (u *Usecase) prepareByDictionaryIDInner(
 		return domain.ErrSubscriptionNotFound
 	}
 
+	if err = u.wordStateRepo.ResumeSuspended(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	hasReviewWords, err := u.wordStateRepo.HasReviewWords(ctx, userID, dictionaryID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -159,14 +282,33 @@ func (u *Usecase) prepareByDictionaryIDInner(
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	u.clearSession(userID)
+	if err = u.clearSession(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
 	return nil
 }
 
-func (u *Usecase) StartDueRound(ctx context.Context, userID int64) (*domain.ReviewWord, string, error) {
+func (u *Usecase) StartDueRound(
+	ctx context.Context,
+	userID int64,
+	mode domain.ReviewMode,
+) (*domain.ReviewCard, string, error) {
 	const op = "StartDueRound"
 
+	session, ok, err := u.getSession(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if ok && session.AllDictionaries {
+		card, err := u.startAllDueRound(ctx, userID, mode)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		return card, "", nil
+	}
+
 	dictionaryID, err := u.userRepo.GetActiveDictionaryID(ctx, userID)
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: %w", op, err)
@@ -175,6 +317,11 @@ func (u *Usecase) StartDueRound(ctx context.Context, userID int64) (*domain.Revi
 		return nil, "", domain.ErrReviewNotStarted
 	}
 
+	now := time.Now()
+	if err = u.wordStateRepo.ResumeSuspended(ctx, userID, now); err != nil {
+		return nil, dictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+
 	hasReviewWords, err := u.wordStateRepo.HasReviewWords(ctx, userID, dictionaryID)
 	if err != nil {
 		return nil, dictionaryID, fmt.Errorf("%s: %w", op, err)
@@ -183,8 +330,15 @@ func (u *Usecase) StartDueRound(ctx context.Context, userID int64) (*domain.Revi
 		return nil, dictionaryID, domain.ErrEmptyReviewWordsList
 	}
 
-	now := time.Now()
-	words, err := u.wordStateRepo.ListDueReviewWords(ctx, userID, dictionaryID, now)
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, dictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+	if mode == domain.ReviewModePreferred {
+		mode = settings.ReviewMode
+	}
+
+	words, err := u.wordStateRepo.ListDueReviewWords(ctx, userID, dictionaryID, now, settings.DayStart(now))
 	if err != nil {
 		return nil, dictionaryID, fmt.Errorf("%s: %w", op, err)
 	}
@@ -192,61 +346,453 @@ func (u *Usecase) StartDueRound(ctx context.Context, userID int64) (*domain.Revi
 		return nil, dictionaryID, domain.ErrNoWordsDueForReview
 	}
 
-	u.setSession(userID, dictionaryID, words)
+	words, err = u.limitDailyReviews(ctx, userID, settings, words, now)
+	if err != nil {
+		return nil, dictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
 
-	nextW, err := u.nextWord(userID)
+	candidates, err := u.quizCandidates(ctx, words, mode)
 	if err != nil {
 		return nil, dictionaryID, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return nextW, dictionaryID, nil
+	card, err := u.startSession(ctx, userID, &reviewSession{
+		DictionaryID:   dictionaryID,
+		Mode:           mode,
+		Keyboard:       settings.ReviewKeyboard,
+		Settings:       settings,
+		Queue:          words,
+		QuizCandidates: candidates,
+	})
+	if err != nil {
+		return nil, dictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return card, dictionaryID, nil
 }
 
-func (u *Usecase) RateCurrent(ctx context.Context, userID int64, grade int) (*domain.ReviewWord, string, error) {
+// PrepareAll scopes the next round to due words of all subscribed
+// dictionaries instead of the active one.
+func (u *Usecase) PrepareAll(ctx context.Context, userID int64) error {
+	const op = "PrepareAll"
+
+	if err := u.wordStateRepo.ResumeSuspended(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	hasReviewWords, err := u.wordStateRepo.HasAnyReviewWords(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !hasReviewWords {
+		return domain.ErrEmptyReviewWordsList
+	}
+
+	if err = u.userRepo.ClearActiveDictionaryID(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = u.setSession(ctx, userID, &reviewSession{AllDictionaries: true}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (u *Usecase) startAllDueRound(
+	ctx context.Context,
+	userID int64,
+	mode domain.ReviewMode,
+) (*domain.ReviewCard, error) {
+	const op = "startAllDueRound"
+
+	now := time.Now()
+	if err := u.wordStateRepo.ResumeSuspended(ctx, userID, now); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if mode == domain.ReviewModePreferred {
+		mode = settings.ReviewMode
+	}
+
+	words, err := u.wordStateRepo.ListAllDueReviewWords(ctx, userID, now, settings.DayStart(now))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(words) == 0 {
+		return nil, domain.ErrNoWordsDueForReview
+	}
+	words = domain.InterleaveReviewWords(words, now)
+
+	words, err = u.limitDailyReviews(ctx, userID, settings, words, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	candidates, err := u.quizCandidates(ctx, words, mode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	card, err := u.startSession(ctx, userID, &reviewSession{
+		AllDictionaries: true,
+		Mode:            mode,
+		Keyboard:        settings.ReviewKeyboard,
+		Settings:        settings,
+		Queue:           words,
+		QuizCandidates:  candidates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return card, nil
+}
+
+func (u *Usecase) RateCurrent(ctx context.Context, userID int64, grade int) (*domain.ReviewCard, string, error) {
 	const op = "RateCurrent"
 
+	session, ok, err := u.getSession(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok || session.Current == nil {
+		return nil, "", domain.ErrReviewNotStarted
+	}
+
+	card, err := u.rate(ctx, userID, session, grade)
+	if err != nil {
+		return nil, session.DictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return card, session.DictionaryID, nil
+}
+
+// RateCard is RateCurrent for a button of an inline card.
+// ErrStaleReviewCard is returned when the token is not of the current card.
+func (u *Usecase) RateCard(
+	ctx context.Context,
+	userID int64,
+	token string,
+	grade int,
+) (*domain.ReviewCard, string, error) {
+	const op = "RateCard"
+
+	session, ok, err := u.getSession(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok || session.Current == nil || session.Token != token {
+		return nil, "", domain.ErrStaleReviewCard
+	}
+
+	card, err := u.rate(ctx, userID, session, grade)
+	if err != nil {
+		return nil, session.DictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return card, session.DictionaryID, nil
+}
+
+// rate grades the current card of the session and moves on to the next one.
+func (u *Usecase) rate(ctx context.Context, userID int64, session *reviewSession, grade int) (*domain.ReviewCard, error) {
 	if grade < domain.MinGrade || grade > domain.MaxGrade {
-		return nil, "", domain.ErrInvalidReviewGrade
+		return nil, domain.ErrInvalidReviewGrade
 	}
 
-	session, ok := u.getSession(userID)
-	if !ok || session.current == nil {
-		return nil, "", domain.ErrReviewNotStarted
+	err := u.applyGrade(ctx, userID, session, session.Current, grade, false, time.Now())
+	if errors.Is(err, domain.ErrWordStateChanged) {
+		return u.skipChanged(ctx, userID, session)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return u.next(ctx, userID, session)
+}
+
+// AnswerCurrent grades a typed answer to the current card of a typed, cloze or
+// listening session.
+// ErrAnswerNotExpected is returned when the user has no such card, so that
+// arbitrary chat text can be ignored. The result is returned even when the
+// round is finished.
+func (u *Usecase) AnswerCurrent(
+	ctx context.Context,
+	userID int64,
+	answer string,
+) (*domain.TypedAnswerResult, *domain.ReviewCard, string, error) {
+	const op = "AnswerCurrent"
+
+	session, ok, err := u.getSession(ctx, userID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok || session.Current == nil || !session.Mode.TypesAnswers() {
+		return nil, nil, "", domain.ErrAnswerNotExpected
 	}
 
 	now := time.Now()
+	current := session.Current
+	elapsed := now.Sub(session.ShownAt)
+
+	var result *domain.TypedAnswerResult
+	if session.Cloze != nil {
+		result = domain.GradeClozeAnswer(session.Cloze, current, answer, elapsed, session.Settings.Scheduler)
+	} else {
+		result = domain.GradeTypedAnswer(current.Spelling, answer, elapsed, session.Settings.Scheduler)
+	}
+	result.Word = current
+
+	err = u.applyGrade(ctx, userID, session, current, result.Grade, result.Slow, now)
+	if errors.Is(err, domain.ErrWordStateChanged) {
+		card, err := u.skipChanged(ctx, userID, session)
+		if err != nil {
+			return nil, nil, session.DictionaryID, fmt.Errorf("%s: %w", op, err)
+		}
+		return nil, card, session.DictionaryID, nil
+	}
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	card, err := u.next(ctx, userID, session)
+	if err != nil {
+		return result, nil, session.DictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, card, session.DictionaryID, nil
+}
+
+// AnswerQuiz grades the choice of an option of the current quiz card.
+// ErrStaleReviewCard is returned for stale buttons: when the token is not of
+// the current card of a quiz session. The result is returned even when the
+// round is finished.
+func (u *Usecase) AnswerQuiz(
+	ctx context.Context,
+	userID int64,
+	token string,
+	option int,
+) (*domain.QuizAnswerResult, *domain.ReviewCard, string, error) {
+	const op = "AnswerQuiz"
+
+	session, ok, err := u.getSession(ctx, userID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok || session.Current == nil || session.Mode != domain.ReviewModeQuiz ||
+		session.Token != token || session.Quiz == nil ||
+		option < 0 || option >= len(session.Quiz.Options) {
+		return nil, nil, "", domain.ErrStaleReviewCard
+	}
+
+	now := time.Now()
+	current := session.Current
+	result := domain.GradeQuizAnswer(session.Quiz, option, now.Sub(session.ShownAt), session.Settings.Scheduler)
+	result.Word = current
+
+	err = u.applyGrade(ctx, userID, session, current, result.Grade, result.Slow, now)
+	if errors.Is(err, domain.ErrWordStateChanged) {
+		card, err := u.skipChanged(ctx, userID, session)
+		if err != nil {
+			return nil, nil, session.DictionaryID, fmt.Errorf("%s: %w", op, err)
+		}
+		return nil, card, session.DictionaryID, nil
+	}
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	card, err := u.next(ctx, userID, session)
+	if err != nil {
+		return result, nil, session.DictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, card, session.DictionaryID, nil
+}
+
+// quizCandidates loads distractor candidates for every dictionary of the
+// words: a sample of up to domain.QuizCandidatesLimit words, kept in the
+// session for the whole round.
+func (u *Usecase) quizCandidates(
+	ctx context.Context,
+	words []*domain.ReviewWord,
+	mode domain.ReviewMode,
+) (map[string][]domain.DictionaryWordPreview, error) {
+	const op = "quizCandidates"
+
+	if mode != domain.ReviewModeQuiz {
+		return nil, nil
+	}
+
+	candidates := make(map[string][]domain.DictionaryWordPreview)
+	for _, w := range words {
+		if _, ok := candidates[w.DictionaryID]; ok {
+			continue
+		}
+
+		previews, err := u.dictionaryRepo.SampleWordPreviews(ctx, w.DictionaryID, domain.QuizCandidatesLimit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		candidates[w.DictionaryID] = previews
+	}
+
+	return candidates, nil
+}
+
+// applyGrade stores the grade of the word, given slow if the answer took
+// long, and records it in the session; the caller saves the session. The grade is computed from the state of the card
+// when it was shown and stored only if that state is still current: a card
+// changed meanwhile, e.g. by /word or a round of all dictionaries, is rejected
+// with ErrWordStateChanged, see skipChanged.
+func (u *Usecase) applyGrade(
+	ctx context.Context,
+	userID int64,
+	session *reviewSession,
+	word *domain.ReviewWord,
+	grade int,
+	slow bool,
+	now time.Time,
+) error {
+	const op = "applyGrade"
+
+	snapshot := session.CurrentState
+	if snapshot == nil {
+		// The session was saved before the state was kept with the card.
+		var err error
+		snapshot, err = u.wordStateRepo.GetStateSnapshot(ctx, userID, word.ID, word.Direction)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	result, err := domain.ComputeSM2(&domain.SM2Input{
-		EF:           session.current.EF,
-		IntervalDays: session.current.IntervalDays,
-		Repetition:   session.current.Repetition,
+		EF:           snapshot.EF,
+		IntervalDays: snapshot.IntervalDays,
+		Repetition:   snapshot.Repetition,
 		Grade:        grade,
+		Slow:         slow,
+		Scheduler:    session.Settings.Scheduler,
 	}, now)
 	if err != nil {
-		return nil, "", fmt.Errorf("%s: %w", op, err)
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err = u.wordStateRepo.ApplyReviewResult(ctx, &domain.ApplyReviewResultInput{
+	err = u.wordStateRepo.ApplyReviewResult(ctx, &domain.ApplyReviewResultInput{
 		UserID:     userID,
-		DictWordID: session.current.ID,
+		DictWordID: word.ID,
+		Direction:  word.Direction,
 		Grade:      grade,
+		Passed:     grade >= domain.PassingGrade(session.Settings.Scheduler),
 		Result:     result,
 		ReviewedAt: now,
-	}); err != nil {
+		Version:    snapshot.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	session.recordGrade(undoEntry{Word: word, Grade: grade, Snapshot: snapshot})
+
+	return nil
+}
+
+// Undo restores the state of the last graded card of the session and shows it
+// again. It can be repeated up to maxUndoDepth times, also after the round is
+// finished.
+func (u *Usecase) Undo(ctx context.Context, userID int64) (*domain.ReviewCard, string, error) {
+	const op = "Undo"
+
+	session, ok, err := u.getSession(ctx, userID)
+	if err != nil {
 		return nil, "", fmt.Errorf("%s: %w", op, err)
 	}
+	if !ok {
+		return nil, "", domain.ErrNothingToUndo
+	}
+
+	card, err := u.undo(ctx, userID, session)
+	if err != nil {
+		return nil, session.DictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return card, session.DictionaryID, nil
+}
+
+// UndoCard is Undo for a button of an inline card. Any card of the session
+// may undo, since the session shows its cards in a single message.
+func (u *Usecase) UndoCard(ctx context.Context, userID int64, token string) (*domain.ReviewCard, string, error) {
+	const op = "UndoCard"
 
-	nextW, err := u.nextWord(userID)
+	session, ok, err := u.getSession(ctx, userID)
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: %w", op, err)
 	}
+	if !ok || !session.ownsToken(token) {
+		return nil, "", domain.ErrStaleReviewCard
+	}
+
+	card, err := u.undo(ctx, userID, session)
+	if err != nil {
+		return nil, session.DictionaryID, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return card, session.DictionaryID, nil
+}
+
+func (u *Usecase) undo(ctx context.Context, userID int64, session *reviewSession) (*domain.ReviewCard, error) {
+	entry, ok := session.popUndo()
+	if !ok {
+		return nil, domain.ErrNothingToUndo
+	}
+
+	err := u.wordStateRepo.RestoreState(ctx, entry.Snapshot)
+	if errors.Is(err, domain.ErrWordStateChanged) {
+		// The card has been graded again since, so the entry can't be undone
+		// and is dropped.
+		if err = u.sessions.SaveSession(ctx, userID, domain.SessionReview, session); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrNothingToUndo
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session.requeue(entry.Word)
+
+	return u.advance(ctx, userID, session)
+}
+
+// StopCard is Stop for a button of an inline card of the session.
+func (u *Usecase) StopCard(ctx context.Context, userID int64, token string) error {
+	const op = "StopCard"
+
+	session, ok, err := u.getSession(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok || !session.ownsToken(token) {
+		return domain.ErrStaleReviewCard
+	}
+
+	if err = u.Stop(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
-	return nextW, session.dictionaryID, nil
+	return nil
 }
 
 func (u *Usecase) Stop(ctx context.Context, userID int64) error {
 	const op = "Stop"
 
-	u.clearSession(userID)
+	if err := u.clearSession(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 	if err := u.userRepo.ClearActiveDictionaryID(ctx, userID); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -265,43 +811,230 @@ func (u *Usecase) ActiveDictionaryID(ctx context.Context, userID int64) (string,
 	return dictionaryID, nil
 }
 
-func (u *Usecase) setSession(userID int64, dictionaryID string, words []*domain.ReviewWord) {
-	u.sessionMu.Lock()
-	defer u.sessionMu.Unlock()
+func (u *Usecase) ReviewKeyboard(ctx context.Context, userID int64) (domain.ReviewKeyboard, error) {
+	const op = "ReviewKeyboard"
+
+	keyboard, err := u.userRepo.GetReviewKeyboard(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keyboard, nil
+}
+
+// SetReviewKeyboard changes the review UI; it applies from the next round.
+func (u *Usecase) SetReviewKeyboard(ctx context.Context, userID int64, keyboard domain.ReviewKeyboard) error {
+	const op = "SetReviewKeyboard"
 
-	u.sessions[userID] = &reviewSession{
-		dictionaryID: dictionaryID,
-		queue:        words,
-		current:      nil,
+	if _, ok := domain.ParseReviewKeyboard(string(keyboard)); !ok {
+		return domain.ErrInvalidReviewKeyboard
 	}
+
+	if err := u.userRepo.SetReviewKeyboard(ctx, userID, keyboard); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
 }
 
-func (u *Usecase) getSession(userID int64) (*reviewSession, bool) {
-	u.sessionMu.RLock()
-	defer u.sessionMu.RUnlock()
+// startSession gives the session a new ID and shows its first card.
+func (u *Usecase) startSession(ctx context.Context, userID int64, session *reviewSession) (*domain.ReviewCard, error) {
+	session.ID = strconv.FormatUint(uint64(rand.Uint32()), 36)
+	session.StartedAt = time.Now()
 
-	session, ok := u.sessions[userID]
-	return session, ok
+	return u.advance(ctx, userID, session)
 }
 
-func (u *Usecase) clearSession(userID int64) {
-	u.sessionMu.Lock()
-	defer u.sessionMu.Unlock()
-	delete(u.sessions, userID)
+// setSession stores a new session without showing a card.
+func (u *Usecase) setSession(ctx context.Context, userID int64, session *reviewSession) error {
+	session.ID = strconv.FormatUint(uint64(rand.Uint32()), 36)
+	session.StartedAt = time.Now()
+
+	return u.sessions.SaveSession(ctx, userID, domain.SessionReview, session)
+}
+
+// getSession loads the session of the user; false is returned if there is
+// none or it has expired.
+func (u *Usecase) getSession(ctx context.Context, userID int64) (*reviewSession, bool, error) {
+	session := &reviewSession{}
+	ok, err := u.sessions.LoadSession(ctx, userID, domain.SessionReview, session)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return session, true, nil
+}
+
+func (u *Usecase) clearSession(ctx context.Context, userID int64) error {
+	return u.sessions.DeleteSession(ctx, userID, domain.SessionReview)
+}
+
+// skipChanged moves on from the current card when its grade was rejected for
+// a change made elsewhere, and saves the session; the card is done for the
+// round, as its new state was not shown. The next card is marked with
+// PreviousChanged.
+func (u *Usecase) skipChanged(ctx context.Context, userID int64, session *reviewSession) (*domain.ReviewCard, error) {
+	u.logger.Debug().
+		Int64("user_id", userID).
+		Str("dict_word_id", session.Current.ID).
+		Str("direction", string(session.Current.Direction)).
+		Msg("skipChanged: card changed since shown, skipped")
+
+	card, err := u.next(ctx, userID, session)
+	if card != nil {
+		card.PreviousChanged = true
+	}
+
+	return card, err
+}
+
+// next is advance that returns the summary of a finished round in
+// ReviewRoundFinishedError.
+func (u *Usecase) next(ctx context.Context, userID int64, session *reviewSession) (*domain.ReviewCard, error) {
+	const op = "next"
+
+	card, err := u.advance(ctx, userID, session)
+	if !errors.Is(err, domain.ErrReviewRoundFinished) {
+		return card, err
+	}
+
+	summary := domain.SummarizeReview(session.Grades, session.Settings.Scheduler, time.Since(session.StartedAt))
+
+	tomorrowEnd := session.Settings.DayStart(time.Now()).AddDate(0, 0, 2)
+	summary.DueTomorrow, err = u.wordStateRepo.CountDueReviewWords(ctx, userID, session.DictionaryID, tomorrowEnd)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil, &domain.ReviewRoundFinishedError{Summary: summary}
 }
 
-func (u *Usecase) nextWord(userID int64) (*domain.ReviewWord, error) {
-	u.sessionMu.Lock()
-	defer u.sessionMu.Unlock()
+// advance moves the session to the next card and saves it. A finished
+// session is kept with no current card, so that its grades can still be
+// undone; ErrReviewRoundFinished is returned for it.
+func (u *Usecase) advance(ctx context.Context, userID int64, session *reviewSession) (*domain.ReviewCard, error) {
+	const op = "advance"
+
+	if err := u.ensureQuizCandidates(ctx, session); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	session, ok := u.sessions[userID]
-	if !ok || len(session.queue) == 0 {
-		delete(u.sessions, userID)
+	card := nextCard(session)
+	if card != nil {
+		state, err := u.wordStateRepo.GetStateSnapshot(ctx, userID, session.Current.ID, session.Current.Direction)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		session.CurrentState = state
+	}
+	if err := u.sessions.SaveSession(ctx, userID, domain.SessionReview, session); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if card == nil {
 		return nil, domain.ErrReviewRoundFinished
 	}
 
-	session.current = session.queue[0]
-	session.queue = session.queue[1:]
+	return card, nil
+}
+
+// ensureQuizCandidates loads the distractor candidates of a quiz session
+// stored without them.
+func (u *Usecase) ensureQuizCandidates(ctx context.Context, session *reviewSession) error {
+	if session.Mode != domain.ReviewModeQuiz || session.QuizCandidates != nil || len(session.Queue) == 0 {
+		return nil
+	}
+
+	candidates, err := u.quizCandidates(ctx, session.Queue, session.Mode)
+	if err != nil {
+		return err
+	}
+	session.QuizCandidates = candidates
+
+	return nil
+}
+
+// nextCard moves the session to the next card; nil is returned when the
+// queue is empty.
+func nextCard(session *reviewSession) *domain.ReviewCard {
+	session.Quiz = nil
+	session.Cloze = nil
+	session.CurrentState = nil
+	if len(session.Queue) == 0 {
+		session.Current = nil
+		return nil
+	}
+
+	session.Current = session.Queue[0]
+	session.Queue = session.Queue[1:]
+	session.ShownAt = time.Now()
+	session.Seq++
+	session.Token = session.ID + "." + strconv.Itoa(session.Seq)
+
+	card := &domain.ReviewCard{
+		Word:     session.Current,
+		Mode:     session.Mode,
+		Token:    session.Token,
+		Keyboard: session.Keyboard,
+	}
+	switch session.Mode {
+	case domain.ReviewModeQuiz:
+		session.Quiz = domain.BuildQuiz(session.Current, session.QuizCandidates[session.Current.DictionaryID])
+		card.Quiz = session.Quiz
+	case domain.ReviewModeCloze:
+		cloze, ok := domain.BuildCloze(session.Current)
+		if !ok {
+			card.Mode = domain.ReviewModeTyped
+			break
+		}
+		session.Cloze = cloze
+		card.Cloze = cloze
+	case domain.ReviewModeListening:
+		if strings.TrimSpace(session.Current.Audio) == "" {
+			card.Mode = domain.ReviewModeTyped
+		}
+	}
+
+	return card
+}
+
+// burySiblings keeps only the first card of every word, so both directions of
+// a word don't show up in the same round.
+func burySiblings(words []*domain.ReviewWord) []*domain.ReviewWord {
+	seen := make(map[string]struct{}, len(words))
+	buried := words[:0]
+	for _, w := range words {
+		if _, ok := seen[w.ID]; ok {
+			continue
+		}
+
+		seen[w.ID] = struct{}{}
+		buried = append(buried, w)
+	}
+
+	return buried
+}
+
+// limitDailyReviews cuts the due cards down to the grades left of the daily
+// limit. ErrDailyReviewLimit is returned if none are left.
+func (u *Usecase) limitDailyReviews(
+	ctx context.Context,
+	userID int64,
+	settings *domain.UserSettings,
+	words []*domain.ReviewWord,
+	now time.Time,
+) ([]*domain.ReviewWord, error) {
+	done, err := u.wordStateRepo.CountReviewsSince(ctx, userID, settings.DayStart(now))
+	if err != nil {
+		return nil, err
+	}
+
+	left := settings.ReviewsPerDay - done
+	if left <= 0 {
+		return nil, domain.ErrDailyReviewLimit
+	}
+	if len(words) > left {
+		words = words[:left]
+	}
 
-	return session.current, nil
+	return words, nil
 }
@@ -0,0 +1,78 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+func TestNextCardListening(t *testing.T) {
+	tests := []struct {
+		name     string
+		audio    string
+		wantMode domain.ReviewMode
+	}{
+		{"with audio", "https://example.com/cat.mp3", domain.ReviewModeListening},
+		{"without audio", "", domain.ReviewModeTyped},
+		{"blank audio", "  ", domain.ReviewModeTyped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			word := &domain.ReviewWord{ID: "1", Spelling: "cat", Audio: tt.audio}
+			session := &reviewSession{
+				ID:    "s",
+				Mode:  domain.ReviewModeListening,
+				Queue: []*domain.ReviewWord{word},
+			}
+
+			card := nextCard(session)
+			if card == nil {
+				t.Fatal("nextCard = nil, want a card")
+			}
+			if card.Mode != tt.wantMode {
+				t.Errorf("card mode = %q, want %q", card.Mode, tt.wantMode)
+			}
+			if !card.Mode.TypesAnswers() {
+				t.Errorf("card mode %q doesn't take typed answers", card.Mode)
+			}
+			if session.Current != word || len(session.Queue) != 0 {
+				t.Errorf("session isn't moved to the word")
+			}
+		})
+	}
+}
+
+func TestNextCardToken(t *testing.T) {
+	session := &reviewSession{
+		ID:       "s",
+		Mode:     domain.ReviewModeFlashcard,
+		Keyboard: domain.ReviewKeyboardInline,
+		Queue:    []*domain.ReviewWord{{ID: "1"}, {ID: "2"}},
+	}
+
+	first := nextCard(session)
+	second := nextCard(session)
+	if first == nil || second == nil {
+		t.Fatal("nextCard = nil, want a card for every word")
+	}
+	if first.Token == second.Token {
+		t.Errorf("cards share the token %q", first.Token)
+	}
+	if second.Token != session.Token {
+		t.Errorf("session token = %q, want the one of the shown card %q", session.Token, second.Token)
+	}
+	if !second.Inline() {
+		t.Error("flashcard of an inline session isn't inline")
+	}
+	if !session.ownsToken(first.Token) || session.ownsToken("other."+first.Token) {
+		t.Error("session doesn't tell its own tokens from others")
+	}
+
+	if card := nextCard(session); card != nil {
+		t.Errorf("nextCard of an empty queue = %+v, want nil", card)
+	}
+	if session.Current != nil {
+		t.Error("session keeps the current card after the queue is over")
+	}
+}
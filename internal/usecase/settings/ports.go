@@ -0,0 +1,12 @@
+package settings
+
+import (
+	"context"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type SettingsRepo interface {
+	GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+	UpdateSettings(ctx context.Context, userID int64, settings *domain.UserSettings) error
+}
@@ -0,0 +1,72 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type Usecase struct {
+	settingsRepo SettingsRepo
+	logger       *zerolog.Logger
+}
+
+func NewUsecase(settingsRepo SettingsRepo, parentLogger *zerolog.Logger) *Usecase {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "settings_usecase").Logger()
+
+	return &Usecase{
+		settingsRepo: settingsRepo,
+		logger:       &logger,
+	}
+}
+
+func (u *Usecase) Settings(ctx context.Context, userID int64) (*domain.UserSettings, error) {
+	const op = "Settings"
+
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return settings, nil
+}
+
+// Adjust moves a setting by delta steps, see domain.UserSettings.Adjust, and
+// returns the updated settings.
+func (u *Usecase) Adjust(
+	ctx context.Context,
+	userID int64,
+	field domain.SettingField,
+	delta int,
+) (*domain.UserSettings, error) {
+	const op = "Adjust"
+
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = settings.Adjust(field, delta, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err = u.settingsRepo.UpdateSettings(ctx, userID, settings); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.logger.Debug().
+		Int64("user_id", userID).
+		Str("field", string(field)).
+		Int("delta", delta).
+		Msgf("%s succeeded", op)
+
+	return settings, nil
+}
@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type SubscriptionsRepo interface {
+	ListByUser(ctx context.Context, userID int64) ([]domain.Dictionary, error)
+}
+
+type SettingsRepo interface {
+	GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+}
+
+type StatsRepo interface {
+	CountWordsByStatus(ctx context.Context, userID int64, dictionaryID string) (map[domain.UserWordStatus]int, error)
+	CountRetention(ctx context.Context, userID int64, dictionaryID string, since time.Time) (domain.Retention, error)
+	ListReviewsPerDay(
+		ctx context.Context,
+		userID int64,
+		dictionaryID, timezone string,
+		since time.Time,
+	) ([]domain.DayCount, error)
+	ListDueForecast(
+		ctx context.Context,
+		userID int64,
+		dictionaryID, timezone string,
+		today, until time.Time,
+	) ([]domain.DayCount, error)
+}
@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type Usecase struct {
+	subsRepo     SubscriptionsRepo
+	settingsRepo SettingsRepo
+	statsRepo    StatsRepo
+	logger       *zerolog.Logger
+}
+
+func NewUsecase(
+	subsRepo SubscriptionsRepo,
+	settingsRepo SettingsRepo,
+	statsRepo StatsRepo,
+	parentLogger *zerolog.Logger,
+) *Usecase {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "stats_usecase").Logger()
+
+	return &Usecase{
+		subsRepo:     subsRepo,
+		settingsRepo: settingsRepo,
+		statsRepo:    statsRepo,
+		logger:       &logger,
+	}
+}
+
+// Stats returns the progress of the user in the subscribed dictionary of the
+// number, as listed by /mydict, or in all of them for number 0.
+func (u *Usecase) Stats(ctx context.Context, userID int64, number int) (*domain.UserStats, error) {
+	const op = "Stats"
+
+	if number < 0 {
+		return nil, domain.ErrInvalidDictionaryNumber
+	}
+
+	stats := &domain.UserStats{}
+
+	dictionaryID := ""
+	if number > 0 {
+		dictionaries, err := u.subsRepo.ListByUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if number > len(dictionaries) {
+			return nil, domain.ErrInvalidDictionaryNumber
+		}
+
+		dictionaryID = dictionaries[number-1].ID
+		stats.DictionaryTitle = dictionaries[number-1].Title
+	}
+
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	loc := settings.Reminder.Location()
+	today := domain.LocalDate(now, loc)
+
+	stats.WordsByStatus, err = u.statsRepo.CountWordsByStatus(ctx, userID, dictionaryID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	shortSince := domain.DateStart(today.AddDate(0, 0, 1-domain.RetentionShortDays), loc)
+	stats.RetentionShort, err = u.statsRepo.CountRetention(ctx, userID, dictionaryID, shortSince)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	firstDay := today.AddDate(0, 0, 1-domain.StatsDays)
+	longSince := domain.DateStart(firstDay, loc)
+	stats.RetentionLong, err = u.statsRepo.CountRetention(ctx, userID, dictionaryID, longSince)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reviews, err := u.statsRepo.ListReviewsPerDay(ctx, userID, dictionaryID, loc.String(), longSince)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	stats.ReviewsPerDay = domain.FillDays(reviews, firstDay, domain.StatsDays)
+
+	until := domain.DateStart(today.AddDate(0, 0, domain.StatsDays), loc)
+	forecast, err := u.statsRepo.ListDueForecast(ctx, userID, dictionaryID, loc.String(), today, until)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	stats.DueForecast = domain.FillDays(forecast, today, domain.StatsDays)
+
+	u.logger.Debug().
+		Int64("user_id", userID).
+		Str("dictionary_id", dictionaryID).
+		Msgf("%s succeeded", op)
+
+	return stats, nil
+}
@@ -0,0 +1,18 @@
+package streak
+
+import (
+	"context"
+	"time"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type SettingsRepo interface {
+	GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+}
+
+type StreakRepo interface {
+	ListDailyActivity(ctx context.Context, userID int64, timezone string, since time.Time) ([]domain.DayActivity, error)
+	GetStreakState(ctx context.Context, userID int64) (*domain.StreakState, error)
+	SaveStreakSettlement(ctx context.Context, userID int64, prev *time.Time, settlement domain.StreakSettlement) (bool, error)
+}
@@ -0,0 +1,105 @@
+package streak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+// settleAttempts bounds the retries of a settlement that lost the race to a
+// concurrent one.
+const settleAttempts = 3
+
+var errSettledConcurrently = errors.New("streak settled concurrently")
+
+type Usecase struct {
+	settingsRepo SettingsRepo
+	streakRepo   StreakRepo
+	logger       *zerolog.Logger
+}
+
+func NewUsecase(settingsRepo SettingsRepo, streakRepo StreakRepo, parentLogger *zerolog.Logger) *Usecase {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "streak_usecase").Logger()
+
+	return &Usecase{
+		settingsRepo: settingsRepo,
+		streakRepo:   streakRepo,
+		logger:       &logger,
+	}
+}
+
+// Streak settles the streak freezes of the days finished since the last call
+// and returns the streaks with the activity calendar.
+func (u *Usecase) Streak(ctx context.Context, userID int64) (*domain.StreakStats, error) {
+	const op = "Streak"
+
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for range settleAttempts {
+		stats, err := u.settle(ctx, userID, settings, time.Now())
+		if errors.Is(err, errSettledConcurrently) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		u.logger.Debug().
+			Int64("user_id", userID).
+			Int("current", stats.Current).
+			Int("longest", stats.Longest).
+			Msgf("%s succeeded", op)
+
+		return stats, nil
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, errSettledConcurrently)
+}
+
+func (u *Usecase) settle(
+	ctx context.Context,
+	userID int64,
+	settings *domain.UserSettings,
+	now time.Time,
+) (*domain.StreakStats, error) {
+	loc := settings.Reminder.Location()
+	today := domain.LocalDate(now, loc)
+	since := domain.DateStart(domain.ActivityCalendarStart(today), loc)
+
+	state, err := u.streakRepo.GetStreakState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := u.streakRepo.ListDailyActivity(ctx, userID, loc.String(), since)
+	if err != nil {
+		return nil, err
+	}
+	log := domain.NewActivityLog(days)
+
+	settlement := domain.SettleStreak(log, settings.DailyGoal, *state, settings.StreakFreeze, today)
+	if state.SettledThrough == nil || state.SettledThrough.Before(settlement.SettledThrough) {
+		saved, err := u.streakRepo.SaveStreakSettlement(ctx, userID, state.SettledThrough, settlement)
+		if err != nil {
+			return nil, err
+		}
+		if !saved {
+			return nil, errSettledConcurrently
+		}
+		log.Freeze(settlement.Frozen)
+	}
+
+	return domain.ComputeStreak(log, settings.DailyGoal, today, settlement.Freezes), nil
+}
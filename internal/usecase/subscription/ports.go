@@ -1,6 +1,10 @@
 package subscription
 
-import "context"
+import (
+	"context"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
 
 type UserRepo interface {
 	CreateUser(ctx context.Context, id int64, username string) error
@@ -11,7 +15,13 @@ type DictionaryRepo interface {
 }
 
 type SubscriptionsRepo interface {
-	Subscribe(ctx context.Context, userID int64, dictionaryID string) (bool, error)
+	Subscribe(ctx context.Context, userID int64, dictionaryID string, directions domain.CardDirections) (bool, error)
 	Unsubscribe(ctx context.Context, userID int64, dictionaryID string) (bool, error)
 	IsSubscribedByUser(ctx context.Context, userID int64, dictionaryID string) (bool, error)
+	GetCardDirections(ctx context.Context, userID int64, dictionaryID string) (domain.CardDirections, error)
+	SetCardDirections(ctx context.Context, userID int64, dictionaryID string, directions domain.CardDirections) error
+}
+
+type SettingsRepo interface {
+	GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
 }
@@ -10,16 +10,18 @@ import (
 )
 
 type SubscriptionUsecase struct {
-	userRepo UserRepo
-	dictRepo DictionaryRepo
-	subsRepo SubscriptionsRepo
-	logger   *zerolog.Logger
+	userRepo     UserRepo
+	dictRepo     DictionaryRepo
+	subsRepo     SubscriptionsRepo
+	settingsRepo SettingsRepo
+	logger       *zerolog.Logger
 }
 
 func NewUsecase(
 	userRepo UserRepo,
 	dictRepo DictionaryRepo,
 	subsRepo SubscriptionsRepo,
+	settingsRepo SettingsRepo,
 	parentLogger *zerolog.Logger,
 ) *SubscriptionUsecase {
 	if parentLogger == nil {
@@ -29,10 +31,11 @@ func NewUsecase(
 	logger := parentLogger.With().Str("component", "subscription_usecase").Logger()
 
 	return &SubscriptionUsecase{
-		userRepo: userRepo,
-		dictRepo: dictRepo,
-		subsRepo: subsRepo,
-		logger:   &logger,
+		userRepo:     userRepo,
+		dictRepo:     dictRepo,
+		subsRepo:     subsRepo,
+		settingsRepo: settingsRepo,
+		logger:       &logger,
 	}
 }
 
@@ -43,7 +46,12 @@ func (u *SubscriptionUsecase) Subscribe(ctx context.Context, userID int64, usern
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	inserted, err := u.subsRepo.Subscribe(ctx, userID, dictionaryID)
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	inserted, err := u.subsRepo.Subscribe(ctx, userID, dictionaryID, settings.CardDirections)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -113,6 +121,46 @@ func (u *SubscriptionUsecase) EnsureSubscribed(ctx context.Context, userID int64
 	return nil
 }
 
+func (u *SubscriptionUsecase) CardDirections(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+) (domain.CardDirections, error) {
+	const op = "CardDirections"
+
+	directions, err := u.subsRepo.GetCardDirections(ctx, userID, dictionaryID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return directions, nil
+}
+
+func (u *SubscriptionUsecase) SetCardDirections(
+	ctx context.Context,
+	userID int64,
+	dictionaryID string,
+	directions domain.CardDirections,
+) error {
+	const op = "SetCardDirections"
+
+	if _, ok := domain.ParseCardDirections(string(directions)); !ok {
+		return domain.ErrInvalidCardDirections
+	}
+
+	if err := u.subsRepo.SetCardDirections(ctx, userID, dictionaryID, directions); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.logger.Debug().
+		Int64("user_id", userID).
+		Str("dictionary_id", dictionaryID).
+		Str("card_directions", string(directions)).
+		Msgf("%s succeeded", op)
+
+	return nil
+}
+
 func (u *SubscriptionUsecase) ensureUserAndDictionaryByID(ctx context.Context, userID int64, username, dictionaryID string) error {
 	// Create user for the case when the user picked removeAll before.
 	// Idempotent creation.
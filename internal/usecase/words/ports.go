@@ -0,0 +1,24 @@
+package words
+
+import (
+	"context"
+	"time"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type WordsStateRepo interface {
+	ListUserWordsBySpelling(ctx context.Context, userID int64, spelling string) ([]*domain.UserWord, error)
+	ListUserWordsByStatus(ctx context.Context, userID int64, statuses []domain.UserWordStatus, limit int) ([]*domain.UserWord, error)
+	GetUserWord(ctx context.Context, userID int64, dictWordID string) (*domain.UserWord, error)
+	ListWordReviewHistory(ctx context.Context, userID int64, dictWordID string, limit int) ([]domain.ReviewHistoryEntry, error)
+	UnblockWord(ctx context.Context, userID int64, dictWordID string) (bool, error)
+	ResetWord(ctx context.Context, userID int64, dictWordID string) (bool, error)
+	SuspendWord(ctx context.Context, userID int64, dictWordID string, until time.Time) (bool, error)
+	RescheduleWord(ctx context.Context, userID int64, dictWordID string, at time.Time) (bool, error)
+	MarkWordKnown(ctx context.Context, userID int64, dictWordID string, intervalDays int, now time.Time) (bool, error)
+}
+
+type SettingsRepo interface {
+	GetSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+}
@@ -0,0 +1,196 @@
+package words
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+type Usecase struct {
+	wordStateRepo WordsStateRepo
+	settingsRepo  SettingsRepo
+	logger        *zerolog.Logger
+}
+
+func NewUsecase(wordStateRepo WordsStateRepo, settingsRepo SettingsRepo, parentLogger *zerolog.Logger) *Usecase {
+	if parentLogger == nil {
+		panic("logger cannot be nil")
+	}
+
+	logger := parentLogger.With().Str("component", "words_usecase").Logger()
+
+	return &Usecase{
+		wordStateRepo: wordStateRepo,
+		settingsRepo:  settingsRepo,
+		logger:        &logger,
+	}
+}
+
+// Find returns the words of the subscribed dictionaries spelled so, with their
+// latest grades; a word may be in several dictionaries.
+func (u *Usecase) Find(ctx context.Context, userID int64, spelling string) ([]*domain.UserWord, error) {
+	const op = "Find"
+
+	spelling = strings.TrimSpace(spelling)
+	if spelling == "" {
+		return nil, domain.ErrWordNotFound
+	}
+
+	words, err := u.wordStateRepo.ListUserWordsBySpelling(ctx, userID, spelling)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(words) == 0 {
+		return nil, domain.ErrWordNotFound
+	}
+
+	for _, word := range words {
+		if err = u.attachHistory(ctx, userID, word); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return words, nil
+}
+
+// ListSetAside returns up to domain.WordSetAsideLimit hidden and paused words
+// of the user, which no longer show up on review.
+func (u *Usecase) ListSetAside(ctx context.Context, userID int64) ([]*domain.UserWord, error) {
+	const op = "ListSetAside"
+
+	words, err := u.wordStateRepo.ListUserWordsByStatus(
+		ctx,
+		userID,
+		[]domain.UserWordStatus{domain.UserWordStatusBlocked, domain.UserWordStatusSuspended},
+		domain.WordSetAsideLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return words, nil
+}
+
+// Get returns the word with its latest grades.
+func (u *Usecase) Get(ctx context.Context, userID int64, dictWordID string) (*domain.UserWord, error) {
+	const op = "Get"
+
+	word, err := u.wordStateRepo.GetUserWord(ctx, userID, dictWordID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if word == nil {
+		return nil, domain.ErrWordNotFound
+	}
+	if err = u.attachHistory(ctx, userID, word); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return word, nil
+}
+
+// Apply applies the action to the word and returns the word as it is now.
+// arg is the number of days or months for the actions that take one.
+func (u *Usecase) Apply(
+	ctx context.Context,
+	userID int64,
+	dictWordID string,
+	action domain.WordAction,
+	arg int,
+) (*domain.UserWord, error) {
+	const op = "Apply"
+
+	if err := validateAction(action, arg); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	var applied bool
+	var err error
+	switch action {
+	case domain.WordActionUnblock:
+		applied, err = u.wordStateRepo.UnblockWord(ctx, userID, dictWordID)
+	case domain.WordActionReset:
+		applied, err = u.wordStateRepo.ResetWord(ctx, userID, dictWordID)
+	case domain.WordActionSuspend:
+		applied, err = u.wordStateRepo.SuspendWord(ctx, userID, dictWordID, now.AddDate(0, 0, arg))
+	case domain.WordActionResume:
+		applied, err = u.wordStateRepo.RescheduleWord(ctx, userID, dictWordID, now)
+	case domain.WordActionTomorrow:
+		applied, err = u.rescheduleTomorrow(ctx, userID, dictWordID, now)
+	case domain.WordActionKnown:
+		days := int(now.AddDate(0, arg, 0).Sub(now).Hours() / 24)
+		applied, err = u.wordStateRepo.MarkWordKnown(ctx, userID, dictWordID, days, now)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	word, err := u.Get(ctx, userID, dictWordID)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return word, domain.ErrWordActionNotApplicable
+	}
+
+	u.logger.Debug().
+		Int64("user_id", userID).
+		Str("dict_word_id", dictWordID).
+		Str("action", string(action)).
+		Int("arg", arg).
+		Msg("word action applied")
+
+	return word, nil
+}
+
+// rescheduleTomorrow makes the word due at the start of the user's next day,
+// in the user's time zone.
+func (u *Usecase) rescheduleTomorrow(ctx context.Context, userID int64, dictWordID string, now time.Time) (bool, error) {
+	settings, err := u.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return u.wordStateRepo.RescheduleWord(ctx, userID, dictWordID, settings.DayStart(now).AddDate(0, 0, 1))
+}
+
+func (u *Usecase) attachHistory(ctx context.Context, userID int64, word *domain.UserWord) error {
+	if len(word.Cards) == 0 {
+		return nil
+	}
+
+	history, err := u.wordStateRepo.ListWordReviewHistory(ctx, userID, word.ID, domain.WordHistoryLimit)
+	if err != nil {
+		return err
+	}
+	word.AttachHistory(history)
+
+	return nil
+}
+
+func validateAction(action domain.WordAction, arg int) error {
+	switch action {
+	case domain.WordActionSuspend:
+		if !slices.Contains(domain.WordSuspendDays, arg) {
+			return domain.ErrInvalidWordAction
+		}
+	case domain.WordActionKnown:
+		if !slices.Contains(domain.WordKnownMonths, arg) {
+			return domain.ErrInvalidWordAction
+		}
+	default:
+		if _, ok := domain.ParseWordAction(string(action)); !ok || arg != 0 {
+			return domain.ErrInvalidWordAction
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package words
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/krezefal/eng-tg-bot/internal/domain"
+)
+
+func TestValidateAction(t *testing.T) {
+	tests := []struct {
+		action domain.WordAction
+		arg    int
+		ok     bool
+	}{
+		{domain.WordActionUnblock, 0, true},
+		{domain.WordActionReset, 0, true},
+		{domain.WordActionResume, 0, true},
+		{domain.WordActionTomorrow, 0, true},
+		{domain.WordActionReset, 1, false},
+		{domain.WordActionSuspend, 7, true},
+		{domain.WordActionSuspend, 90, true},
+		{domain.WordActionSuspend, 0, false},
+		{domain.WordActionSuspend, 8, false},
+		{domain.WordActionKnown, 1, true},
+		{domain.WordActionKnown, 12, true},
+		{domain.WordActionKnown, 24, false},
+		{domain.WordAction("delete"), 0, false},
+		{domain.WordAction(""), 0, false},
+	}
+
+	for _, tt := range tests {
+		err := validateAction(tt.action, tt.arg)
+		if tt.ok && err != nil {
+			t.Errorf("validateAction(%q, %d) = %v, want nil", tt.action, tt.arg, err)
+		}
+		if !tt.ok && !errors.Is(err, domain.ErrInvalidWordAction) {
+			t.Errorf("validateAction(%q, %d) = %v, want %v", tt.action, tt.arg, err, domain.ErrInvalidWordAction)
+		}
+	}
+}
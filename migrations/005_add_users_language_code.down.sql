@@ -0,0 +1,9 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS language_code;
+
+COMMIT;
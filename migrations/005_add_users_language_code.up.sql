@@ -0,0 +1,9 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS language_code VARCHAR(8) NULL;
+
+COMMIT;
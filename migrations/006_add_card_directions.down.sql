@@ -0,0 +1,33 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+ALTER TABLE user_dictionaries
+    DROP COLUMN IF EXISTS card_directions;
+
+DELETE FROM user_words_state
+WHERE direction <> 'forward';
+
+ALTER TABLE user_words_state
+    DROP CONSTRAINT IF EXISTS user_words_state_pkey;
+
+ALTER TABLE user_words_state
+    ADD CONSTRAINT user_words_state_pkey
+        PRIMARY KEY (user_id, dict_word_id);
+
+ALTER TABLE user_words_state
+    DROP COLUMN IF EXISTS direction;
+
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'card_directions_mode') THEN
+DROP TYPE card_directions_mode;
+END IF;
+
+  IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'card_direction') THEN
+DROP TYPE card_direction;
+END IF;
+END$$;
+
+COMMIT;
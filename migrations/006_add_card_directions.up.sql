@@ -0,0 +1,39 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'card_direction') THEN
+CREATE TYPE card_direction AS ENUM ('forward', 'reverse');
+END IF;
+
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'card_directions_mode') THEN
+CREATE TYPE card_directions_mode AS ENUM ('forward', 'reverse', 'both');
+END IF;
+END$$;
+
+-- каждое направление карточки (EN->RU, RU->EN) имеет свое состояние SM-2
+ALTER TABLE user_words_state
+    ADD COLUMN IF NOT EXISTS direction card_direction NOT NULL DEFAULT 'forward';
+
+ALTER TABLE user_words_state
+    DROP CONSTRAINT IF EXISTS user_words_state_pkey;
+
+ALTER TABLE user_words_state
+    ADD CONSTRAINT user_words_state_pkey
+        PRIMARY KEY (user_id, dict_word_id, direction);
+
+-- обратные карточки для уже отслеживаемых слов
+INSERT INTO user_words_state (user_id, dict_word_id, status, direction)
+SELECT user_id, dict_word_id, status, 'reverse'
+FROM user_words_state
+WHERE direction = 'forward'
+ON CONFLICT (user_id, dict_word_id, direction) DO NOTHING;
+
+-- направления карточек, выбранные пользователем для словаря
+ALTER TABLE user_dictionaries
+    ADD COLUMN IF NOT EXISTS card_directions card_directions_mode NOT NULL DEFAULT 'forward';
+
+COMMIT;
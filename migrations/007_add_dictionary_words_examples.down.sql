@@ -0,0 +1,9 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+ALTER TABLE dictionary_words
+    DROP COLUMN IF EXISTS examples;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- примеры употребления слова, используются в карточках и cloze-режиме
+ALTER TABLE dictionary_words
+    ADD COLUMN IF NOT EXISTS examples TEXT[] NOT NULL DEFAULT '{}';
+
+COMMIT;
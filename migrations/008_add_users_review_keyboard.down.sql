@@ -0,0 +1,11 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS review_keyboard;
+
+DROP TYPE IF EXISTS review_keyboard;
+
+COMMIT;
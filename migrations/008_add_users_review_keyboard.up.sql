@@ -0,0 +1,19 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- Enums
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'review_keyboard') THEN
+CREATE TYPE review_keyboard AS ENUM ('reply', 'inline');
+END IF;
+END$$;
+
+-- как показывать карточки при повторении: reply-клавиатура и новое сообщение
+-- на каждую карточку или inline-клавиатура и редактирование одного сообщения
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS review_keyboard review_keyboard NOT NULL DEFAULT 'reply';
+
+COMMIT;
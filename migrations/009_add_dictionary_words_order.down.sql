@@ -0,0 +1,23 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP INDEX IF EXISTS idx_dictionary_words_dictionary_id_random_key;
+DROP INDEX IF EXISTS idx_dictionary_words_dictionary_id_topic_position;
+DROP INDEX IF EXISTS idx_dictionary_words_dictionary_id_frequency_rank;
+DROP INDEX IF EXISTS idx_dictionary_words_dictionary_id_position;
+
+ALTER TABLE dictionary_words
+    DROP COLUMN IF EXISTS random_key,
+    DROP COLUMN IF EXISTS topic_position,
+    DROP COLUMN IF EXISTS topic,
+    DROP COLUMN IF EXISTS frequency_rank,
+    DROP COLUMN IF EXISTS position;
+
+ALTER TABLE dictionaries
+    DROP COLUMN IF EXISTS word_order;
+
+DROP TYPE IF EXISTS word_order;
+
+COMMIT;
@@ -0,0 +1,53 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- Enums
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'word_order') THEN
+CREATE TYPE word_order AS ENUM ('author', 'frequency', 'random', 'topic');
+END IF;
+END$$;
+
+-- порядок, в котором новые слова словаря показываются при обучении
+ALTER TABLE dictionaries
+    ADD COLUMN IF NOT EXISTS word_order word_order NOT NULL DEFAULT 'random';
+
+-- position         - порядок слов, заданный автором словаря
+-- frequency_rank   - ранг по частотности (1 - самое частое), NULL - неизвестен
+-- topic            - тема слова для чередования тем
+-- topic_position   - порядковый номер слова внутри своей темы
+-- random_key       - случайный ключ: выбор случайного слова по индексу
+--                    вместо ORDER BY random() по всему словарю
+ALTER TABLE dictionary_words
+    ADD COLUMN IF NOT EXISTS position       INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS frequency_rank INT NULL CHECK (frequency_rank > 0),
+    ADD COLUMN IF NOT EXISTS topic          VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS topic_position INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS random_key     DOUBLE PRECISION NOT NULL DEFAULT random();
+
+-- у уже загруженных слов авторского порядка нет, берем алфавитный
+UPDATE dictionary_words dw
+SET position = o.position,
+    topic_position = o.position
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY dictionary_id ORDER BY spelling) AS position
+    FROM dictionary_words
+) o
+WHERE o.id = dw.id;
+
+CREATE INDEX IF NOT EXISTS idx_dictionary_words_dictionary_id_position
+    ON dictionary_words(dictionary_id, position);
+
+CREATE INDEX IF NOT EXISTS idx_dictionary_words_dictionary_id_frequency_rank
+    ON dictionary_words(dictionary_id, frequency_rank, position);
+
+CREATE INDEX IF NOT EXISTS idx_dictionary_words_dictionary_id_topic_position
+    ON dictionary_words(dictionary_id, topic_position, topic);
+
+CREATE INDEX IF NOT EXISTS idx_dictionary_words_dictionary_id_random_key
+    ON dictionary_words(dictionary_id, random_key);
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP INDEX IF EXISTS idx_dictionary_words_lower_spelling;
+
+-- значение из enum удалить нельзя, поэтому тип пересоздается
+UPDATE user_words_state
+SET status = 'learning'
+WHERE status = 'suspended';
+
+ALTER TABLE user_words_state
+    ALTER COLUMN status DROP DEFAULT;
+
+ALTER TYPE user_word_status RENAME TO user_word_status_old;
+
+CREATE TYPE user_word_status AS ENUM ('learning', 'blocked');
+
+ALTER TABLE user_words_state
+    ALTER COLUMN status TYPE user_word_status USING status::text::user_word_status;
+
+ALTER TABLE user_words_state
+    ALTER COLUMN status SET DEFAULT 'learning';
+
+DROP TYPE user_word_status_old;
+
+COMMIT;
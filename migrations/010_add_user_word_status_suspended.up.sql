@@ -0,0 +1,15 @@
+-- =========================
+-- UP migration
+-- =========================
+-- ALTER TYPE ... ADD VALUE нельзя использовать в той же транзакции, где
+-- значение добавлено, поэтому миграция только добавляет его.
+BEGIN;
+
+-- слово приостановлено до next_review_at и не показывается при повторении
+ALTER TYPE user_word_status ADD VALUE IF NOT EXISTS 'suspended';
+
+-- поиск слова по написанию для /word
+CREATE INDEX IF NOT EXISTS idx_dictionary_words_lower_spelling
+    ON dictionary_words(lower(spelling));
+
+COMMIT;
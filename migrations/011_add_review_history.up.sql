@@ -0,0 +1,28 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- история оценок карточек: по строке на каждую оценку при повторении
+CREATE TABLE IF NOT EXISTS review_history (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT         NOT NULL,
+    dict_word_id  UUID           NOT NULL,
+    direction     card_direction NOT NULL,
+
+    grade         INT  NOT NULL CHECK (grade BETWEEN 0 AND 5),
+    -- состояние SM-2 после оценки
+    ef            REAL NOT NULL,
+    interval_days INT  NOT NULL CHECK (interval_days >= 0),
+
+    reviewed_at   TIMESTAMPTZ NOT NULL,
+
+    -- история удаляется вместе с прогрессом (например, при отписке)
+    FOREIGN KEY (user_id, dict_word_id, direction)
+        REFERENCES user_words_state(user_id, dict_word_id, direction) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_history_user_word
+    ON review_history(user_id, dict_word_id, direction, reviewed_at);
+
+COMMIT;
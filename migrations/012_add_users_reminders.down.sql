@@ -0,0 +1,16 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP INDEX IF EXISTS idx_user_words_state_learning_next_review;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS last_reminded_at,
+    DROP COLUMN IF EXISTS quiet_hours_end,
+    DROP COLUMN IF EXISTS quiet_hours_start,
+    DROP COLUMN IF EXISTS reminder_time,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS reminders_enabled;
+
+COMMIT;
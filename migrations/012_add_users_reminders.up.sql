@@ -0,0 +1,25 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- напоминания о словах, которые пора повторить
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS reminders_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- часовой пояс пользователя (имя из базы IANA)
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC',
+    -- время напоминания и тихие часы — минуты от полуночи по местному времени
+    ADD COLUMN IF NOT EXISTS reminder_time INT NOT NULL DEFAULT 600
+        CHECK (reminder_time BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS quiet_hours_start INT NULL DEFAULT 1320
+        CHECK (quiet_hours_start BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS quiet_hours_end INT NULL DEFAULT 480
+        CHECK (quiet_hours_end BETWEEN 0 AND 1439),
+    ADD COLUMN IF NOT EXISTS last_reminded_at TIMESTAMPTZ NULL;
+
+-- выбор кандидатов на напоминание: due-слова пользователя
+CREATE INDEX IF NOT EXISTS idx_user_words_state_learning_next_review
+    ON user_words_state(user_id, next_review_at)
+    WHERE status = 'learning';
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP INDEX IF EXISTS idx_review_history_user_reviewed_at;
+DROP INDEX IF EXISTS idx_user_words_state_user_added_at;
+
+ALTER TABLE user_words_state
+    DROP COLUMN IF EXISTS added_at;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS scheduler,
+    DROP COLUMN IF EXISTS card_directions,
+    DROP COLUMN IF EXISTS review_mode,
+    DROP COLUMN IF EXISTS reviews_per_day,
+    DROP COLUMN IF EXISTS new_words_per_day;
+
+DROP TYPE IF EXISTS scheduler;
+DROP TYPE IF EXISTS review_mode;
+
+COMMIT;
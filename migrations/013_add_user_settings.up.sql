@@ -0,0 +1,46 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- Enums
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'review_mode') THEN
+CREATE TYPE review_mode AS ENUM ('flashcard', 'typed', 'quiz', 'cloze', 'listening');
+END IF;
+
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'scheduler') THEN
+CREATE TYPE scheduler AS ENUM ('sm2', 'sm2_lenient');
+END IF;
+END$$;
+
+-- настройки пользователя (часовой пояс, язык и напоминания уже хранятся в users)
+ALTER TABLE users
+    -- лимит новых слов и оценок в подходах по due-словам за местные сутки
+    ADD COLUMN IF NOT EXISTS new_words_per_day INT NOT NULL DEFAULT 20
+        CHECK (new_words_per_day > 0),
+    ADD COLUMN IF NOT EXISTS reviews_per_day INT NOT NULL DEFAULT 200
+        CHECK (reviews_per_day > 0),
+    -- режим подхода, запускаемого кнопкой `Старт`
+    ADD COLUMN IF NOT EXISTS review_mode review_mode NOT NULL DEFAULT 'flashcard',
+    -- направления карточек для новых подписок
+    ADD COLUMN IF NOT EXISTS card_directions card_directions_mode NOT NULL DEFAULT 'forward',
+    ADD COLUMN IF NOT EXISTS scheduler scheduler NOT NULL DEFAULT 'sm2';
+
+-- когда слово начали учить, для дневного лимита новых слов; у старых слов
+-- время неизвестно
+ALTER TABLE user_words_state
+    ADD COLUMN IF NOT EXISTS added_at TIMESTAMPTZ NULL;
+
+ALTER TABLE user_words_state
+    ALTER COLUMN added_at SET DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS idx_user_words_state_user_added_at
+    ON user_words_state(user_id, added_at);
+
+-- дневной лимит оценок и статистика по дням
+CREATE INDEX IF NOT EXISTS idx_review_history_user_reviewed_at
+    ON review_history(user_id, reviewed_at);
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP TABLE IF EXISTS streak_frozen_days;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS streak_settled_through,
+    DROP COLUMN IF EXISTS streak_freezes,
+    DROP COLUMN IF EXISTS streak_freeze_enabled,
+    DROP COLUMN IF EXISTS daily_goal,
+    DROP COLUMN IF EXISTS daily_goal_kind;
+
+DROP TYPE IF EXISTS daily_goal_kind;
+
+COMMIT;
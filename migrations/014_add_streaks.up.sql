@@ -0,0 +1,35 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- Enums
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'daily_goal_kind') THEN
+CREATE TYPE daily_goal_kind AS ENUM ('reviews', 'new_words');
+END IF;
+END$$;
+
+ALTER TABLE users
+    -- дневная цель: сколько оценок или новых слов нужно за местные сутки,
+    -- чтобы день засчитался в серию
+    ADD COLUMN IF NOT EXISTS daily_goal_kind daily_goal_kind NOT NULL DEFAULT 'reviews',
+    ADD COLUMN IF NOT EXISTS daily_goal INT NOT NULL DEFAULT 20
+        CHECK (daily_goal > 0),
+    -- тратить ли заморозки на пропущенные дни
+    ADD COLUMN IF NOT EXISTS streak_freeze_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- заработанные и еще не потраченные заморозки серии
+    ADD COLUMN IF NOT EXISTS streak_freezes INT NOT NULL DEFAULT 0
+        CHECK (streak_freezes >= 0),
+    -- последний местный день, за который начислены и потрачены заморозки
+    ADD COLUMN IF NOT EXISTS streak_settled_through DATE NULL;
+
+-- дни, пропуск которых покрыт заморозкой
+CREATE TABLE IF NOT EXISTS streak_frozen_days (
+    user_id BIGINT NOT NULL REFERENCES users(tg_id) ON DELETE CASCADE,
+    day     DATE NOT NULL,
+    PRIMARY KEY (user_id, day)
+);
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP INDEX IF EXISTS idx_users_digest_enabled;
+DROP TABLE IF EXISTS digest_deliveries;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS digest_enabled;
+
+COMMIT;
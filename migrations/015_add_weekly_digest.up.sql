@@ -0,0 +1,26 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- еженедельная сводка отправляется только тем, кто ее включил
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS digest_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- сводки по неделям: строка вставляется до отправки, поэтому повторный проход
+-- (в том числе после рестарта посреди рассылки) не отправит сводку еще раз.
+-- sent_at пуст, если отправка не удалась или сводка была пустой
+CREATE TABLE IF NOT EXISTS digest_deliveries (
+    user_id    BIGINT      NOT NULL REFERENCES users(tg_id) ON DELETE CASCADE,
+    -- местная дата первого дня недели, за которую сводка
+    week_start DATE        NOT NULL,
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at    TIMESTAMPTZ NULL,
+    PRIMARY KEY (user_id, week_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_digest_enabled
+    ON users(tg_id)
+    WHERE digest_enabled;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP INDEX IF EXISTS idx_notifications_pending;
+DROP INDEX IF EXISTS uq_notifications_user_batch;
+DROP TABLE IF EXISTS notifications;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS notifications_enabled;
+
+DROP TYPE IF EXISTS notification_kind;
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'notification_kind') THEN
+CREATE TYPE notification_kind AS ENUM ('new_words', 'batch_unlocked');
+END IF;
+END$$;
+
+-- уведомления о новых словах в словарях, на которые подписан пользователь
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS notifications_enabled BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- outbox уведомлений: строки добавляются в той же транзакции, что и слова
+-- (seeder), или задачей, которая находит открывшиеся партии; отправляет их
+-- отдельная задача бота с повторами
+CREATE TABLE IF NOT EXISTS notifications (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT            NOT NULL REFERENCES users(tg_id) ON DELETE CASCADE,
+    kind            notification_kind NOT NULL,
+    dictionary_id   UUID              NOT NULL REFERENCES dictionaries(id) ON DELETE CASCADE,
+    -- партия on_schedule-словаря для batch_unlocked
+    batch_id        UUID              NULL REFERENCES dictionary_schedule_batch(id) ON DELETE CASCADE,
+    -- сколько слов добавилось или открылось
+    words           INT               NOT NULL CHECK (words > 0),
+
+    -- неудачные попытки отправки и время следующей
+    attempts        INT               NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    next_attempt_at TIMESTAMPTZ       NOT NULL DEFAULT now(),
+    last_error      TEXT              NOT NULL DEFAULT '',
+
+    created_at      TIMESTAMPTZ       NOT NULL DEFAULT now(),
+    sent_at         TIMESTAMPTZ       NULL
+);
+
+-- об открытии партии пользователь узнает один раз
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications_user_batch
+    ON notifications(user_id, batch_id)
+    WHERE batch_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_pending
+    ON notifications(next_attempt_at)
+    WHERE sent_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP INDEX IF EXISTS idx_user_sessions_expires_at;
+DROP TABLE IF EXISTS user_sessions;
+
+DROP TYPE IF EXISTS session_kind;
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'session_kind') THEN
+CREATE TYPE session_kind AS ENUM ('review', 'learning', 'learn_batch');
+END IF;
+END$$;
+
+-- состояние подходов повторения и изучения слов, чтобы они переживали
+-- рестарт бота; data — JSON, который пишет и читает сам юзкейс
+CREATE TABLE IF NOT EXISTS user_sessions (
+    user_id    BIGINT       NOT NULL REFERENCES users(tg_id) ON DELETE CASCADE,
+    kind       session_kind NOT NULL,
+    data       JSONB        NOT NULL,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    -- сессия продлевается при каждом сохранении, просроченные удаляет бот
+    expires_at TIMESTAMPTZ  NOT NULL,
+
+    PRIMARY KEY (user_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_expires_at
+    ON user_sessions(expires_at);
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP INDEX IF EXISTS idx_processed_updates_processed_at;
+DROP TABLE IF EXISTS processed_updates;
+
+ALTER TABLE user_words_state
+    DROP COLUMN IF EXISTS version;
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- версия состояния карточки: растет при каждом изменении, оценка применяется
+-- только к той версии, из которой она посчитана (compare-and-set)
+ALTER TABLE user_words_state
+    ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 0;
+
+-- уже обработанные апдейты Telegram: повторно доставленный апдейт
+-- пропускается; записи старше суток удаляет бот
+CREATE TABLE IF NOT EXISTS processed_updates (
+    update_id    BIGINT      PRIMARY KEY,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_updates_processed_at
+    ON processed_updates(processed_at);
+
+COMMIT;
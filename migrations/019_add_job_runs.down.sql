@@ -0,0 +1,8 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+DROP TABLE IF EXISTS job_runs;
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- последний успешный запуск фоновой задачи: экземпляр, которому досталась
+-- задача, не запускает ее, если другой экземпляр уже сделал это меньше
+-- интервала назад
+CREATE TABLE IF NOT EXISTS job_runs (
+    name        TEXT        PRIMARY KEY,
+    last_run_at TIMESTAMPTZ NOT NULL
+);
+
+COMMIT;
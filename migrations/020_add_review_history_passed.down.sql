@@ -0,0 +1,9 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+ALTER TABLE review_history
+    DROP COLUMN IF EXISTS passed;
+
+COMMIT;
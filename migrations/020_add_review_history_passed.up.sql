@@ -0,0 +1,26 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- прошла ли оценка по алгоритму, действовавшему в момент оценки: проходная
+-- оценка зависит от алгоритма (SM-2 — только `Помню!`, мягкий SM-2 — и
+-- `Легко`), поэтому точность и трудные слова считаются по этому признаку
+ALTER TABLE review_history
+    ADD COLUMN IF NOT EXISTS passed BOOLEAN NULL;
+
+-- алгоритм на момент старых оценок неизвестен, берется текущий
+UPDATE review_history rh
+SET passed = rh.grade >= CASE WHEN u.scheduler = 'sm2_lenient' THEN 2 ELSE 3 END
+FROM users u
+WHERE u.tg_id = rh.user_id
+    AND rh.passed IS NULL;
+
+UPDATE review_history
+SET passed = grade >= 3
+WHERE passed IS NULL;
+
+ALTER TABLE review_history
+    ALTER COLUMN passed SET NOT NULL;
+
+COMMIT;
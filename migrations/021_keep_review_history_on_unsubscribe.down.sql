@@ -0,0 +1,25 @@
+-- =========================
+-- DOWN migration
+-- =========================
+BEGIN;
+
+ALTER TABLE review_history
+    DROP CONSTRAINT IF EXISTS review_history_dict_word_id_fkey,
+    DROP CONSTRAINT IF EXISTS review_history_user_id_fkey;
+
+-- история слов, прогресс по которым уже удален, не может ссылаться на него
+DELETE FROM review_history rh
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM user_words_state uws
+    WHERE uws.user_id = rh.user_id
+        AND uws.dict_word_id = rh.dict_word_id
+        AND uws.direction = rh.direction
+);
+
+ALTER TABLE review_history
+    ADD CONSTRAINT review_history_user_id_dict_word_id_direction_fkey
+        FOREIGN KEY (user_id, dict_word_id, direction)
+        REFERENCES user_words_state(user_id, dict_word_id, direction) ON DELETE CASCADE;
+
+COMMIT;
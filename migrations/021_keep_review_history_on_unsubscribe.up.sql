@@ -0,0 +1,18 @@
+-- =========================
+-- UP migration
+-- =========================
+BEGIN;
+
+-- история оценок больше не удаляется вместе с прогрессом: после отписки от
+-- словаря серии, календарь активности и статистика за прошлые дни остаются.
+-- История удаляется вместе с пользователем или со словом словаря
+ALTER TABLE review_history
+    DROP CONSTRAINT IF EXISTS review_history_user_id_dict_word_id_direction_fkey;
+
+ALTER TABLE review_history
+    ADD CONSTRAINT review_history_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES users(tg_id) ON DELETE CASCADE,
+    ADD CONSTRAINT review_history_dict_word_id_fkey
+        FOREIGN KEY (dict_word_id) REFERENCES dictionary_words(id) ON DELETE CASCADE;
+
+COMMIT;